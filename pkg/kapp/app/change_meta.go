@@ -7,6 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 type ChangeMeta struct {
@@ -17,6 +20,45 @@ type ChangeMeta struct {
 	Description string `json:"description,omitempty"`
 
 	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Resources references (not content) of resources
+	// that were part of the app at the time of change
+	Resources []ChangeResource `json:"resources,omitempty"`
+}
+
+// ChangeResource identifies a resource recorded in an app change
+type ChangeResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// NewChangeResources returns references to given resources
+func NewChangeResources(resources []ctlres.Resource) []ChangeResource {
+	var result []ChangeResource
+	for _, res := range resources {
+		result = append(result, ChangeResource{
+			APIVersion: res.APIVersion(),
+			Kind:       res.Kind(),
+			Namespace:  res.Namespace(),
+			Name:       res.Name(),
+		})
+	}
+	return result
+}
+
+// AsResource returns resource that only has identifying fields set
+func (r ChangeResource) AsResource() ctlres.Resource {
+	metadata := map[string]interface{}{"name": r.Name}
+	if len(r.Namespace) > 0 {
+		metadata["namespace"] = r.Namespace
+	}
+	return ctlres.NewResourceUnstructured(unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": r.APIVersion,
+		"kind":       r.Kind,
+		"metadata":   metadata,
+	}}, ctlres.ResourceType{})
 }
 
 func NewChangeMetaFromString(data string) ChangeMeta {
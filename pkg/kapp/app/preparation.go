@@ -14,6 +14,8 @@ import (
 
 const (
 	nonceAnnKey = "kapp.k14s.io/nonce"
+
+	DeployIDAnnKeyDefault = "kapp.k14s.io/deploy-id"
 )
 
 type Preparation struct {
@@ -32,6 +34,9 @@ type PrepareResourcesOpts struct {
 	IntoNamespace    string   // this ns is allowed automatically
 	MapNamespaces    []string // this ns is allowed automatically
 	DefaultNamespace string   // this ns is allowed automatically
+
+	DeployID       string // added to all resources when non-empty
+	DeployIDAnnKey string
 }
 
 func NewPreparation(resourceTypes ctlres.ResourceTypes, opts PrepareResourcesOpts) Preparation {
@@ -61,6 +66,11 @@ func (a Preparation) PrepareResources(resources []ctlres.Resource) ([]ctlres.Res
 		return nil, err
 	}
 
+	resources, err = a.addDeployID(resources)
+	if err != nil {
+		return nil, err
+	}
+
 	return resources, nil
 }
 
@@ -135,6 +145,31 @@ func (a Preparation) addNonce(resources []ctlres.Resource) ([]ctlres.Resource, e
 	return resources, nil
 }
 
+func (a Preparation) addDeployID(resources []ctlres.Resource) ([]ctlres.Resource, error) {
+	if len(a.opts.DeployID) == 0 {
+		return resources, nil
+	}
+
+	annKey := a.opts.DeployIDAnnKey
+	if len(annKey) == 0 {
+		annKey = DeployIDAnnKeyDefault
+	}
+
+	addDeployIDMod := ctlres.StringMapAppendMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations"}),
+		KVs:             map[string]string{annKey: a.opts.DeployID},
+	}
+
+	for _, res := range resources {
+		err := addDeployIDMod.Apply(res)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
 func (a Preparation) validateBasicInfo(resources []ctlres.Resource) error {
 	var errs []error
 
@@ -21,6 +21,9 @@ const (
 	isChangeLabelValue   = ""
 	legacyChangeLabelKey = "kapp.k14s.io/app-change-app"       // holds app name
 	changeLabelKey       = "kapp.k14s.io/app-change-app-label" // holds app label
+
+	// ConfigMaps are limited to 1MiB, leave room for metadata
+	changeMetaMaxSize = 512 * 1024
 )
 
 type RecordedAppChanges struct {
@@ -117,6 +120,13 @@ func (a RecordedAppChanges) Begin(meta ChangeMeta, appChangesMaxToKeep int) (*Ch
 		StartedAt:   time.Now().UTC(),
 		Description: meta.Description,
 		Namespaces:  meta.Namespaces,
+		Resources:   meta.Resources,
+	}
+
+	// Recording resources is best effort so that
+	// large apps can still record app changes
+	if len(newMeta.AsString()) > changeMetaMaxSize {
+		newMeta.Resources = nil
 	}
 
 	configMap := &corev1.ConfigMap{
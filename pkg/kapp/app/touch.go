@@ -7,6 +7,7 @@ type Touch struct {
 	App              App
 	Description      string
 	Namespaces       []string
+	Resources        []ChangeResource
 	IgnoreSuccessErr bool
 
 	AppChangesMaxToKeep int
@@ -16,6 +17,7 @@ func (t Touch) Do(doFunc func() error) error {
 	meta := ChangeMeta{
 		Description: t.Description,
 		Namespaces:  t.Namespaces,
+		Resources:   t.Resources,
 	}
 
 	change, err := t.App.BeginChange(meta, t.AppChangesMaxToKeep)
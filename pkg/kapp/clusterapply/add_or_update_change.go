@@ -38,6 +38,9 @@ type AddOrUpdateChange struct {
 	changeSetFactory    ctldiff.ChangeSetFactory
 	opts                AddOrUpdateChangeOpts
 	diffMaskRules       []ctlconf.DiffMaskRule
+
+	// savedResFunc receives resource as returned by the server after save
+	savedResFunc func(ctlres.Resource)
 }
 
 func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
@@ -218,6 +221,8 @@ func (c AddOrUpdateChange) tryToUpdateAfterCreateConflict(allowNoopUpdates bool)
 }
 
 func (c AddOrUpdateChange) recordAppliedResource(savedRes ctlres.Resource) error {
+	c.notifySavedResource(savedRes)
+
 	savedResWithHistory := c.changeFactory.NewResourceWithHistory(savedRes)
 
 	// It may not be benefitial to record last applied conf
@@ -262,16 +267,24 @@ func (c AddOrUpdateChange) recordAppliedResource(savedRes ctlres.Resource) error
 			return true, nil
 		}
 
-		_, err = c.identifiedResources.Update(latestResWithHistoryUpdated)
+		updatedRes, err := c.identifiedResources.Update(latestResWithHistoryUpdated)
 		if err != nil {
 			latestResWithHistory = nil // Get again
 			return false, fmt.Errorf("Saving record of last applied resource: %w", err)
 		}
 
+		c.notifySavedResource(updatedRes)
+
 		return true, nil
 	})
 }
 
+func (c AddOrUpdateChange) notifySavedResource(savedRes ctlres.Resource) {
+	if c.savedResFunc != nil {
+		c.savedResFunc(savedRes)
+	}
+}
+
 type AddPlainStrategy struct {
 	newRes ctlres.Resource
 	aou    AddOrUpdateChange
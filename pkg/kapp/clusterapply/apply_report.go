@@ -0,0 +1,162 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// ApplyReportStatus is an outcome of applying or waiting on a change
+type ApplyReportStatus string
+
+const (
+	ApplyReportStatusSucceeded ApplyReportStatus = "succeeded"
+	ApplyReportStatusFailed    ApplyReportStatus = "failed"
+	// ApplyReportStatusPending indicates that change was not
+	// attempted or completed (e.g. due to earlier failure)
+	ApplyReportStatusPending ApplyReportStatus = "pending"
+	// ApplyReportStatusSkipped indicates that waiting was not needed
+	ApplyReportStatusSkipped ApplyReportStatus = "skipped"
+)
+
+// ApplyReportEntry is a structured representation of an outcome
+// of a single change meant to be consumed by external tools (e.g. CI)
+type ApplyReportEntry struct {
+	Op              ClusterChangeApplyOp `json:"op"`
+	WaitOp          ClusterChangeWaitOp  `json:"waitOp"`
+	GVK             ChangeGVKJSON        `json:"gvk"`
+	Namespace       string               `json:"namespace"`
+	Name            string               `json:"name"`
+	Status          ApplyReportStatus    `json:"status"`
+	ApplyError      string               `json:"applyError,omitempty"`
+	WaitOutcome     ApplyReportStatus    `json:"waitOutcome"`
+	WaitMessage     string               `json:"waitMessage,omitempty"`
+	DurationSeconds float64              `json:"durationSeconds"`
+
+	startedAt time.Time
+}
+
+// ApplyReport collects outcomes of applying and waiting on changes.
+// It is safe for concurrent use; nil ApplyReport does not record anything.
+type ApplyReport struct {
+	entries map[*ClusterChange]*ApplyReportEntry
+	order   []*ClusterChange
+	mu      sync.Mutex
+}
+
+func NewApplyReport() *ApplyReport {
+	return &ApplyReport{entries: map[*ClusterChange]*ApplyReportEntry{}}
+}
+
+// Add records changes as pending so that changes
+// that were never attempted are included in the report
+func (r *ApplyReport) Add(changes []*ClusterChange) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, change := range changes {
+		r.entry(change)
+	}
+}
+
+func (r *ApplyReport) applyStarted(change *ClusterChange) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(change)
+	if entry.startedAt.IsZero() {
+		entry.startedAt = time.Now()
+	}
+}
+
+func (r *ApplyReport) applied(change *ClusterChange, err error) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(change)
+	if err != nil {
+		entry.Status = ApplyReportStatusFailed
+		entry.ApplyError = err.Error()
+		entry.finish()
+		return
+	}
+	if entry.WaitOp == ClusterChangeWaitOpNoop {
+		entry.WaitOutcome = ApplyReportStatusSkipped
+	}
+}
+
+func (r *ApplyReport) waited(change *ClusterChange, status ApplyReportStatus, msg string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(change)
+	if entry.WaitOutcome != ApplyReportStatusSkipped {
+		entry.WaitOutcome = status
+	}
+	entry.WaitMessage = msg
+	entry.Status = status
+	entry.finish()
+}
+
+// AsJSON serializes report entries in order changes were recorded
+func (r *ApplyReport) AsJSON() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []ApplyReportEntry{}
+	for _, change := range r.order {
+		result = append(result, *r.entries[change])
+	}
+
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// entry returns existing or new entry for a change. Callers must hold r.mu.
+func (r *ApplyReport) entry(change *ClusterChange) *ApplyReportEntry {
+	if entry, found := r.entries[change]; found {
+		return entry
+	}
+
+	res := change.Resource()
+	gvk := res.GroupVersion().WithKind(res.Kind())
+
+	entry := &ApplyReportEntry{
+		Op:          change.ApplyOp(),
+		WaitOp:      change.WaitOp(),
+		GVK:         ChangeGVKJSON{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+		Namespace:   res.Namespace(),
+		Name:        res.Name(),
+		Status:      ApplyReportStatusPending,
+		WaitOutcome: ApplyReportStatusPending,
+	}
+
+	r.entries[change] = entry
+	r.order = append(r.order, change)
+
+	return entry
+}
+
+func (e *ApplyReportEntry) finish() {
+	if !e.startedAt.IsZero() {
+		e.DurationSeconds = time.Since(e.startedAt).Seconds()
+	}
+}
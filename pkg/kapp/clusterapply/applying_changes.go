@@ -24,10 +24,11 @@ type ApplyingChanges struct {
 	clusterChangeFactory ClusterChangeFactory
 	ui                   UI
 	exitOnError          bool
+	report               *ApplyReport
 }
 
-func NewApplyingChanges(numTotal int, opts ApplyingChangesOpts, clusterChangeFactory ClusterChangeFactory, ui UI, exitOnError bool) *ApplyingChanges {
-	return &ApplyingChanges{numTotal, opts, map[*ctldgraph.Change]struct{}{}, clusterChangeFactory, ui, exitOnError}
+func NewApplyingChanges(numTotal int, opts ApplyingChangesOpts, clusterChangeFactory ClusterChangeFactory, ui UI, exitOnError bool, report *ApplyReport) *ApplyingChanges {
+	return &ApplyingChanges{numTotal, opts, map[*ctldgraph.Change]struct{}{}, clusterChangeFactory, ui, exitOnError, report}
 }
 
 type applyResult struct {
@@ -69,6 +70,7 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 				defer applyThrottle.Done()
 
 				clusterChange := change.Change.(wrappedClusterChange).ClusterChange
+				c.report.applyStarted(clusterChange)
 				retryable, descMsgs, err := clusterChange.Apply()
 
 				applyCh <- applyResult{
@@ -92,6 +94,7 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 			if result.Err != nil {
 				lastErr = result.Err
 				if !result.Retryable {
+					c.report.applied(result.ClusterChange, result.Err)
 					if c.exitOnError {
 						return nil, nil, result.Err
 					}
@@ -101,6 +104,7 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 				continue
 			}
 
+			c.report.applied(result.ClusterChange, nil)
 			c.markApplied(result.Change)
 			appliedChanges = append(appliedChanges, WaitingChange{result.Change, result.ClusterChange, time.Now()})
 		}
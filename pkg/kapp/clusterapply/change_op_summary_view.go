@@ -0,0 +1,63 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"sort"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+var changeOpSummaryOrder = []ClusterChangeApplyOp{
+	ClusterChangeApplyOpAdd,
+	ClusterChangeApplyOpUpdate,
+	ClusterChangeApplyOpDelete,
+	ClusterChangeApplyOpExists,
+	ClusterChangeApplyOpNoop,
+}
+
+// ChangeOpSummaryView shows changes within a graph
+// grouped by their apply operation (create, update, etc.)
+type ChangeOpSummaryView struct {
+	changesGraph *ctldgraph.ChangeGraph
+}
+
+func NewChangeOpSummaryView(changesGraph *ctldgraph.ChangeGraph) ChangeOpSummaryView {
+	return ChangeOpSummaryView{changesGraph}
+}
+
+func (v ChangeOpSummaryView) Print(ui ui.UI) {
+	descsByOp := map[ClusterChangeApplyOp][]string{}
+
+	for _, change := range v.changesGraph.All() {
+		clusterChange := change.Change.(wrappedClusterChange).ClusterChange
+		op := clusterChange.ApplyOp()
+		descsByOp[op] = append(descsByOp[op], clusterChange.Resource().Description())
+	}
+
+	table := uitable.Table{
+		Title: "Summary",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Op"),
+			uitable.NewHeader("Count"),
+			uitable.NewHeader("Resources"),
+		},
+	}
+
+	for _, op := range changeOpSummaryOrder {
+		descs := descsByOp[op]
+		sort.Strings(descs)
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(applyOpCodeUI[op]),
+			uitable.NewValueInt(len(descs)),
+			uitable.NewValueStrings(descs),
+		})
+	}
+
+	ui.PrintTable(table)
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+// ChangeOrderView shows order in which changes within a graph
+// would be applied as well as reverse order used for deletion
+type ChangeOrderView struct {
+	changesGraph *ctldgraph.ChangeGraph
+}
+
+func NewChangeOrderView(changesGraph *ctldgraph.ChangeGraph) ChangeOrderView {
+	return ChangeOrderView{changesGraph}
+}
+
+func (v ChangeOrderView) Print(ui ui.UI) {
+	ui.PrintTable(v.table("Apply order", v.changesGraph.ApplyOrder()))
+	ui.PrintTable(v.table("Delete order", v.changesGraph.DeleteOrder()))
+}
+
+func (ChangeOrderView) table(title string, changes []*ctldgraph.Change) uitable.Table {
+	table := uitable.Table{
+		Title: title,
+
+		Header: []uitable.Header{
+			uitable.NewHeader("#"),
+			uitable.NewHeader("Op"),
+			uitable.NewHeader("Resource"),
+		},
+	}
+
+	for i, change := range changes {
+		clusterChange := change.Change.(wrappedClusterChange).ClusterChange
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueInt(i + 1),
+			uitable.NewValueString(applyOpCodeUI[clusterChange.ApplyOp()]),
+			uitable.NewValueString(clusterChange.Resource().Description()),
+		})
+	}
+
+	return table
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+)
+
+// ChangeJSON is a structured representation of a single resource
+// change meant to be consumed by external tools (e.g. policy engines)
+type ChangeJSON struct {
+	Op        ClusterChangeApplyOp  `json:"op"`
+	GVK       ChangeGVKJSON         `json:"gvk"`
+	Namespace string                `json:"namespace"`
+	Name      string                `json:"name"`
+	Diff      []ctldiff.FieldChange `json:"diff"`
+}
+
+type ChangeGVKJSON struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// NewChangeSetJSON serializes changes as a JSON array
+// with field level diff of each (masked) resource
+func NewChangeSetJSON(changeViews []ChangeView, maskRules []ctlconf.DiffMaskRule) ([]byte, error) {
+	result := []ChangeJSON{}
+
+	for _, view := range changeViews {
+		res := view.Resource()
+
+		fieldChanges, err := view.ConfigurableTextDiff().MaskedFieldChanges(maskRules)
+		if err != nil {
+			return nil, fmt.Errorf("Calculating diff for %s: %w", res.Description(), err)
+		}
+		if fieldChanges == nil {
+			fieldChanges = []ctldiff.FieldChange{}
+		}
+
+		gvk := res.GroupVersion().WithKind(res.Kind())
+
+		result = append(result, ChangeJSON{
+			Op:        view.ApplyOp(),
+			GVK:       ChangeGVKJSON{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+			Namespace: res.Namespace(),
+			Name:      res.Name(),
+			Diff:      fieldChanges,
+		})
+	}
+
+	return json.MarshalIndent(result, "", "  ")
+}
@@ -5,6 +5,7 @@ package clusterapply
 
 import (
 	"fmt"
+	"strings"
 
 	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	"carvel.dev/kapp/pkg/kapp/diff"
@@ -14,10 +15,19 @@ import (
 	"github.com/cppforlife/go-cli-ui/ui"
 )
 
+const (
+	// DiffFormatText shows changes using kapp's own rendering
+	DiffFormatText = "text"
+	// DiffFormatPatch shows changes in unified diff format
+	DiffFormatPatch = "patch"
+)
+
 type ChangeSetViewOpts struct {
 	Summary     bool
 	Changes     bool
 	ChangesYAML bool
+	Anonymize   bool
+	Format      string
 	ctldiff.TextDiffViewOpts
 }
 
@@ -27,41 +37,90 @@ type ChangeSetView struct {
 	opts        ChangeSetViewOpts
 
 	changesView *ChangesView
+	anonymizer  *ctldiff.Anonymizer
 }
 
 func NewChangeSetView(changeViews []ChangeView,
 	maskRules []ctlconf.DiffMaskRule, opts ChangeSetViewOpts) *ChangeSetView {
 
-	return &ChangeSetView{changeViews, maskRules, opts, nil}
+	view := &ChangeSetView{changeViews: changeViews, maskRules: maskRules, opts: opts}
+
+	if opts.Anonymize {
+		var resources []ctlres.Resource
+		for _, changeView := range changeViews {
+			resources = append(resources, changeView.Resource())
+		}
+		view.anonymizer = ctldiff.NewAnonymizer(resources)
+	}
+
+	return view
 }
 
 func (v *ChangeSetView) Print(ui ui.UI) {
 	if v.opts.ChangesYAML {
 		v.printChangesYAML(ui)
 	}
-	if v.opts.Changes {
+	if v.opts.Changes && v.opts.Format == DiffFormatPatch {
+		v.printChangesPatch(ui)
+	} else if v.opts.Changes {
 		for _, view := range v.changeViews {
 			textDiffView := ctldiff.NewTextDiffView(view.ConfigurableTextDiff(), v.maskRules, v.opts.TextDiffViewOpts)
-			ui.BeginLinef("@@ %s %s @@\n", applyOpCodeUI[view.ApplyOp()], view.Resource().Description())
-			ui.PrintBlock([]byte(textDiffView.String()))
+			ui.BeginLinef("@@ %s %s @@\n", applyOpCodeUI[view.ApplyOp()], v.anonymize(view.Resource().Description()))
+			ui.PrintBlock([]byte(v.anonymize(textDiffView.String())))
 		}
 	}
 
-	v.changesView = &ChangesView{ChangeViews: v.changeViews, Sort: true, countsView: NewChangesCountsView()}
+	v.changesView = &ChangesView{ChangeViews: v.changeViews, Sort: true,
+		countsView: NewChangesCountsView(), anonymizer: v.anonymizer}
 
 	if v.opts.Summary {
 		v.changesView.Print(ui)
 	}
 }
 
+func (v *ChangeSetView) anonymize(str string) string {
+	if v.anonymizer == nil {
+		return str
+	}
+	return v.anonymizer.String(str)
+}
+
 func (v *ChangeSetView) Summary() string {
 	return v.changesView.Summary() // assumes Print was used before
 }
 
-func (v ChangeSetView) printChangesYAML(ui ui.UI) error {
+func (v *ChangeSetView) printChangesPatch(ui ui.UI) {
+	for _, view := range v.changeViews {
+		patchView := ctldiff.NewUnifiedDiffView(view.ConfigurableTextDiff(),
+			v.maskRules, v.opts.TextDiffViewOpts, patchPath(view.Resource()))
+
+		patch, err := patchView.String()
+		if err != nil {
+			ui.ErrorLinef("Error calculating patch for %s: %s", v.anonymize(view.Resource().Description()), err)
+			continue
+		}
+		ui.PrintBlock([]byte(v.anonymize(patch)))
+	}
+}
+
+// patchPath returns file path representing resource
+// (e.g. "default/deployment.apps/app.yaml")
+func patchPath(res ctlres.Resource) string {
+	kind := strings.ToLower(res.Kind())
+	if len(res.APIGroup()) > 0 {
+		kind += "." + res.APIGroup()
+	}
+	path := kind + "/" + res.Name() + ".yaml"
+	if len(res.Namespace()) > 0 {
+		path = res.Namespace() + "/" + path
+	}
+	return path
+}
+
+func (v *ChangeSetView) printChangesYAML(ui ui.UI) error {
 	for _, view := range v.changeViews {
 		resYAML := ""
-		opAndResDesc := fmt.Sprintf("# %s: %s", applyOpCodeUI[view.ApplyOp()], view.Resource().Description())
+		opAndResDesc := fmt.Sprintf("# %s: %s", applyOpCodeUI[view.ApplyOp()], v.anonymize(view.Resource().Description()))
 		strategy, err := view.ApplyStrategyOp()
 		if err != nil {
 			return err
@@ -96,7 +155,7 @@ func (v ChangeSetView) printChangesYAML(ui ui.UI) error {
 			if err != nil {
 				return err
 			}
-			resYAML = string(resBytes)
+			resYAML = v.anonymize(string(resBytes))
 		}
 		ui.PrintBlock([]byte(fmt.Sprintf(`---
 %s
@@ -30,6 +30,7 @@ type ChangesView struct {
 	Sort        bool
 
 	countsView *ChangesCountsView
+	anonymizer *ctldiff.Anonymizer
 }
 
 func (v *ChangesView) Print(ui ui.UI) {
@@ -81,9 +82,14 @@ func (v *ChangesView) Print(ui ui.UI) {
 		resource := view.Resource()
 		v.countsView.Add(view.ApplyOp(), view.WaitOp())
 
+		namespace, name := resource.Namespace(), resource.Name()
+		if v.anonymizer != nil {
+			namespace, name = v.anonymizer.Value(namespace), v.anonymizer.Value(name)
+		}
+
 		row := []uitable.Value{
-			cmdcore.NewValueNamespace(resource.Namespace()),
-			uitable.NewValueString(resource.Name()),
+			cmdcore.NewValueNamespace(namespace),
+			uitable.NewValueString(name),
 			uitable.NewValueString(resource.Kind()),
 			uitable.NewValueString(resource.APIVersion()),
 		}
@@ -275,3 +281,29 @@ func NewValueResourceConverged(resource ctlres.Resource) ValueResourceConverged
 
 	return ValueResourceConverged{stateVal, reasonVal}
 }
+
+// HasErrorState returns true if resource is known to have failed
+// reconciling (e.g. failed conditions or failed Pod phase)
+func HasErrorState(resource ctlres.Resource) bool {
+	if !resource.IsProvisioned() {
+		return false
+	}
+
+	convergedResFactory := NewConvergedResourceFactory(nil, ConvergedResourceFactoryOpts{})
+
+	state, _, err := convergedResFactory.New(resource, nil).IsDoneApplying()
+	return err != nil || (state.Done && !state.Successful)
+}
+
+// IsReadyState returns true if resource is known to have
+// successfully reconciled (e.g. Deployment finished rollout)
+func IsReadyState(resource ctlres.Resource) bool {
+	if !resource.IsProvisioned() {
+		return false
+	}
+
+	convergedResFactory := NewConvergedResourceFactory(nil, ConvergedResourceFactoryOpts{})
+
+	state, _, err := convergedResFactory.New(resource, nil).IsDoneApplying()
+	return err == nil && state.Done && state.Successful
+}
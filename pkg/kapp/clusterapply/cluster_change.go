@@ -52,6 +52,8 @@ type ClusterChangeOpts struct {
 	ApplyIgnored bool
 	Wait         bool
 	WaitIgnored  bool
+	// WaitIgnoreKinds lists kinds that are not waited on when added or updated
+	WaitIgnoreKinds []string
 
 	AddOrUpdateChangeOpts
 }
@@ -66,6 +68,7 @@ type ClusterChange struct {
 	ui                  UI
 
 	markedNeedsWaiting bool
+	savedRes           ctlres.Resource
 
 	diffMaskRules []ctlconf.DiffMaskRule
 }
@@ -80,7 +83,7 @@ func NewClusterChange(change ctldiff.Change, opts ClusterChangeOpts,
 	diffMaskRules []ctlconf.DiffMaskRule) *ClusterChange {
 
 	return &ClusterChange{change, opts, identifiedResources,
-		changeFactory, changeSetFactory, convergedResFactory, ui, false, diffMaskRules}
+		changeFactory, changeSetFactory, convergedResFactory, ui, false, nil, diffMaskRules}
 }
 
 func (c *ClusterChange) ApplyOp() ClusterChangeApplyOp {
@@ -125,6 +128,11 @@ func (c *ClusterChange) WaitOp() ClusterChangeWaitOp {
 
 	switch c.change.Op() {
 	case ctldiff.ChangeOpAdd, ctldiff.ChangeOpUpdate:
+		// Deletions are still waited on so that dependent changes
+		// are not applied before resource is actually gone
+		if c.isWaitIgnoredKind() {
+			return ClusterChangeWaitOpNoop
+		}
 		return ClusterChangeWaitOpOK
 
 	case ctldiff.ChangeOpDelete:
@@ -165,6 +173,20 @@ func (c *ClusterChange) WaitOp() ClusterChangeWaitOp {
 
 func (c *ClusterChange) MarkNeedsWaiting() { c.markedNeedsWaiting = true }
 
+// SavedResource returns resource as it was last returned by the server
+// while applying this change (nil if resource was not created or updated)
+func (c *ClusterChange) SavedResource() ctlres.Resource { return c.savedRes }
+
+func (c *ClusterChange) isWaitIgnoredKind() bool {
+	kind := c.Resource().Kind()
+	for _, ignoredKind := range c.opts.WaitIgnoreKinds {
+		if ignoredKind == kind {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *ClusterChange) ApplyStrategyOp() (ClusterChangeApplyStrategyOp, error) {
 	strategy, err := c.applyStrategy()
 	if err != nil {
@@ -206,7 +228,8 @@ func (c *ClusterChange) applyStrategy() (ApplyStrategy, error) {
 	case ClusterChangeApplyOpAdd, ClusterChangeApplyOpUpdate:
 		return AddOrUpdateChange{
 			c.change, c.identifiedResources, c.changeFactory,
-			c.changeSetFactory, c.opts.AddOrUpdateChangeOpts, c.diffMaskRules}.ApplyStrategy()
+			c.changeSetFactory, c.opts.AddOrUpdateChangeOpts, c.diffMaskRules,
+			func(res ctlres.Resource) { c.savedRes = res }}.ApplyStrategy()
 
 	case ClusterChangeApplyOpDelete:
 		return DeleteChange{c.change, c.identifiedResources}.ApplyStrategy()
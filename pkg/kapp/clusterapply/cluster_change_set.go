@@ -11,6 +11,7 @@ import (
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
 	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	uierrs "github.com/cppforlife/go-cli-ui/errors"
 )
 
@@ -20,6 +21,9 @@ type ClusterChangeSetOpts struct {
 
 	ExitEarlyOnApplyError bool
 	ExitEarlyOnWaitError  bool
+
+	// Report, if set, collects outcomes of applied changes
+	Report *ApplyReport
 }
 
 type ClusterChangeSet struct {
@@ -100,8 +104,14 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 
 	blockedChanges := ctldgraph.NewBlockedChanges(changesGraph)
 	applyingChanges := NewApplyingChanges(
-		expectedNumChanges, c.opts.ApplyingChangesOpts, c.clusterChangeFactory, c.ui, c.opts.ExitEarlyOnApplyError)
-	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts, c.ui, c.opts.ExitEarlyOnWaitError)
+		expectedNumChanges, c.opts.ApplyingChangesOpts, c.clusterChangeFactory, c.ui, c.opts.ExitEarlyOnApplyError, c.opts.Report)
+	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts, c.ui, c.opts.ExitEarlyOnWaitError, c.opts.Report)
+
+	var allChanges []*ClusterChange
+	for _, change := range changesGraph.All() {
+		allChanges = append(allChanges, change.Change.(wrappedClusterChange).ClusterChange)
+	}
+	c.opts.Report.Add(allChanges)
 
 	var unsuccessfulChanges []string
 
@@ -146,6 +156,19 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 	}
 }
 
+// SavedResources returns resources as they were accepted by the server
+// for changes within given graph that created or updated a resource
+func (c ClusterChangeSet) SavedResources(changesGraph *ctldgraph.ChangeGraph) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, change := range changesGraph.All() {
+		savedRes := change.Change.(wrappedClusterChange).SavedResource()
+		if savedRes != nil {
+			result = append(result, savedRes)
+		}
+	}
+	return result
+}
+
 func ClusterChangesAsChangeViews(changes []*ClusterChange) []ChangeView {
 	var result []ChangeView
 	for _, change := range changes {
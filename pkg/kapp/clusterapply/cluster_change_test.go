@@ -0,0 +1,46 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply_test
+
+import (
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterChangeWaitOpWithWaitIgnoreKinds(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+  namespace: my-ns
+`))
+
+	opts := ctlcap.ClusterChangeOpts{Wait: true, WaitIgnoreKinds: []string{"ConfigMap"}}
+
+	waitOp := func(op ctldiff.ChangeOp) ctlcap.ClusterChangeWaitOp {
+		change := fakeChange{res: res, op: op}
+		return ctlcap.NewClusterChange(change, opts, ctlres.IdentifiedResources{},
+			ctldiff.ChangeFactory{}, ctldiff.ChangeSetFactory{}, ctlcap.ConvergedResourceFactory{}, nil, nil).WaitOp()
+	}
+
+	require.Equal(t, ctlcap.ClusterChangeWaitOpNoop, waitOp(ctldiff.ChangeOpAdd))
+	require.Equal(t, ctlcap.ClusterChangeWaitOpNoop, waitOp(ctldiff.ChangeOpUpdate))
+	require.Equal(t, ctlcap.ClusterChangeWaitOpDelete, waitOp(ctldiff.ChangeOpDelete))
+}
+
+type fakeChange struct {
+	ctldiff.Change
+
+	res ctlres.Resource
+	op  ctldiff.ChangeOp
+}
+
+func (c fakeChange) NewOrExistingResource() ctlres.Resource { return c.res }
+func (c fakeChange) Op() ctldiff.ChangeOp                   { return c.op }
+func (c fakeChange) IsIgnored() bool                        { return false }
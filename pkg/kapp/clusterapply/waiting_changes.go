@@ -28,6 +28,7 @@ type WaitingChanges struct {
 	opts           WaitingChangesOpts
 	ui             UI
 	exitOnError    bool
+	report         *ApplyReport
 }
 
 type WaitingChange struct {
@@ -36,8 +37,8 @@ type WaitingChange struct {
 	startTime time.Time
 }
 
-func NewWaitingChanges(numTotal int, opts WaitingChangesOpts, ui UI, exitOnError bool) *WaitingChanges {
-	return &WaitingChanges{numTotal, 0, nil, opts, ui, exitOnError}
+func NewWaitingChanges(numTotal int, opts WaitingChangesOpts, ui UI, exitOnError bool, report *ApplyReport) *WaitingChanges {
+	return &WaitingChanges{numTotal, 0, nil, opts, ui, exitOnError, report}
 }
 
 func (c *WaitingChanges) Track(changes []WaitingChange) {
@@ -94,6 +95,7 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 			c.ui.Notify(descMsgs)
 
 			if err != nil {
+				c.report.waited(change.Cluster, ApplyReportStatusFailed, err.Error())
 				err = fmt.Errorf("%s: Errored: %w", desc, err)
 				if c.exitOnError {
 					return nil, nil, err
@@ -114,6 +116,7 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 				}
 
 			case state.Done && !state.Successful:
+				c.report.waited(change.Cluster, ApplyReportStatusFailed, state.Message)
 				msg := ""
 				if len(state.Message) > 0 {
 					msg = ": " + state.Message
@@ -125,6 +128,7 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 				unsuccessfulChangeDesc = append(unsuccessfulChangeDesc, err.Error())
 
 			case state.Done && state.Successful:
+				c.report.waited(change.Cluster, ApplyReportStatusSucceeded, state.Message)
 				doneChanges = append(doneChanges, change)
 			}
 		}
@@ -54,6 +54,8 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 
 	cmd.Flags().BoolVar(&s.Wait, prefix+"wait", defaults.Wait, "Set to wait for changes to be applied")
 	cmd.Flags().BoolVar(&s.WaitIgnored, prefix+"wait-ignored", defaults.WaitIgnored, "Set to wait for ignored changes to be applied")
+	cmd.Flags().StringSliceVar(&s.WaitIgnoreKinds, prefix+"wait-ignore-kind", nil,
+		"Set kind of resources to apply without waiting for them (can repeat)")
 
 	cmd.Flags().DurationVar(&s.WaitingChangesOpts.Timeout, prefix+"wait-timeout",
 		mustParseDuration("15m"), "Maximum amount of time to wait in wait phase")
@@ -69,6 +71,18 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 	cmd.Flags().BoolVar(&s.ExitEarlyOnWaitError, prefix+"exit-early-on-wait-error", true, "Exit quickly on wait failure")
 }
 
+// Validate checks that concurrency values bound
+// apply and wait operations to at least one at a time
+func (s *ApplyFlags) Validate() error {
+	if s.ApplyingChangesOpts.Concurrency < 1 {
+		return fmt.Errorf("Expected --apply-concurrency to be >= 1, but was %d", s.ApplyingChangesOpts.Concurrency)
+	}
+	if s.WaitingChangesOpts.Concurrency < 1 {
+		return fmt.Errorf("Expected --wait-concurrency to be >= 1, but was %d", s.WaitingChangesOpts.Concurrency)
+	}
+	return nil
+}
+
 func mustParseDuration(str string) time.Duration {
 	dur, err := time.ParseDuration(str)
 	if err != nil {
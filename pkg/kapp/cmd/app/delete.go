@@ -60,6 +60,11 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 }
 
 func (o *DeleteOptions) Run() error {
+	err := o.ApplyFlags.Validate()
+	if err != nil {
+		return err
+	}
+
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
@@ -205,7 +210,7 @@ func (o *DeleteOptions) existingResources(app ctlapp.App,
 		return nil, false, err
 	}
 
-	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter(o.depsFactory)
 	if err != nil {
 		return nil, false, err
 	}
@@ -101,7 +101,13 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 }
 
 func (o *DeployOptions) Run() error {
+	err := o.ApplyFlags.Validate()
+	if err != nil {
+		return err
+	}
+
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
+	o.ResourceTypesFlags.FieldManager = o.DeployFlags.FieldManager
 
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
 	if err != nil {
@@ -142,7 +148,7 @@ func (o *DeployOptions) Run() error {
 
 	labeledResources := ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
 
-	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter(o.depsFactory)
 	if err != nil {
 		return err
 	}
@@ -162,6 +168,10 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	if len(o.DeployFlags.ReportFile) > 0 {
+		o.ApplyFlags.Report = ctlcap.NewApplyReport()
+	}
+
 	existingResources, existingPodRs, err := o.existingResources(
 		newResources, labeledResources, resourceFilter, supportObjs.Apps, usedGKs, append(meta.LastChange.Namespaces, nsNames...), isNewApp)
 	if err != nil {
@@ -183,10 +193,20 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	err = o.validateRequiredLabels(clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
 	if o.DiffFlags.UI {
 		return o.presentDiffUI(clusterChangesGraph)
 	}
 
+	if o.DeployFlags.DryRunOrder {
+		ctlcap.NewChangeOrderView(clusterChangesGraph).Print(o.ui)
+		return nil
+	}
+
 	if o.DiffFlags.Run || hasNoChanges {
 		o.writeAppMetadataToFile(app)
 
@@ -200,6 +220,7 @@ func (o *DeployOptions) Run() error {
 	}
 
 	if o.PreflightChecks != nil {
+		o.PreflightChecks.SetFieldManager(o.DeployFlags.FieldManager)
 		err = o.PreflightChecks.SetConfig(conf.PreflightRules())
 		if err != nil {
 			return fmt.Errorf("preflight configuration settings failed: %w", err)
@@ -243,10 +264,24 @@ func (o *DeployOptions) Run() error {
 		AppChangesMaxToKeep: o.DeployFlags.AppChangesMaxToKeep,
 	}
 
+	if o.DeployFlags.AppChangesRecordResources {
+		touch.Resources = ctlapp.NewChangeResources(newResources)
+	}
+
 	err = touch.Do(func() error {
 		defer o.writeAppMetadataToFile(app)
 
 		err := clusterChangeSet.Apply(clusterChangesGraph)
+		// Write report regardless of apply outcome
+		reportErr := o.writeApplyReportToFile()
+		if err != nil {
+			return err
+		}
+		if reportErr != nil {
+			return reportErr
+		}
+
+		err = o.writeAppliedResourcesToFile(clusterChangeSet.SavedResources(clusterChangesGraph))
 		if err != nil {
 			return err
 		}
@@ -324,6 +359,21 @@ func (o *DeployOptions) newResources(
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
 
+	waitRules, err := o.DeployFlags.WaitRules()
+	if err != nil {
+		return nil, ctlconf.Conf{}, nil, nil, err
+	}
+
+	conf = conf.WithPriorityWaitRules(waitRules)
+
+	if len(o.DeployFlags.ApplyPriorityKinds) > 0 {
+		applyPriorityConfig, err := ctlconf.NewApplyPriorityKindsConfig(o.DeployFlags.ApplyPriorityKinds)
+		if err != nil {
+			return nil, ctlconf.Conf{}, nil, nil, err
+		}
+		conf = conf.WithConfig(applyPriorityConfig)
+	}
+
 	newResources, err = prep.PrepareResources(newResources)
 	if err != nil {
 		return nil, ctlconf.Conf{}, nil, nil, err
@@ -489,6 +539,22 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 			changeViews, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts)
 		changeSetView.Print(o.ui)
 		changesSummary = changeSetView.Summary()
+
+		if o.DeployFlags.Summary {
+			ctlcap.NewChangeOpSummaryView(clusterChangesGraph).Print(o.ui)
+		}
+	}
+
+	if len(o.DeployFlags.DiffJSONFile) > 0 {
+		changesJSON, err := ctlcap.NewChangeSetJSON(ctlcap.ClusterChangesAsChangeViews(clusterChanges), conf.DiffMaskRules())
+		if err != nil {
+			return clusterChangeSet, clusterChangesGraph, false, "", err
+		}
+
+		err = os.WriteFile(o.DeployFlags.DiffJSONFile, changesJSON, os.ModePerm)
+		if err != nil {
+			return clusterChangeSet, clusterChangesGraph, false, "", fmt.Errorf("Writing diff JSON: %w", err)
+		}
 	}
 
 	return clusterChangeSet, clusterChangesGraph, (len(clusterChanges) == 0), changesSummary, err
@@ -515,6 +581,71 @@ func (o *DeployOptions) writeAppMetadataToFile(app ctlapp.App) error {
 	return nil
 }
 
+func (o *DeployOptions) validateRequiredLabels(graph *ctldgraph.ChangeGraph) error {
+	if len(o.DeployFlags.RequiredLabels) == 0 {
+		return nil
+	}
+
+	var errs []string
+
+	for _, change := range graph.All() {
+		if change.Change.Op() == ctldgraph.ActualChangeOpDelete {
+			continue
+		}
+		res := change.Change.Resource()
+		for _, key := range o.DeployFlags.RequiredLabels {
+			if _, found := res.Labels()[key]; !found {
+				errs = append(errs, fmt.Sprintf("- Resource '%s' is missing required label '%s'", res.Description(), key))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Validation errors:\n%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func (o *DeployOptions) writeApplyReportToFile() error {
+	if o.ApplyFlags.Report == nil {
+		return nil
+	}
+
+	reportJSON, err := o.ApplyFlags.Report.AsJSON()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(o.DeployFlags.ReportFile, reportJSON, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Writing apply report: %w", err)
+	}
+	return nil
+}
+
+func (o *DeployOptions) writeAppliedResourcesToFile(resources []ctlres.Resource) error {
+	if o.DeployFlags.OutputApplied == "" {
+		return nil
+	}
+
+	var result []byte
+
+	for _, res := range resources {
+		resBs, err := res.AsYAMLBytes()
+		if err != nil {
+			return err
+		}
+		result = append(result, []byte("---\n")...)
+		result = append(result, resBs...)
+	}
+
+	err := os.WriteFile(o.DeployFlags.OutputApplied, result, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Writing applied resources: %w", err)
+	}
+	return nil
+}
+
 const (
 	deployLogsAnnKey              = "kapp.k14s.io/deploy-logs" // valid value is '' (default), for-new, for-existing, for-new-or-existing
 	deployLogsAnnDefault          = ""                         // equivalent to for-new
@@ -7,26 +7,39 @@ import (
 	"fmt"
 
 	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	"github.com/spf13/cobra"
 )
 
 type DeployFlags struct {
 	ctlapp.PrepareResourcesOpts
-	Patch      bool
-	AllowEmpty bool
+	Patch       bool
+	AllowEmpty  bool
+	Summary     bool
+	DryRunOrder bool
+
+	RequiredLabels []string
+	FieldManager   string
+	WaitFor        []string
+
+	ApplyPriorityKinds []string
 
 	ExistingNonLabeledResourcesCheck            bool
 	ExistingNonLabeledResourcesCheckConcurrency int
 	OverrideOwnershipOfExistingResources        bool
 	OwnershipOverrideAllowedApps                []string
 
-	AppChangesMaxToKeep int
+	AppChangesMaxToKeep       int
+	AppChangesRecordResources bool
 
 	DefaultLabelScopingRules bool
 
 	Logs            bool
 	LogsAll         bool
 	AppMetadataFile string
+	OutputApplied   string
+	DiffJSONFile    string
+	ReportFile      string
 
 	DisableGKScoping bool
 }
@@ -40,8 +53,19 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&s.IntoNamespace, "into-ns", "", "Place resources into namespace")
 	cmd.Flags().StringSliceVar(&s.MapNamespaces, "map-ns", nil, "Map resources from one namespace into another (could be specified multiple times)")
 
+	cmd.Flags().StringVar(&s.DeployID, "deploy-id", "", "Annotate all applied resources with given deploy ID")
+	cmd.Flags().StringVar(&s.DeployIDAnnKey, "deploy-id-annotation", ctlapp.DeployIDAnnKeyDefault, "Set annotation key used for deploy ID")
+
 	cmd.Flags().BoolVarP(&s.Patch, "patch", "p", false, "Add or update existing resources only, never delete any")
 	cmd.Flags().BoolVar(&s.AllowEmpty, "dangerous-allow-empty-list-of-resources", false, "Allow to apply empty set of resources (same as running kapp delete)")
+	cmd.Flags().BoolVar(&s.Summary, "summary", false, "Show summary of changes grouped by operation before applying")
+	cmd.Flags().StringSliceVar(&s.RequiredLabels, "require-label", nil, "Fail if any deployed resource does not have given label key set (can repeat)")
+	cmd.Flags().StringSliceVar(&s.WaitFor, "wait-for", nil,
+		"Wait for resources of given kind to have condition (format: <Kind>[.<group>]:<ConditionType>=<Status>) (example: Pod:Ready=True) (can repeat)")
+	cmd.Flags().StringSliceVar(&s.ApplyPriorityKinds, "apply-priority-kinds", nil,
+		"Apply resources of given kinds in given order before other resources (format: <Kind>[.<group>]) (example: ConfigMap,Secret)")
+	cmd.Flags().StringVar(&s.FieldManager, "field-manager", "kapp", "Set field manager name recorded in managed fields of applied resources")
+	cmd.Flags().BoolVar(&s.DryRunOrder, "dry-run-order", false, "Show order in which changes would be applied and deleted without applying them")
 
 	cmd.Flags().BoolVar(&s.ExistingNonLabeledResourcesCheck, "existing-non-labeled-resources-check",
 		true, "Find and consider existing non-labeled resources in diff")
@@ -56,11 +80,29 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 		true, "Use default label scoping rules")
 
 	cmd.Flags().IntVar(&s.AppChangesMaxToKeep, "app-changes-max-to-keep", ctlapp.AppChangesMaxToKeepDefault, "Maximum number of app changes to keep")
+	cmd.Flags().BoolVar(&s.AppChangesRecordResources, "app-changes-record-resources", false,
+		"Record references of applied resources in app change (skipped if app change would become too large)")
 
 	cmd.Flags().BoolVar(&s.Logs, "logs", true, fmt.Sprintf("Show logs from Pods annotated as '%s'", deployLogsAnnKey))
 	cmd.Flags().BoolVar(&s.LogsAll, "logs-all", false, "Show logs from all Pods")
 	cmd.Flags().StringVar(&s.AppMetadataFile, "app-metadata-file-output", "", "Set filename to write app metadata")
+	cmd.Flags().StringVar(&s.DiffJSONFile, "diff-json", "", "Set filename to write planned changes with field level diff as JSON")
+	cmd.Flags().StringVar(&s.ReportFile, "report", "", "Set filename to write outcome of each applied change (op, status, wait outcome, duration) as JSON")
+	cmd.Flags().StringVar(&s.OutputApplied, "output-applied", "", "Set filename to write applied resources (as accepted by the server) after successful deploy")
 
 	cmd.Flags().BoolVar(&s.DisableGKScoping, "dangerous-disable-gk-scoping",
 		false, "Disable scoping of resource searching to used GroupKinds")
 }
+
+// WaitRules returns wait rules specified via --wait-for flag
+func (s *DeployFlags) WaitRules() ([]ctlconf.WaitRule, error) {
+	var rules []ctlconf.WaitRule
+	for _, val := range s.WaitFor {
+		rule, err := ctlconf.NewWaitRuleFromString(val)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
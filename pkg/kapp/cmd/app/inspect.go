@@ -4,16 +4,25 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
 
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
 	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	"carvel.dev/kapp/pkg/kapp/logger"
-	"carvel.dev/kapp/pkg/kapp/resources"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type InspectOptions struct {
@@ -25,10 +34,26 @@ type InspectOptions struct {
 	ResourceFilterFlags cmdtools.ResourceFilterFlags
 	ResourceTypesFlags  ResourceTypesFlags
 
-	Raw           bool
-	Status        bool
-	Tree          bool
-	ManagedFields bool
+	Raw              bool
+	Status           bool
+	Tree             bool
+	ManagedFields    bool
+	RootsOnly        bool
+	ErrorsOnly       bool
+	SortBy           string
+	ListPageSize     int64
+	Since            time.Duration
+	CompareNamespace string
+	ShowOwnership    bool
+	ShowProvenance   bool
+	ShowContainers   bool
+	ShowSize         bool
+	Metrics          bool
+	GraphOutput      string
+	Limit            int
+	AllNamespaces    bool
+	AppChange        string
+	NoColor          bool
 }
 
 func NewInspectOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *InspectOptions {
@@ -52,13 +77,46 @@ func NewInspectCmd(o *InspectOptions, flagsFactory cmdcore.FlagsFactory) *cobra.
 	cmd.Flags().BoolVar(&o.Status, "status", false, "Output status content")
 	cmd.Flags().BoolVarP(&o.Tree, "tree", "t", false, "Tree view")
 	cmd.Flags().BoolVar(&o.ManagedFields, "managed-fields", false, "Keep the metadata.managedFields when printing objects")
+	cmd.Flags().BoolVar(&o.RootsOnly, "roots-only", false, "Show only top-level resources (resources without owner references)")
+	cmd.Flags().BoolVar(&o.ErrorsOnly, "errors-only", false, "Show only resources that failed reconciling (e.g. failed conditions)")
+	cmd.Flags().StringVar(&o.SortBy, "sort-by", "", "Sort resources by value at JSONPath (example: .metadata.creationTimestamp) or by serialized size from largest to smallest (size)")
+	cmd.Flags().DurationVar(&o.Since, "since", 0, "Show only resources changed within given duration (example: 10m)")
+	cmd.Flags().StringVar(&o.CompareNamespace, "compare-namespace", "", "Show differences with the same app deployed in given namespace")
+	cmd.Flags().BoolVar(&o.ShowOwnership, "show-ownership", false, "Show kapp ownership labels and annotations of each resource")
+	cmd.Flags().BoolVar(&o.ShowProvenance, "show-provenance", false, "Show Helm and kapp management labels and annotations of each resource side by side")
+	cmd.Flags().BoolVar(&o.ShowContainers, "show-containers", false, "Show readiness and restart count of each Pod container")
+	cmd.Flags().BoolVar(&o.ShowSize, "show-size", false, "Show serialized size of each resource in bytes")
+	cmd.Flags().BoolVar(&o.Metrics, "metrics", false, "Output resource counts and readiness by kind in Prometheus text format")
+	cmd.Flags().StringVar(&o.GraphOutput, "graph-output", "", "Write owner reference graph of displayed resources in Graphviz DOT format to given file")
+	cmd.Flags().IntVar(&o.Limit, "limit", 0, "Show only first N resources after sorting and filtering (0 means no limit)")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", false, "Show app resources in all namespaces instead of only namespaces recorded during last deploy")
+	cmd.Flags().StringVar(&o.AppChange, "app-change", "", "Show resources recorded in given app change (see 'kapp app-change ls') instead of live resources")
+	cmd.Flags().BoolVar(&o.NoColor, "no-color", false, "Omit color codes from output (also enabled by NO_COLOR environment variable)")
+	cmd.Flags().Int64Var(&o.ListPageSize, "list-page-size", 0, "Maximum number of resources to fetch per list request (0 means no limit)")
 	return cmd
 }
 
 func (o *InspectOptions) Run() error {
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
-	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	sortBySize := o.SortBy == cmdtools.SortBySize
+
+	var sortByPath *cmdtools.JSONPath
+	if len(o.SortBy) > 0 && !sortBySize {
+		path, err := cmdtools.NewJSONPath(o.SortBy)
+		if err != nil {
+			return err
+		}
+		sortByPath = &path
+	}
+
+	resTypesFlags := o.ResourceTypesFlags
+	if o.AllNamespaces {
+		// Listing must not be limited to fallback allowed namespaces
+		resTypesFlags.ScopeToFallbackAllowedNamespaces = false
+	}
+
+	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, resTypesFlags, o.logger)
 	if err != nil {
 		return err
 	}
@@ -80,19 +138,79 @@ func (o *InspectOptions) Run() error {
 		return err
 	}
 
-	resources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, resources.IdentifiedResourcesListOpts{
-		ResourceNamespaces: meta.LastChange.Namespaces})
-	if err != nil {
-		return err
+	source := fmt.Sprintf("app '%s'", app.Name())
+
+	var resources []ctlres.Resource
+
+	if len(o.AppChange) > 0 {
+		resources, err = o.appChangeResources(app)
+		if err != nil {
+			return err
+		}
+		source = fmt.Sprintf("app change '%s' of app '%s'", o.AppChange, app.Name())
+	} else {
+		resourceNamespaces := meta.LastChange.Namespaces
+		if o.AllNamespaces {
+			listableNamespaces, err := o.listableNamespaces(supportObjs.CoreClient)
+			if err != nil {
+				return err
+			}
+			resourceNamespaces = append(append([]string{}, resourceNamespaces...), listableNamespaces...)
+		}
+
+		resources, err = supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+			ResourceNamespaces: resourceNamespaces, PageSize: o.ListPageSize})
+		if err != nil {
+			return err
+		}
 	}
 
-	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter(o.depsFactory)
 	if err != nil {
 		return err
 	}
 
-	resources = resourceFilter.Apply(resources)
-	source := fmt.Sprintf("app '%s'", app.Name())
+	filter := func(resources []ctlres.Resource) []ctlres.Resource {
+		resources = resourceFilter.Apply(resources)
+		if o.RootsOnly {
+			resources = o.rootResources(resources)
+		}
+		if o.Since > 0 {
+			resources = o.recentlyChangedResources(resources)
+		}
+		if o.ErrorsOnly {
+			resources = o.errorResources(resources)
+		}
+		return resources
+	}
+
+	resources = filter(resources)
+
+	if len(o.CompareNamespace) > 0 {
+		return o.compareNamespace(resources, filter)
+	}
+
+	if len(o.GraphOutput) > 0 {
+		err := os.WriteFile(o.GraphOutput, cmdtools.InspectOwnerGraph{Resources: resources}.DOT(), os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("Writing owner reference graph: %w", err)
+		}
+	}
+
+	var notes []string
+
+	if o.Limit > 0 && len(resources) > o.Limit {
+		switch {
+		case sortBySize:
+			resources = cmdtools.SortResourcesBySize(resources)
+		case sortByPath != nil:
+			resources = cmdtools.SortResourcesByJSONPath(resources, *sortByPath)
+		default:
+			resources = o.sortedResources(resources)
+		}
+		notes = append(notes, fmt.Sprintf("(showing %d of %d)", o.Limit, len(resources)))
+		resources = resources[:o.Limit]
+	}
 
 	switch {
 	case o.Raw:
@@ -117,13 +235,109 @@ func (o *InspectOptions) Run() error {
 	case o.Status:
 		InspectStatusView{Source: source, Resources: resources}.Print(o.ui)
 
+	case o.Metrics:
+		o.ui.PrintBlock(cmdtools.InspectMetrics{App: app.Name(), Resources: resources}.Text())
+
 	default:
 		if o.Tree {
-			cmdtools.InspectTreeView{Source: source, Resources: resources, Sort: true}.Print(o.ui)
+			cmdtools.InspectTreeView{Source: source, Resources: resources, Sort: true,
+				NoColor: cmdtools.NoColorRequested(o.NoColor)}.Print(o.ui)
 		} else {
-			cmdtools.InspectView{Source: source, Resources: resources, Sort: true}.Print(o.ui)
+			cmdtools.InspectView{Source: source, Resources: resources, Sort: true,
+				SortByPath: sortByPath, SortBySize: sortBySize, ShowOwnership: o.ShowOwnership, ShowProvenance: o.ShowProvenance,
+				ShowContainers: o.ShowContainers, ShowSize: o.ShowSize, Notes: notes,
+				NoColor: cmdtools.NoColorRequested(o.NoColor)}.Print(o.ui)
 		}
 	}
 
 	return nil
 }
+
+// appChangeResources returns resources recorded in app change.
+// Recorded resources only include identifying fields (not content).
+func (o *InspectOptions) appChangeResources(app ctlapp.App) ([]ctlres.Resource, error) {
+	changes, err := app.Changes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		if change.Name() != o.AppChange {
+			continue
+		}
+		meta := change.Meta()
+		if len(meta.Resources) == 0 {
+			return nil, fmt.Errorf("App change '%s' does not have recorded resources "+
+				"(resources are only recorded by deploys made with --app-changes-record-resources)", o.AppChange)
+		}
+		var result []ctlres.Resource
+		for _, res := range meta.Resources {
+			result = append(result, res.AsResource())
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("Expected to find app change '%s' for app '%s'", o.AppChange, app.Name())
+}
+
+// sortedResources sorts resources in the same way as inspect table
+func (o *InspectOptions) sortedResources(resources []ctlres.Resource) []ctlres.Resource {
+	result := append([]ctlres.Resource{}, resources...)
+	sort.SliceStable(result, func(i, j int) bool {
+		keyI := []string{result[i].Namespace(), result[i].Name(), result[i].Kind(), result[i].APIVersion()}
+		keyJ := []string{result[j].Namespace(), result[j].Name(), result[j].Kind(), result[j].APIVersion()}
+		return slices.Compare(keyI, keyJ) < 0
+	})
+	return result
+}
+
+func (o *InspectOptions) rootResources(resources []ctlres.Resource) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, res := range resources {
+		if len(res.OwnerRefs()) == 0 {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+func (o *InspectOptions) errorResources(resources []ctlres.Resource) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, res := range resources {
+		if ctlcap.HasErrorState(res) {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+func (o *InspectOptions) recentlyChangedResources(resources []ctlres.Resource) []ctlres.Resource {
+	since := time.Now().Add(-o.Since)
+
+	var result []ctlres.Resource
+	for _, res := range resources {
+		if ctlres.LastChangedAt(res).After(since) {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+// listableNamespaces returns names of all namespaces caller is allowed to list.
+// Returns no namespaces if caller is not allowed to list namespaces.
+func (o *InspectOptions) listableNamespaces(coreClient kubernetes.Interface) ([]string, error) {
+	nsList, err := coreClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		if errors.IsForbidden(err) {
+			o.ui.PrintLinef("Warning: Not allowed to list namespaces, showing resources only in recorded namespaces")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Listing namespaces: %w", err)
+	}
+
+	var names []string
+	for _, ns := range nsList.Items {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+var (
+	// Fields that are expected to differ between
+	// copies of the same app in different namespaces
+	compareNamespaceExcludedPaths = [][]string{
+		{"metadata", "uid"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "generation"},
+		{"metadata", "managedFields"},
+		{"metadata", "selfLink"},
+		{"metadata", "ownerReferences"},
+		{"metadata", "labels", "kapp.k14s.io/app"},
+		{"metadata", "labels", "kapp.k14s.io/association"},
+		{"metadata", "annotations", "kapp.k14s.io/identity"},
+		{"status"},
+	}
+
+	compareNamespaceOpCodeUI = map[ctldiff.ChangeOp]string{
+		ctldiff.ChangeOpAdd:    "create",
+		ctldiff.ChangeOpDelete: "delete",
+		ctldiff.ChangeOpUpdate: "update",
+	}
+)
+
+// compareNamespace shows structural differences between resources of
+// this app and resources of the app with the same name in compared namespace.
+// Resources created by the cluster (e.g. Pods of a Deployment) are not compared
+// since their names are typically generated.
+func (o *InspectOptions) compareNamespace(resources []ctlres.Resource, filter func([]ctlres.Resource) []ctlres.Resource) error {
+	compareAppFlags := o.AppFlags
+	compareAppFlags.NamespaceFlags.Name = o.CompareNamespace
+	compareAppFlags.AppNamespace = ""
+
+	compareApp, supportObjs, err := Factory(o.depsFactory, compareAppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	labelSelector, err := compareApp.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	meta, err := compareApp.Meta()
+	if err != nil {
+		return err
+	}
+
+	comparedResources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: meta.LastChange.Namespaces, PageSize: o.ListPageSize})
+	if err != nil {
+		return err
+	}
+
+	comparedResources = filter(comparedResources)
+
+	var mods []ctlres.FieldRemoveMod
+	for _, path := range compareNamespaceExcludedPaths {
+		mods = append(mods, ctlres.FieldRemoveMod{
+			ResourceMatcher: ctlres.AllMatcher{},
+			Path:            ctlres.NewPathFromStrings(path),
+		})
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, mods, ctldiff.ChangeOpts{})
+
+	changes, err := ctldiff.NewChangeSet(o.comparableResources(resources, ""),
+		o.comparableResources(comparedResources, o.CompareNamespace), ctldiff.ChangeSetOpts{}, changeFactory).Calculate()
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Comparing app '%s' in namespace '%s' (existing) with namespace '%s' (new)",
+		compareApp.Name(), o.AppFlags.NamespaceFlags.Name, o.CompareNamespace)
+
+	var changeViews []ctlcap.ChangeView
+	for _, change := range changes {
+		if change.Op() == ctldiff.ChangeOpKeep {
+			continue
+		}
+
+		diff, err := ctldiff.DiffResources(change.ExistingResource(), change.NewResource())
+		if err != nil {
+			return err
+		}
+
+		o.ui.BeginLinef("@@ %s %s @@\n", compareNamespaceOpCodeUI[change.Op()], change.NewOrExistingResource().Description())
+		o.ui.PrintBlock([]byte(diff))
+
+		changeViews = append(changeViews, cmdtools.NewDiffChangeView(change))
+	}
+
+	ctlcap.NewChangeSetView(changeViews, nil, ctlcap.ChangeSetViewOpts{Summary: true}).Print(o.ui)
+
+	return nil
+}
+
+// comparableResources excludes cluster created resources and moves
+// resources from given namespace into this app's namespace so that
+// they are matched with their counterparts
+func (o *InspectOptions) comparableResources(resources []ctlres.Resource, fromNamespace string) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, res := range resources {
+		if res.Transient() {
+			continue
+		}
+		if len(fromNamespace) > 0 && res.Namespace() == fromNamespace {
+			res = res.DeepCopy()
+			res.SetNamespace(o.AppFlags.NamespaceFlags.Name)
+		}
+		result = append(result, res)
+	}
+	return result
+}
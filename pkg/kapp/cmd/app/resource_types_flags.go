@@ -13,6 +13,9 @@ type ResourceTypesFlags struct {
 	CanIgnoreFailingAPIService func(schema.GroupVersion) bool
 
 	ScopeToFallbackAllowedNamespaces bool
+
+	// FieldManager is used for resource writes (set by deploy)
+	FieldManager string
 }
 
 func (s *ResourceTypesFlags) Set(cmd *cobra.Command) {
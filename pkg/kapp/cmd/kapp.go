@@ -14,9 +14,13 @@ import (
 	cmdsa "carvel.dev/kapp/pkg/kapp/cmd/serviceaccount"
 	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
 	"carvel.dev/kapp/pkg/kapp/crdupgradesafety"
+	"carvel.dev/kapp/pkg/kapp/deprecatedapis"
+	"carvel.dev/kapp/pkg/kapp/fieldconflicts"
+	"carvel.dev/kapp/pkg/kapp/imagereferences"
 	"carvel.dev/kapp/pkg/kapp/logger"
 	"carvel.dev/kapp/pkg/kapp/permissions"
 	"carvel.dev/kapp/pkg/kapp/preflight"
+	"carvel.dev/kapp/pkg/kapp/resourcequota"
 	"carvel.dev/kapp/pkg/kapp/version"
 	"github.com/cppforlife/cobrautil"
 	"github.com/cppforlife/go-cli-ui/ui"
@@ -49,16 +53,20 @@ func NewKappOptions(ui *ui.ConfUI, configFactory cmdcore.ConfigFactory,
 func NewDefaultKappCmd(ui *ui.ConfUI) *cobra.Command {
 	configFactory := cmdcore.NewConfigFactoryImpl()
 	depsFactory := cmdcore.NewDepsFactoryImpl(configFactory, ui)
-	preflights := defaultKappPreflightRegistry(depsFactory)
+	preflights := defaultKappPreflightRegistry(depsFactory, ui)
 	options := NewKappOptions(ui, configFactory, depsFactory, preflights)
 	flagsFactory := cmdcore.NewFlagsFactory(configFactory, depsFactory)
 	return NewKappCmd(options, flagsFactory)
 }
 
-func defaultKappPreflightRegistry(depsFactory cmdcore.DepsFactory) *preflight.Registry {
+func defaultKappPreflightRegistry(depsFactory cmdcore.DepsFactory, ui ui.UI) *preflight.Registry {
 	registry := preflight.NewRegistry(map[string]preflight.Check{
 		"PermissionValidation": permissions.NewPreflight(depsFactory, false),
-		"CRDUpgradeSafety":     crdupgradesafety.NewPreflight(depsFactory, false),
+		"CRDUpgradeSafety":     crdupgradesafety.NewPreflight(depsFactory, ui, false),
+		"ImageReferences":      imagereferences.NewPreflight(false),
+		"FieldConflicts":       fieldconflicts.NewPreflight(depsFactory, false),
+		"ResourceQuota":        resourcequota.NewPreflight(depsFactory, false),
+		"DeprecatedAPIs":       deprecatedapis.NewPreflight(depsFactory, ui, false),
 	})
 
 	return registry
@@ -139,6 +147,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	appCmd.AddCommand(cmdtools.NewInspectCmd(cmdtools.NewInspectOptions(o.ui, o.depsFactory), flagsFactory))
 	appCmd.AddCommand(cmdtools.NewDiffCmd(cmdtools.NewDiffOptions(o.ui, o.depsFactory), flagsFactory))
 	appCmd.AddCommand(cmdtools.NewListLabelsCmd(cmdtools.NewListLabelsOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	appCmd.AddCommand(cmdtools.NewCRDUpgradeSafetyCmd(cmdtools.NewCRDUpgradeSafetyOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(appCmd)
 
 	finishDebugLog := func(cmd *cobra.Command) {
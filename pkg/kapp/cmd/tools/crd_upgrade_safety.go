@@ -0,0 +1,213 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sort"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"carvel.dev/kapp/pkg/kapp/crdupgradesafety"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const stdinFile = "-"
+
+type CRDUpgradeSafetyOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	OldFiles       []string
+	NewFiles       []string
+	OldFromCluster bool
+
+	FileSystem fs.FS
+}
+
+func NewCRDUpgradeSafetyOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *CRDUpgradeSafetyOptions {
+	return &CRDUpgradeSafetyOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewCRDUpgradeSafetyCmd(o *CRDUpgradeSafetyOptions, _ cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "crd-upgrade-safety",
+		Short: "Check that upgrading CRDs from old files to new files is safe",
+		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Check CRD upgrade safety between two files
+  kapp tools crd-upgrade-safety --old old-crd.yml --new new-crd.yml
+
+  # Check CRD upgrade safety between two directories of CRDs
+  kapp tools crd-upgrade-safety --old old-crds/ --new new-crds/
+
+  # Check CRD upgrade safety of multiple CRDs read from stdin
+  cat new-crds/*.yml | kapp tools crd-upgrade-safety --old old-crds/ --new -
+
+  # Check CRD upgrade safety between CRDs on the cluster and a file
+  kapp tools crd-upgrade-safety --old-from-cluster --new new-crd.yml`,
+	}
+	cmd.Flags().StringSliceVar(&o.OldFiles, "old", nil, "Set file or directory with existing CRDs (format: /tmp/foo, https://..., -) (can repeat)")
+	cmd.Flags().BoolVar(&o.OldFromCluster, "old-from-cluster", false, "Use CRDs on the cluster with the same names as new CRDs as existing CRDs")
+	cmd.Flags().StringSliceVar(&o.NewFiles, "new", nil, "Set file or directory with new CRDs (format: /tmp/foo, https://..., -) (can repeat)")
+	return cmd
+}
+
+func (o *CRDUpgradeSafetyOptions) Run() error {
+	if o.OldFromCluster && len(o.OldFiles) > 0 {
+		return fmt.Errorf("Expected only one of --old or --old-from-cluster specified")
+	}
+	if (len(o.OldFiles) == 0 && !o.OldFromCluster) || len(o.NewFiles) == 0 {
+		return fmt.Errorf("Expected at least one --old (or --old-from-cluster) and one --new specified")
+	}
+	if slices.Contains(o.OldFiles, stdinFile) && slices.Contains(o.NewFiles, stdinFile) {
+		return fmt.Errorf("Expected stdin (-) to be used by only one of --old or --new")
+	}
+
+	newCRDs, err := o.crdsByName(o.NewFiles)
+	if err != nil {
+		return err
+	}
+
+	var oldCRDs map[string]apiextv1.CustomResourceDefinition
+
+	if o.OldFromCluster {
+		oldCRDs, err = o.clusterCRDsByName(sortedCRDNames(newCRDs))
+	} else {
+		oldCRDs, err = o.crdsByName(o.OldFiles)
+	}
+	if err != nil {
+		return err
+	}
+
+	validator := crdupgradesafety.NewDefaultValidator()
+	validateErrs := []error{}
+
+	for _, name := range sortedCRDNames(oldCRDs) {
+		newCRD, found := newCRDs[name]
+		if !found {
+			validateErrs = append(validateErrs, fmt.Errorf("CustomResourceDefinition %s removed", name))
+			o.ui.PrintLinef("CustomResourceDefinition %s: removed", name)
+			continue
+		}
+
+		warnings, err := validator.ValidateWithWarnings(oldCRDs[name], newCRD)
+		for _, warning := range warnings {
+			o.ui.PrintLinef("Warning: %s", warning)
+		}
+		if err != nil {
+			validateErrs = append(validateErrs, err)
+			o.ui.PrintLinef("CustomResourceDefinition %s: unsafe", name)
+			continue
+		}
+
+		o.ui.PrintLinef("CustomResourceDefinition %s: safe", name)
+	}
+
+	for _, name := range sortedCRDNames(newCRDs) {
+		if _, found := oldCRDs[name]; !found {
+			o.ui.PrintLinef("CustomResourceDefinition %s: added (skipped)", name)
+		}
+	}
+
+	if len(validateErrs) > 0 {
+		baseErr := errors.New("validation for safe CRD upgrades failed")
+		return errors.Join(append([]error{baseErr}, validateErrs...)...)
+	}
+
+	return nil
+}
+
+func (o *CRDUpgradeSafetyOptions) crdsByName(files []string) (map[string]apiextv1.CustomResourceDefinition, error) {
+	result := map[string]apiextv1.CustomResourceDefinition{}
+
+	for _, file := range files {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fileRes := range fileRs {
+			resources, err := fileRes.Resources()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, res := range resources {
+				if res.GroupVersion().WithKind(res.Kind()) != apiextv1.SchemeGroupVersion.WithKind("CustomResourceDefinition") {
+					continue
+				}
+
+				crd := apiextv1.CustomResourceDefinition{}
+				err := res.AsUncheckedTypedObj(&crd)
+				if err != nil {
+					return nil, fmt.Errorf("converting resource %s to a CRD object: %w", res.Description(), err)
+				}
+
+				if _, found := result[crd.Name]; found {
+					return nil, fmt.Errorf("CustomResourceDefinition %s specified more than once", crd.Name)
+				}
+				result[crd.Name] = crd
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// clusterCRDsByName fetches CRDs with given names from the cluster.
+// CRDs that do not exist on the cluster are not included.
+func (o *CRDUpgradeSafetyOptions) clusterCRDsByName(names []string) (map[string]apiextv1.CustomResourceDefinition, error) {
+	dynamicClient, err := o.depsFactory.DynamicClient(cmdcore.DynamicClientOpts{})
+	if err != nil {
+		return nil, err
+	}
+	crdClient := dynamicClient.Resource(apiextv1.SchemeGroupVersion.WithResource("customresourcedefinitions"))
+
+	scheme := runtime.NewScheme()
+	err = apiextv1.AddToScheme(scheme)
+	if err != nil {
+		return nil, fmt.Errorf("adding apiextension apis to scheme: %w", err)
+	}
+
+	result := map[string]apiextv1.CustomResourceDefinition{}
+
+	for _, name := range names {
+		uCRD, err := crdClient.Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("getting CustomResourceDefinition %s: %w", name, err)
+		}
+
+		crd := apiextv1.CustomResourceDefinition{}
+		err = scheme.Convert(uCRD, &crd, nil)
+		if err != nil {
+			return nil, fmt.Errorf("converting CustomResourceDefinition %s to a CRD object: %w", name, err)
+		}
+		result[name] = crd
+	}
+
+	return result, nil
+}
+
+func sortedCRDNames(crds map[string]apiextv1.CustomResourceDefinition) []string {
+	var names []string
+	for name := range crds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+const crdTemplate = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: __plural__.example.com
+spec:
+  group: example.com
+  names:
+    kind: __kind__
+    plural: __plural__
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              name:
+                type: string
+                maxLength: __max_length__
+`
+
+func testCRD(kind, plural, maxLength string) string {
+	crd := strings.ReplaceAll(crdTemplate, "__kind__", kind)
+	crd = strings.ReplaceAll(crd, "__plural__", plural)
+	return strings.ReplaceAll(crd, "__max_length__", maxLength)
+}
+
+func TestCRDUpgradeSafetyDirectories(t *testing.T) {
+	fsys := fstest.MapFS{
+		"old/foos.yml": {Data: []byte(testCRD("Foo", "foos", "10"))},
+		"old/bars.yml": {Data: []byte(testCRD("Bar", "bars", "10"))},
+		"old/bazs.yml": {Data: []byte(testCRD("Baz", "bazs", "10"))},
+
+		// foos is unchanged, bars is unsafely changed, bazs is removed, quxs is added
+		"new/foos.yml": {Data: []byte(testCRD("Foo", "foos", "10"))},
+		"new/bars.yml": {Data: []byte(testCRD("Bar", "bars", "5"))},
+		"new/quxs.yml": {Data: []byte(testCRD("Qux", "quxs", "10"))},
+	}
+
+	opts := cmdtools.NewCRDUpgradeSafetyOptions(ui.NewNoopUI(), nil)
+	opts.FileSystem = fsys
+	opts.OldFiles = []string{"old"}
+	opts.NewFiles = []string{"new"}
+
+	err := opts.Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "CustomResourceDefinition bars.example.com failed upgrade safety validation")
+	require.Contains(t, err.Error(), "maximum length constraint decreased from 10 to 5")
+	require.Contains(t, err.Error(), "CustomResourceDefinition bazs.example.com removed")
+	require.NotContains(t, err.Error(), "foos.example.com")
+	require.NotContains(t, err.Error(), "quxs.example.com")
+}
+
+func TestCRDUpgradeSafetyDirectoriesSafe(t *testing.T) {
+	fsys := fstest.MapFS{
+		"old/foos.yml": {Data: []byte(testCRD("Foo", "foos", "10"))},
+		"new/foos.yml": {Data: []byte(testCRD("Foo", "foos", "20"))},
+		"new/quxs.yml": {Data: []byte(testCRD("Qux", "quxs", "10"))},
+	}
+
+	opts := cmdtools.NewCRDUpgradeSafetyOptions(ui.NewNoopUI(), nil)
+	opts.FileSystem = fsys
+	opts.OldFiles = []string{"old"}
+	opts.NewFiles = []string{"new"}
+
+	require.NoError(t, opts.Run())
+}
+
+func TestCRDUpgradeSafetyStdin(t *testing.T) {
+	fsys := fstest.MapFS{
+		"old/foos.yml": {Data: []byte(testCRD("Foo", "foos", "10"))},
+		"old/bars.yml": {Data: []byte(testCRD("Bar", "bars", "10"))},
+	}
+
+	// foos is safely changed, bars is unsafely changed
+	stdin := "---\n" + testCRD("Foo", "foos", "20") + "---\n" + testCRD("Bar", "bars", "5")
+
+	stdinFile, err := os.CreateTemp(t.TempDir(), "stdin")
+	require.NoError(t, err)
+	_, err = stdinFile.WriteString(stdin)
+	require.NoError(t, err)
+	_, err = stdinFile.Seek(0, 0)
+	require.NoError(t, err)
+
+	origStdin := os.Stdin
+	os.Stdin = stdinFile
+	defer func() { os.Stdin = origStdin }()
+
+	opts := cmdtools.NewCRDUpgradeSafetyOptions(ui.NewNoopUI(), nil)
+	opts.FileSystem = fsys
+	opts.OldFiles = []string{"old"}
+	opts.NewFiles = []string{"-"}
+
+	err = opts.Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "CustomResourceDefinition bars.example.com failed upgrade safety validation")
+	require.NotContains(t, err.Error(), "foos.example.com")
+}
+
+func TestCRDUpgradeSafetyStdinForOldAndNew(t *testing.T) {
+	opts := cmdtools.NewCRDUpgradeSafetyOptions(ui.NewNoopUI(), nil)
+	opts.OldFiles = []string{"-"}
+	opts.NewFiles = []string{"-"}
+
+	err := opts.Run()
+	require.EqualError(t, err, "Expected stdin (-) to be used by only one of --old or --new")
+}
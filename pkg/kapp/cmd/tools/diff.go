@@ -100,6 +100,8 @@ type DiffChangeView struct {
 
 var _ ctlcap.ChangeView = DiffChangeView{}
 
+func NewDiffChangeView(change ctldiff.Change) DiffChangeView { return DiffChangeView{change} }
+
 func (v DiffChangeView) Resource() ctlres.Resource { return v.change.NewOrExistingResource() }
 
 func (v DiffChangeView) ClusterOriginalResource() ctlres.Resource {
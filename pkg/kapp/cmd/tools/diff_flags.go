@@ -4,9 +4,12 @@
 package tools
 
 import (
+	"fmt"
+
 	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 type DiffFlags struct {
@@ -33,9 +36,13 @@ func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&s.Summary, prefix+"summary", true, "Show diff summary")
 	cmd.Flags().BoolVarP(&s.Changes, prefix+"changes", "c", false, "Show changes")
 
+	s.Format = ctlcap.DiffFormatText
+	cmd.Flags().Var(&diffFormatValue{&s.Format}, prefix+"format",
+		fmt.Sprintf("Set format of shown changes (%s, %s)", ctlcap.DiffFormatText, ctlcap.DiffFormatPatch))
 	cmd.Flags().IntVar(&s.Context, prefix+"context", 2, "Show number of lines around changed lines")
 	cmd.Flags().BoolVar(&s.LineNumbers, prefix+"line-numbers", true, "Show line numbers")
 	cmd.Flags().BoolVar(&s.Mask, prefix+"mask", true, "Apply masking rules")
+	cmd.Flags().BoolVar(&s.Anonymize, prefix+"anonymize", false, "Replace resource names and namespaces with stable placeholders")
 
 	cmd.Flags().BoolVar(&s.AgainstLastApplied, prefix+"against-last-applied", true, "Show changes against last applied copy when possible")
 
@@ -44,3 +51,24 @@ func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&s.AnchoredDiff, prefix+"anchored", false, "Allow using anchored diff for large resources")
 }
+
+// diffFormatValue implements the pflag.Value
+// interface to validate diff format
+type diffFormatValue struct {
+	format *string
+}
+
+var _ pflag.Value = &diffFormatValue{}
+
+func (v *diffFormatValue) String() string { return *v.format }
+func (v *diffFormatValue) Type() string   { return "string" }
+
+func (v *diffFormatValue) Set(val string) error {
+	switch val {
+	case ctlcap.DiffFormatText, ctlcap.DiffFormatPatch:
+		*v.format = val
+		return nil
+	default:
+		return fmt.Errorf("Unknown diff format '%s' (expected one of: %s, %s)", val, ctlcap.DiffFormatText, ctlcap.DiffFormatPatch)
+	}
+}
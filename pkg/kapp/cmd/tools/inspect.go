@@ -48,7 +48,7 @@ func (o *InspectOptions) Run() error {
 }
 
 func (o *InspectOptions) inspectFiles() error {
-	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter(o.depsFactory)
 	if err != nil {
 		return err
 	}
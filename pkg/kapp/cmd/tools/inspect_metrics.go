@@ -0,0 +1,67 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// InspectMetrics renders resource counts of an app,
+// grouped by kind, in Prometheus text exposition format
+type InspectMetrics struct {
+	App       string
+	Resources []ctlres.Resource
+}
+
+type inspectMetric struct {
+	Name   string
+	Help   string
+	Counts map[string]int
+}
+
+func (m InspectMetrics) Text() []byte {
+	total := inspectMetric{"kapp_app_resources_total", "Number of resources in app by kind.", map[string]int{}}
+	ready := inspectMetric{"kapp_app_resources_ready", "Number of ready resources in app by kind.", map[string]int{}}
+	notReady := inspectMetric{"kapp_app_resources_not_ready", "Number of not ready resources in app by kind.", map[string]int{}}
+
+	for _, res := range m.Resources {
+		total.Counts[res.Kind()]++
+		if ctlcap.IsReadyState(res) {
+			ready.Counts[res.Kind()]++
+		} else {
+			notReady.Counts[res.Kind()]++
+		}
+	}
+
+	var kinds []string
+	for kind := range total.Counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var buf bytes.Buffer
+
+	for _, metric := range []inspectMetric{total, ready, notReady} {
+		fmt.Fprintf(&buf, "# HELP %s %s\n", metric.Name, metric.Help)
+		fmt.Fprintf(&buf, "# TYPE %s gauge\n", metric.Name)
+		for _, kind := range kinds {
+			fmt.Fprintf(&buf, "%s{app=\"%s\",kind=\"%s\"} %d\n",
+				metric.Name, m.labelValue(m.App), m.labelValue(kind), metric.Counts[kind])
+		}
+	}
+
+	return buf.Bytes()
+}
+
+var metricLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (InspectMetrics) labelValue(val string) string {
+	return metricLabelValueReplacer.Replace(val)
+}
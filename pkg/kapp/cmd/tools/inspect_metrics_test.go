@@ -0,0 +1,47 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectMetricsText(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm1","uid":"cm1-uid"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm2","uid":"cm2-uid"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod1","uid":"pod1-uid"},"status":{"phase":"Succeeded"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod2","uid":"pod2-uid"},"status":{"phase":"Pending"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod3","uid":"pod3-uid"},"status":{"phase":"Failed"}}`)),
+	}
+
+	out := string(cmdtools.InspectMetrics{App: `my"app`, Resources: resources}.Text())
+
+	require.Equal(t, `# HELP kapp_app_resources_total Number of resources in app by kind.
+# TYPE kapp_app_resources_total gauge
+kapp_app_resources_total{app="my\"app",kind="ConfigMap"} 2
+kapp_app_resources_total{app="my\"app",kind="Pod"} 3
+# HELP kapp_app_resources_ready Number of ready resources in app by kind.
+# TYPE kapp_app_resources_ready gauge
+kapp_app_resources_ready{app="my\"app",kind="ConfigMap"} 2
+kapp_app_resources_ready{app="my\"app",kind="Pod"} 1
+# HELP kapp_app_resources_not_ready Number of not ready resources in app by kind.
+# TYPE kapp_app_resources_not_ready gauge
+kapp_app_resources_not_ready{app="my\"app",kind="ConfigMap"} 0
+kapp_app_resources_not_ready{app="my\"app",kind="Pod"} 2
+`, out)
+
+	commentLine := regexp.MustCompile(`^# (HELP|TYPE) [a-zA-Z_:][a-zA-Z0-9_:]* .+$`)
+	sampleLine := regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*\{([a-zA-Z_][a-zA-Z0-9_]*="([^"\\]|\\.)*",?)*\} [0-9]+$`)
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		require.True(t, commentLine.MatchString(line) || sampleLine.MatchString(line), "invalid metric line: %s", line)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// InspectOwnerGraph renders owner reference relationships
+// among resources as a Graphviz DOT digraph. Edges point
+// from owner to owned resource; owners that are not part of
+// provided resources are not included.
+type InspectOwnerGraph struct {
+	Resources []ctlres.Resource
+}
+
+func (g InspectOwnerGraph) DOT() []byte {
+	rsByUID := map[string]ctlres.Resource{}
+	for _, res := range g.Resources {
+		rsByUID[res.UID()] = res
+	}
+
+	var nodes, edges []string
+
+	for _, res := range g.Resources {
+		nodes = append(nodes, fmt.Sprintf("  %q [label=%q];\n", res.UID(), g.label(res)))
+
+		for _, ownerRef := range res.OwnerRefs() {
+			if _, found := rsByUID[string(ownerRef.UID)]; found {
+				edges = append(edges, fmt.Sprintf("  %q -> %q;\n", string(ownerRef.UID), res.UID()))
+			}
+		}
+	}
+
+	sort.Strings(nodes)
+	sort.Strings(edges)
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph kapp {\n")
+	buf.WriteString(strings.Join(nodes, ""))
+	buf.WriteString(strings.Join(edges, ""))
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+func (InspectOwnerGraph) label(res ctlres.Resource) string {
+	label := fmt.Sprintf("%s/%s", strings.ToLower(res.Kind()), res.Name())
+	if len(res.Namespace()) > 0 {
+		label += fmt.Sprintf(" (ns: %s)", res.Namespace())
+	}
+	return label
+}
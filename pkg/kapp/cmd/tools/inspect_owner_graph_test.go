@@ -0,0 +1,80 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"testing"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectOwnerGraphDOT(t *testing.T) {
+	deployment := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+  uid: deployment-uid
+`))
+	replicaSet := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: app-rs
+  namespace: default
+  uid: rs-uid
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: app
+    uid: deployment-uid
+`))
+	pod1 := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app-rs-pod1
+  namespace: default
+  uid: pod1-uid
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: ReplicaSet
+    name: app-rs
+    uid: rs-uid
+`))
+	pod2 := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app-rs-pod2
+  namespace: default
+  uid: pod2-uid
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: ReplicaSet
+    name: app-rs
+    uid: rs-uid
+  - apiVersion: v1
+    kind: Node
+    name: not-displayed
+    uid: node-uid
+`))
+
+	dot := cmdtools.InspectOwnerGraph{Resources: []ctlres.Resource{pod2, pod1, replicaSet, deployment}}.DOT()
+
+	expected := `digraph kapp {
+  "deployment-uid" [label="deployment/app (ns: default)"];
+  "pod1-uid" [label="pod/app-rs-pod1 (ns: default)"];
+  "pod2-uid" [label="pod/app-rs-pod2 (ns: default)"];
+  "rs-uid" [label="replicaset/app-rs (ns: default)"];
+  "deployment-uid" -> "rs-uid";
+  "rs-uid" -> "pod1-uid";
+  "rs-uid" -> "pod2-uid";
+}
+`
+	require.Equal(t, expected, string(dot))
+}
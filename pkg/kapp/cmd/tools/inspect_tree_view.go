@@ -20,9 +20,15 @@ type InspectTreeView struct {
 	Source    string
 	Resources []ctlres.Resource
 	Sort      bool
+	// NoColor omits ANSI color codes from output
+	NoColor bool
 }
 
 func (v InspectTreeView) Print(ui ui.UI) {
+	if v.NoColor {
+		defer disableColor()()
+	}
+
 	groupHeader := uitable.NewHeader("Group")
 	groupHeader.Hidden = true
 
@@ -5,6 +5,7 @@ package tools
 
 import (
 	"fmt"
+	"strings"
 
 	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
@@ -17,9 +18,41 @@ type InspectView struct {
 	Source    string
 	Resources []ctlres.Resource
 	Sort      bool
+	// SortByPath takes precedence over Sort when set
+	SortByPath *JSONPath
+	// SortBySize orders resources from largest to smallest
+	// and takes precedence over other sorting options
+	SortBySize bool
+	// ShowOwnership adds a column with kapp ownership labels and annotations
+	ShowOwnership bool
+	// ShowProvenance adds columns with Helm and kapp management
+	// metadata side by side to help identify dual-managed resources
+	ShowProvenance bool
+	// ShowContainers expands Pods into rows for each
+	// of their containers with readiness and restart count
+	ShowContainers bool
+	// ShowSize adds a column with serialized size of each resource
+	ShowSize bool
+	// Notes are shown in addition to default table notes
+	Notes []string
+	// NoColor omits ANSI color codes from output
+	NoColor bool
 }
 
+var (
+	ownershipLabelKeys = []string{"kapp.k14s.io/app", "kapp.k14s.io/association"}
+	ownershipAnnKeys   = []string{"kapp.k14s.io/identity", "kapp.k14s.io/disable-original"}
+
+	helmManagedByLabelKey      = "app.kubernetes.io/managed-by"
+	helmReleaseNameAnnKey      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnKey = "meta.helm.sh/release-namespace"
+)
+
 func (v InspectView) Print(ui ui.UI) {
+	if v.NoColor {
+		defer disableColor()()
+	}
+
 	versionHeader := uitable.NewHeader("Version")
 	versionHeader.Hidden = true
 
@@ -29,37 +62,61 @@ func (v InspectView) Print(ui ui.UI) {
 	reconcileInfoHeader := uitable.NewHeader("Reconcile info")
 	reconcileInfoHeader.Title = "Ri"
 
+	headers := []uitable.Header{
+		uitable.NewHeader("Namespace"),
+		uitable.NewHeader("Name"),
+		uitable.NewHeader("Kind"),
+		versionHeader,
+		uitable.NewHeader("Owner"),
+	}
+	if v.ShowOwnership {
+		headers = append(headers, uitable.NewHeader("Ownership"))
+	}
+	if v.ShowProvenance {
+		headers = append(headers, uitable.NewHeader("Helm"), uitable.NewHeader("Kapp"))
+	}
+	if v.ShowSize {
+		headers = append(headers, uitable.NewHeader("Size"))
+	}
+	containerColumn := len(headers)
+	if v.ShowContainers {
+		headers = append(headers, uitable.NewHeader("Container"), uitable.NewHeader("Ready"), uitable.NewHeader("Restarts"))
+	}
+	headers = append(headers, reconcileStateHeader, reconcileInfoHeader, uitable.NewHeader("Age"))
+
 	table := uitable.Table{
 		Title:   fmt.Sprintf("Resources in %s", v.Source),
 		Content: "resources",
 
-		Header: []uitable.Header{
-			uitable.NewHeader("Namespace"),
-			uitable.NewHeader("Name"),
-			uitable.NewHeader("Kind"),
-			versionHeader,
-			uitable.NewHeader("Owner"),
-			reconcileStateHeader,
-			reconcileInfoHeader,
-			uitable.NewHeader("Age"),
-		},
+		Header: headers,
 
-		Notes: []string{"Rs: Reconcile state", "Ri: Reconcile information"},
+		Notes: append([]string{"Rs: Reconcile state", "Ri: Reconcile information"}, v.Notes...),
 	}
 
-	if v.Sort {
+	resources := v.Resources
+
+	if v.SortBySize {
+		resources = SortResourcesBySize(resources)
+		table.FillFirstColumn = true
+	} else if v.SortByPath != nil {
+		resources = SortResourcesByJSONPath(resources, *v.SortByPath)
+		table.FillFirstColumn = true
+	} else if v.Sort {
 		table.SortBy = []uitable.ColumnSort{
 			{Column: 0, Asc: true},
 			{Column: 1, Asc: true},
 			{Column: 2, Asc: true},
 			{Column: 3, Asc: true},
 		}
+		if v.ShowContainers {
+			table.SortBy = append(table.SortBy, uitable.ColumnSort{Column: containerColumn, Asc: true})
+		}
 	} else {
 		// Otherwise it might look very awkward
 		table.FillFirstColumn = true
 	}
 
-	for _, resource := range v.Resources {
+	for _, resource := range resources {
 		row := []uitable.Value{
 			cmdcore.NewValueNamespace(resource.Namespace()),
 			uitable.NewValueString(resource.Name()),
@@ -68,28 +125,77 @@ func (v InspectView) Print(ui ui.UI) {
 			NewValueResourceOwner(resource),
 		}
 
+		if v.ShowOwnership {
+			row = append(row, NewValueResourceOwnership(resource))
+		}
+
+		if v.ShowProvenance {
+			row = append(row, NewValueResourceHelmProvenance(resource), NewValueResourceKappProvenance(resource))
+		}
+
+		if v.ShowSize {
+			row = append(row, uitable.NewValueInt(ResourceSize(resource)))
+		}
+
+		var statusRow []uitable.Value
+
 		if resource.IsProvisioned() {
 			syncVal := ctlcap.NewValueResourceConverged(resource)
 
-			row = append(row,
+			statusRow = []uitable.Value{
 				syncVal.StateVal,
 				syncVal.ReasonVal,
 				cmdcore.NewValueAge(resource.CreatedAt()),
-			)
+			}
 		} else {
-			row = append(row,
+			statusRow = []uitable.Value{
+				uitable.NewValueString(""),
+				uitable.NewValueString(""),
+				uitable.NewValueString(""),
+			}
+		}
+
+		if !v.ShowContainers {
+			table.Rows = append(table.Rows, append(row, statusRow...))
+			continue
+		}
+
+		containers := ctlres.ContainerStatuses(resource)
+		if len(containers) == 0 {
+			containerRow := []uitable.Value{
 				uitable.NewValueString(""),
 				uitable.NewValueString(""),
 				uitable.NewValueString(""),
-			)
+			}
+			table.Rows = append(table.Rows, concatValues(row, containerRow, statusRow))
+			continue
 		}
 
-		table.Rows = append(table.Rows, row)
+		for _, container := range containers {
+			name := container.Name
+			if container.Init {
+				name += " (init)"
+			}
+			containerRow := []uitable.Value{
+				uitable.NewValueString(name),
+				uitable.NewValueBool(container.Ready),
+				uitable.NewValueInt(int(container.RestartCount)),
+			}
+			table.Rows = append(table.Rows, concatValues(row, containerRow, statusRow))
+		}
 	}
 
 	ui.PrintTable(table)
 }
 
+func concatValues(rows ...[]uitable.Value) []uitable.Value {
+	var result []uitable.Value
+	for _, row := range rows {
+		result = append(result, row...)
+	}
+	return result
+}
+
 func NewValueResourceOwner(resource ctlres.Resource) uitable.ValueString {
 	if resource.IsProvisioned() {
 		if resource.Transient() {
@@ -99,3 +205,61 @@ func NewValueResourceOwner(resource ctlres.Resource) uitable.ValueString {
 	}
 	return uitable.NewValueString("")
 }
+
+// NewValueResourceOwnership returns kapp ownership labels and annotations
+// (e.g. app label, identity annotation) present on a resource
+func NewValueResourceOwnership(resource ctlres.Resource) uitable.ValueStrings {
+	var result []string
+
+	labels := resource.Labels()
+	for _, key := range ownershipLabelKeys {
+		if val, found := labels[key]; found {
+			result = append(result, fmt.Sprintf("label %s=%s", key, val))
+		}
+	}
+
+	anns := resource.Annotations()
+	for _, key := range ownershipAnnKeys {
+		if val, found := anns[key]; found {
+			result = append(result, fmt.Sprintf("annotation %s=%s", key, val))
+		}
+	}
+
+	return uitable.NewValueStrings(result)
+}
+
+// NewValueResourceHelmProvenance returns Helm management metadata
+// (managed-by label, release name and namespace annotations) of a resource
+func NewValueResourceHelmProvenance(resource ctlres.Resource) uitable.ValueStrings {
+	var result []string
+
+	if val, found := resource.Labels()[helmManagedByLabelKey]; found {
+		result = append(result, fmt.Sprintf("managed-by=%s", val))
+	}
+
+	anns := resource.Annotations()
+	if name, found := anns[helmReleaseNameAnnKey]; found {
+		release := name
+		if ns := anns[helmReleaseNamespaceAnnKey]; len(ns) > 0 {
+			release = ns + "/" + name
+		}
+		result = append(result, fmt.Sprintf("release=%s", release))
+	}
+
+	return uitable.NewValueStrings(result)
+}
+
+// NewValueResourceKappProvenance returns kapp app and association
+// labels of a resource
+func NewValueResourceKappProvenance(resource ctlres.Resource) uitable.ValueStrings {
+	var result []string
+
+	labels := resource.Labels()
+	for _, key := range ownershipLabelKeys {
+		if val, found := labels[key]; found {
+			result = append(result, fmt.Sprintf("%s=%s", strings.TrimPrefix(key, "kapp.k14s.io/"), val))
+		}
+	}
+
+	return uitable.NewValueStrings(result)
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"bytes"
+	"testing"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/color"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectViewNoColor(t *testing.T) {
+	// Tests do not run in a terminal, hence force color output
+	prevNoColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = prevNoColor }()
+
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"ns","uid":"cm-uid"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod","namespace":"ns","uid":"pod-uid",` +
+			`"ownerReferences":[{"apiVersion":"v1","kind":"ConfigMap","name":"cm","uid":"cm-uid"}]}}`)),
+	}
+
+	printView := func(view interface{ Print(ui.UI) }) string {
+		var out bytes.Buffer
+		confUI := ui.NewWrappingConfUI(ui.NewWriterUI(&out, &out, ui.NewNoopLogger()), ui.NewNoopLogger())
+		confUI.EnableColor()
+		view.Print(confUI)
+		return out.String()
+	}
+
+	require.Contains(t, printView(cmdtools.InspectView{Source: "app", Resources: resources}), "\x1b[")
+	require.Contains(t, printView(cmdtools.InspectTreeView{Source: "app", Resources: resources}), "\x1b[")
+
+	out := printView(cmdtools.InspectView{Source: "app", Resources: resources, NoColor: true})
+	require.NotContains(t, out, "\x1b")
+	require.Contains(t, out, "Resources in app")
+
+	out = printView(cmdtools.InspectTreeView{Source: "app", Resources: resources, NoColor: true})
+	require.NotContains(t, out, "\x1b")
+	require.Contains(t, out, " L pod")
+
+	// Previous setting is restored after printing
+	require.False(t, color.NoColor)
+}
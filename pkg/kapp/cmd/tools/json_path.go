@@ -0,0 +1,131 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// JSONPath is a simplified JSONPath expression that selects
+// a single value via field names and array indexes
+// (e.g. .metadata.creationTimestamp, .spec.ports[0].port)
+type JSONPath struct {
+	expr  string
+	parts []interface{} // string for fields, int for array indexes
+}
+
+func NewJSONPath(expr string) (JSONPath, error) {
+	path := strings.TrimSpace(expr)
+	if strings.HasPrefix(path, "{") && strings.HasSuffix(path, "}") {
+		path = path[1 : len(path)-1]
+	}
+	path = strings.TrimPrefix(path, "$")
+
+	if !strings.HasPrefix(path, ".") {
+		return JSONPath{}, fmt.Errorf("Expected JSONPath '%s' to start with '.'", expr)
+	}
+
+	var parts []interface{}
+
+	for _, segment := range strings.Split(path[1:], ".") {
+		field, rest, _ := strings.Cut(segment, "[")
+		if len(field) == 0 && len(rest) == 0 {
+			return JSONPath{}, fmt.Errorf("Expected JSONPath '%s' to not contain empty field names", expr)
+		}
+		if len(field) > 0 {
+			parts = append(parts, field)
+		}
+
+		for len(rest) > 0 {
+			idxStr, after, found := strings.Cut(rest, "]")
+			if !found {
+				return JSONPath{}, fmt.Errorf("Expected JSONPath '%s' to have closing ']'", expr)
+			}
+			idx, err := strconv.Atoi(idxStr)
+			if err != nil || idx < 0 {
+				return JSONPath{}, fmt.Errorf("Expected JSONPath '%s' to have non-negative integer array index", expr)
+			}
+			parts = append(parts, idx)
+
+			if len(after) > 0 && !strings.HasPrefix(after, "[") {
+				return JSONPath{}, fmt.Errorf("Expected JSONPath '%s' to have '.' or '[' after ']'", expr)
+			}
+			rest = strings.TrimPrefix(after, "[")
+		}
+	}
+
+	return JSONPath{expr: expr, parts: parts}, nil
+}
+
+func (p JSONPath) String() string { return p.expr }
+
+// Evaluate returns value found at the path or false if it's missing
+func (p JSONPath) Evaluate(obj interface{}) (interface{}, bool) {
+	curr := obj
+
+	for _, part := range p.parts {
+		switch typedPart := part.(type) {
+		case string:
+			typedCurr, ok := curr.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			curr, ok = typedCurr[typedPart]
+			if !ok {
+				return nil, false
+			}
+		case int:
+			typedCurr, ok := curr.([]interface{})
+			if !ok || typedPart >= len(typedCurr) {
+				return nil, false
+			}
+			curr = typedCurr[typedPart]
+		}
+	}
+
+	return curr, true
+}
+
+// SortResourcesByJSONPath stably orders resources by value found at the path.
+// Numeric values are compared numerically, other values as strings.
+// Resources without a value are ordered last.
+func SortResourcesByJSONPath(resources []ctlres.Resource, path JSONPath) []ctlres.Resource {
+	type sortableResource struct {
+		res   ctlres.Resource
+		val   string
+		found bool
+	}
+
+	sortable := make([]sortableResource, len(resources))
+	for i, res := range resources {
+		val, found := path.Evaluate(res.UnstructuredObject())
+		sortable[i] = sortableResource{res: res, found: found}
+		if found {
+			sortable[i].val = fmt.Sprintf("%v", val)
+		}
+	}
+
+	sort.SliceStable(sortable, func(i, j int) bool {
+		if !sortable[i].found || !sortable[j].found {
+			return sortable[i].found && !sortable[j].found
+		}
+		iNum, iErr := strconv.ParseFloat(sortable[i].val, 64)
+		jNum, jErr := strconv.ParseFloat(sortable[j].val, 64)
+		if iErr == nil && jErr == nil {
+			return iNum < jNum
+		}
+		return sortable[i].val < sortable[j].val
+	})
+
+	result := make([]ctlres.Resource, len(sortable))
+	for i, item := range sortable {
+		result[i] = item.res
+	}
+	return result
+}
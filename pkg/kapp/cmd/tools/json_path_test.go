@@ -0,0 +1,88 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"testing"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPathEvaluate(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "foo"},
+		"spec": map[string]interface{}{
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80)},
+				map[string]interface{}{"port": int64(443)},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		expr     string
+		expected interface{}
+		found    bool
+	}{
+		{expr: ".metadata.name", expected: "foo", found: true},
+		{expr: "{.metadata.name}", expected: "foo", found: true},
+		{expr: "$.spec.ports[1].port", expected: int64(443), found: true},
+		{expr: ".spec.ports[2].port", found: false},
+		{expr: ".metadata.namespace", found: false},
+		{expr: ".metadata.name.foo", found: false},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			path, err := cmdtools.NewJSONPath(tc.expr)
+			require.NoError(t, err)
+
+			val, found := path.Evaluate(obj)
+			require.Equal(t, tc.found, found)
+			require.Equal(t, tc.expected, val)
+		})
+	}
+}
+
+func TestJSONPathInvalid(t *testing.T) {
+	for _, expr := range []string{"metadata.name", ".metadata..name", ".spec.ports[a]", ".spec.ports[0"} {
+		_, err := cmdtools.NewJSONPath(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestSortResourcesByJSONPath(t *testing.T) {
+	newRes := func(name, replicas string) ctlres.Resource {
+		spec := ""
+		if len(replicas) > 0 {
+			spec = `, "spec": {"replicas": ` + replicas + `}`
+		}
+		return ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "` + name + `"}` + spec + `}`))
+	}
+
+	resources := []ctlres.Resource{
+		newRes("missing", ""),
+		newRes("ten", "10"),
+		newRes("two", "2"),
+		newRes("another-two", "2"),
+	}
+
+	path, err := cmdtools.NewJSONPath(".spec.replicas")
+	require.NoError(t, err)
+
+	var names []string
+	for _, res := range cmdtools.SortResourcesByJSONPath(resources, path) {
+		names = append(names, res.Name())
+	}
+	require.Equal(t, []string{"two", "another-two", "ten", "missing"}, names)
+
+	path, err = cmdtools.NewJSONPath(".metadata.name")
+	require.NoError(t, err)
+
+	names = nil
+	for _, res := range cmdtools.SortResourcesByJSONPath(resources, path) {
+		names = append(names, res.Name())
+	}
+	require.Equal(t, []string{"another-two", "missing", "ten", "two"}, names)
+}
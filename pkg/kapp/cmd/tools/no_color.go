@@ -0,0 +1,24 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"os"
+
+	"github.com/cppforlife/color"
+)
+
+// NoColorRequested returns true if color output was disabled
+// either via flag or NO_COLOR environment variable (https://no-color.org)
+func NoColorRequested(flag bool) bool {
+	return flag || os.Getenv("NO_COLOR") != ""
+}
+
+// disableColor makes UI omit ANSI color codes (including table
+// header formatting) and returns a func that restores previous setting
+func disableColor() func() {
+	prev := color.NoColor
+	color.NoColor = true
+	return func() { color.NoColor = prev }
+}
@@ -4,34 +4,63 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"github.com/spf13/cobra"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type ResourceFilterFlags struct {
-	Age string
-	Rf  ctlres.ResourceFilter
-	Bf  string
+	Age         string
+	CreatedHour string
+	Replicas    string
+	Rf          ctlres.ResourceFilter
+	Bf          string
+
+	CustomResources         bool
+	ExcludeSystemNamespaces bool
 }
 
 func (s *ResourceFilterFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&s.Age, "filter-age", "", "Set age filter (example: 5m-, 500h+, 10m-)")
+	cmd.Flags().StringVar(&s.CreatedHour, "filter-created-hour", "", "Set filter for daily hour window (in UTC) of creation time (example: 1-3, 22-2)")
+
+	cmd.Flags().StringVar(&s.Replicas, "filter-replicas", "", "Set filter for replica count of Deployments, StatefulSets and ReplicaSets (example: >0, =0, <3)")
+
+	cmd.Flags().IntSliceVar(&s.Rf.ContainerPorts, "filter-container-port", nil, "Set filter for container port declared by pods or pod templates of workloads (example: 80) (can repeat)")
+
+	cmd.Flags().StringSliceVar(&s.Rf.CreatedByFieldManagers, "filter-created-by-field-manager", nil, "Set filter for field manager that created resource based on earliest managed fields entry (example: kube-controller-manager) (can repeat)")
+
+	cmd.Flags().BoolVar(&s.CustomResources, "filter-custom-resources", false, "Set to only include resources of kinds served by CustomResourceDefinitions (requires cluster access)")
 
 	cmd.Flags().StringSliceVar(&s.Rf.Kinds, "filter-kind", nil, "Set kinds filter (example: Pod) (can repeat)")
+	cmd.Flags().StringSliceVar(&s.Rf.Groups, "filter-group", nil, "Set API group filter (example: networking.k8s.io, core) (can repeat)")
+	cmd.Flags().StringSliceVar(&s.Rf.KindGroups, "filter-kind-group", nil, "Set kind-group filter (example: Ingress.networking.k8s.io, Service.core) (can repeat)")
 	cmd.Flags().StringSliceVar(&s.Rf.Namespaces, "filter-ns", nil, "Set namespace filter (example: knative-serving) (can repeat)")
 	cmd.Flags().StringSliceVar(&s.Rf.Names, "filter-name", nil, "Set name filter (example: controller) (can repeat)")
 	cmd.Flags().StringSliceVar(&s.Rf.KindNames, "filter-kind-name", nil, "Set kind-name filter (example: Pod/controller) (can repeat)")
 	cmd.Flags().StringSliceVar(&s.Rf.KindNamespaces, "filter-kind-ns", nil, "Set kind-namespace filter (example: Pod/, Pod/knative-serving) (can repeat)")
 	cmd.Flags().StringSliceVar(&s.Rf.KindNsNames, "filter-kind-ns-name", nil, "Set kind-namespace-name filter (example: Deployment/knative-serving/controller) (can repeat)")
 	cmd.Flags().StringSliceVar(&s.Rf.Labels, "filter-labels", nil, "Set label filter (example: x=y)")
+	cmd.Flags().StringSliceVar(&s.Rf.ChangeGroups, "filter-change-group", nil, "Set change group filter (example: apps.big.co/db) (can repeat)")
+	cmd.Flags().BoolVar(&s.Rf.HasFinalizers, "filter-has-finalizers", false, "Set to only include resources with finalizers")
+	cmd.Flags().StringSliceVar(&s.Rf.Finalizers, "filter-finalizer", nil, "Set finalizer filter (example: kubernetes.io/pvc-protection) (can repeat)")
+	cmd.Flags().StringSliceVar(&s.Rf.StatusReasons, "filter-status-reason", nil, "Set status reason filter (example: CrashLoopBackOff) (can repeat)")
+	cmd.Flags().BoolVar(&s.Rf.Orphaned, "filter-orphaned", false, "Set to only include resources that are not managed by any app (without kapp app label)")
+
+	cmd.Flags().BoolVar(&s.ExcludeSystemNamespaces, "exclude-system-ns", false, "Exclude resources in system namespaces (kube-system, kube-public, kube-node-lease)")
 
 	cmd.Flags().StringVar(&s.Bf, "filter", "", `Set filter (example: {"and":[{"not":{"resource":{"kinds":["foo%"]}}},{"resource":{"kinds":["!foo"]}}]})`)
 }
 
-func (s *ResourceFilterFlags) ResourceFilter() (ctlres.ResourceFilter, error) {
+// ResourceFilter returns filter based on flags. Dependencies are
+// only used for filters that need cluster access (e.g. --filter-custom-resources)
+func (s *ResourceFilterFlags) ResourceFilter(depsFactory cmdcore.DepsFactory) (ctlres.ResourceFilter, error) {
 	createdAtBeforeTime, createdAtAfterTime, err := s.Times()
 	if err != nil {
 		return ctlres.ResourceFilter{}, err
@@ -41,6 +70,33 @@ func (s *ResourceFilterFlags) ResourceFilter() (ctlres.ResourceFilter, error) {
 	rf.CreatedAtAfterTime = createdAtAfterTime
 	rf.CreatedAtBeforeTime = createdAtBeforeTime
 
+	if len(s.CreatedHour) > 0 {
+		rf.CreatedAtHours, err = ctlres.NewHourWindowFromString(s.CreatedHour)
+		if err != nil {
+			return ctlres.ResourceFilter{}, err
+		}
+	}
+
+	if len(s.Replicas) > 0 {
+		rf.Replicas, err = ctlres.NewReplicasComparisonFromString(s.Replicas)
+		if err != nil {
+			return ctlres.ResourceFilter{}, err
+		}
+	}
+
+	if s.CustomResources {
+		rf.CustomResources, err = s.customResourceGroupKinds(depsFactory)
+		if err != nil {
+			return ctlres.ResourceFilter{}, err
+		}
+	}
+
+	if s.ExcludeSystemNamespaces {
+		// Copy to avoid modifying flag backed slice
+		rf.ExcludedNamespaces = append([]string{}, rf.ExcludedNamespaces...)
+		rf.ExcludeSystemNamespaces()
+	}
+
 	if len(s.Bf) > 0 {
 		boolFilter, err := ctlres.NewBoolFilterFromString(s.Bf)
 		if err != nil {
@@ -53,6 +109,26 @@ func (s *ResourceFilterFlags) ResourceFilter() (ctlres.ResourceFilter, error) {
 	return rf, nil
 }
 
+func (s *ResourceFilterFlags) customResourceGroupKinds(depsFactory cmdcore.DepsFactory) (ctlres.CustomResourceGroupKinds, error) {
+	dynamicClient, err := depsFactory.DynamicClient(cmdcore.DynamicClientOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	crdList, err := dynamicClient.Resource(apiextv1.SchemeGroupVersion.WithResource("customresourcedefinitions")).
+		List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Listing CustomResourceDefinitions: %w", err)
+	}
+
+	var crds []ctlres.Resource
+	for _, item := range crdList.Items {
+		crds = append(crds, ctlres.NewResourceUnstructured(item, ctlres.ResourceType{}))
+	}
+
+	return ctlres.NewCustomResourceGroupKinds(crds), nil
+}
+
 func (s *ResourceFilterFlags) Times() (*time.Time, *time.Time, error) {
 	if len(s.Age) == 0 {
 		return nil, nil, nil
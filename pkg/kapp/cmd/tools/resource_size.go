@@ -0,0 +1,39 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools
+
+import (
+	"sort"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// SortBySize is a special --sort-by value
+// that orders resources by their serialized size
+const SortBySize = "size"
+
+// ResourceSize returns number of bytes in compact JSON
+// representation of a resource (similar to how it is stored by cluster)
+func ResourceSize(res ctlres.Resource) int {
+	bs, err := res.AsCompactBytes()
+	if err != nil {
+		return 0
+	}
+	return len(bs)
+}
+
+// SortResourcesBySize stably orders resources from largest to smallest
+// so that bloated resources are shown first (e.g. with --limit)
+func SortResourcesBySize(resources []ctlres.Resource) []ctlres.Resource {
+	sizes := map[ctlres.Resource]int{}
+	for _, res := range resources {
+		sizes[res] = ResourceSize(res)
+	}
+
+	result := append([]ctlres.Resource{}, resources...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return sizes[result[i]] > sizes[result[j]]
+	})
+	return result
+}
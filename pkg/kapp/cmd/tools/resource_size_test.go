@@ -0,0 +1,30 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortResourcesBySize(t *testing.T) {
+	smallJSON := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"small"}}`
+	small := ctlres.MustNewResourceFromBytes([]byte(smallJSON))
+	large := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"large"},"data":{"key":"` +
+		strings.Repeat("x", 1000) + `"}}`))
+	medium := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"medium"},"data":{"key":"value"}}`))
+
+	require.Equal(t, len(smallJSON), cmdtools.ResourceSize(small))
+	require.Greater(t, cmdtools.ResourceSize(large), 1000)
+
+	var names []string
+	for _, res := range cmdtools.SortResourcesBySize([]ctlres.Resource{small, large, medium}) {
+		names = append(names, res.Name())
+	}
+	require.Equal(t, []string{"large", "medium", "small"}, names)
+}
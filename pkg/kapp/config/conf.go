@@ -132,6 +132,20 @@ func (c Conf) WaitRules() []WaitRule {
 	return rules
 }
 
+// WithPriorityWaitRules returns a copy of Conf with given wait rules
+// taking precedence over wait rules from other configs
+func (c Conf) WithPriorityWaitRules(rules []WaitRule) Conf {
+	if len(rules) == 0 {
+		return c
+	}
+	return Conf{append([]Config{{WaitRules: rules}}, c.configs...)}
+}
+
+// WithConfig returns a copy of Conf with given config added
+func (c Conf) WithConfig(config Config) Conf {
+	return Conf{append(append([]Config{}, c.configs...), config)}
+}
+
 func (c Conf) LabelScopingMods(defaultRules bool) func(kvs map[string]string) []ctlres.StringMapAppendMod {
 	return func(kvs map[string]string) []ctlres.StringMapAppendMod {
 		var mods []ctlres.StringMapAppendMod
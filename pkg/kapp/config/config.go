@@ -5,6 +5,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"carvel.dev/kapp/pkg/kapp/version"
@@ -338,3 +339,100 @@ func (r WaitRule) ResourceMatcher() ctlres.ResourceMatcher {
 		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
 	}
 }
+
+// NewWaitRuleFromString parses a wait rule in format
+// "<Kind>[.<group>]:<ConditionType>=<Status>" (e.g. "Pod:Ready=True",
+// "Certificate.cert-manager.io:Ready=True"). Resources of given kind
+// are considered done once they have matching condition.
+func NewWaitRuleFromString(val string) (WaitRule, error) {
+	errFmt := "Expected wait rule '%s' to be in format '<Kind>[.<group>]:<ConditionType>=<Status>' (example: Pod:Ready=True)"
+
+	kindGroup, cond, found := strings.Cut(val, ":")
+	if !found {
+		return WaitRule{}, fmt.Errorf(errFmt, val)
+	}
+
+	condType, condStatus, found := strings.Cut(cond, "=")
+	if !found {
+		return WaitRule{}, fmt.Errorf(errFmt, val)
+	}
+
+	kind, group, _ := strings.Cut(kindGroup, ".")
+	if group == "core" {
+		group = ""
+	}
+
+	if len(kind) == 0 || len(condType) == 0 || len(condStatus) == 0 {
+		return WaitRule{}, fmt.Errorf(errFmt, val)
+	}
+
+	return WaitRule{
+		ConditionMatchers: []WaitRuleConditionMatcher{
+			{Type: condType, Status: condStatus, Success: true},
+		},
+		ResourceMatchers: []ResourceMatcher{
+			{APIGroupKindMatcher: &APIGroupKindMatcher{APIGroup: group, Kind: kind}},
+		},
+	}, nil
+}
+
+const applyPriorityKindsChangeGroupPrefix = "change-groups.kapp.k14s.io/apply-priority-kinds-"
+
+// NewApplyPriorityKindsConfig returns a config with change groups and rules
+// that upsert resources of given kinds (in format "<Kind>[.<group>]") in order
+// before all other resources. Rules are ignored if they would conflict with
+// other ordering rules (e.g. Namespaces are still created before ConfigMaps).
+func NewApplyPriorityKindsConfig(kinds []string) (Config, error) {
+	var config Config
+	var kindMatchers []ResourceMatcher
+
+	for i, val := range kinds {
+		kind, group, _ := strings.Cut(val, ".")
+		if group == "core" {
+			group = ""
+		}
+		if len(kind) == 0 {
+			return Config{}, fmt.Errorf("Expected apply priority kind '%s' to be in format '<Kind>[.<group>]' (example: ConfigMap)", val)
+		}
+
+		kindMatcher := ResourceMatcher{APIGroupKindMatcher: &APIGroupKindMatcher{APIGroup: group, Kind: kind}}
+		groupName := fmt.Sprintf("%s%d", applyPriorityKindsChangeGroupPrefix, i)
+
+		config.ChangeGroupBindings = append(config.ChangeGroupBindings, ChangeGroupBinding{
+			Name:             groupName,
+			ResourceMatchers: []ResourceMatcher{kindMatcher},
+		})
+
+		if i > 0 {
+			config.ChangeRuleBindings = append(config.ChangeRuleBindings, ChangeRuleBinding{
+				Rules:            applyPriorityKindsRules(i),
+				IgnoreIfCyclical: true,
+				ResourceMatchers: []ResourceMatcher{kindMatcher},
+			})
+		}
+
+		kindMatchers = append(kindMatchers, kindMatcher)
+	}
+
+	if len(kinds) > 0 {
+		config.ChangeRuleBindings = append(config.ChangeRuleBindings, ChangeRuleBinding{
+			Rules:            applyPriorityKindsRules(len(kinds)),
+			IgnoreIfCyclical: true,
+			ResourceMatchers: []ResourceMatcher{{
+				NotMatcher: &NotMatcher{Matcher: ResourceMatcher{AnyMatcher: &AnyMatcher{Matchers: kindMatchers}}},
+			}},
+		})
+	}
+
+	return config, nil
+}
+
+// applyPriorityKindsRules returns rules to upsert after
+// all change groups of kinds with higher priority than given
+func applyPriorityKindsRules(priority int) []string {
+	var rules []string
+	for i := 0; i < priority; i++ {
+		rules = append(rules, fmt.Sprintf("upsert after upserting %s%d", applyPriorityKindsChangeGroupPrefix, i))
+	}
+	return rules
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWaitRuleFromString(t *testing.T) {
+	rule, err := config.NewWaitRuleFromString("Certificate.cert-manager.io:Ready=True")
+	require.NoError(t, err)
+	require.Equal(t, config.WaitRule{
+		ConditionMatchers: []config.WaitRuleConditionMatcher{{Type: "Ready", Status: "True", Success: true}},
+		ResourceMatchers: []config.ResourceMatcher{
+			{APIGroupKindMatcher: &config.APIGroupKindMatcher{APIGroup: "cert-manager.io", Kind: "Certificate"}},
+		},
+	}, rule)
+
+	for _, val := range []string{"Pod:Ready=True", "Pod.core:Ready=True"} {
+		rule, err := config.NewWaitRuleFromString(val)
+		require.NoError(t, err)
+		require.Equal(t, &config.APIGroupKindMatcher{APIGroup: "", Kind: "Pod"}, rule.ResourceMatchers[0].APIGroupKindMatcher)
+	}
+
+	for _, val := range []string{"", "Pod", "Pod:Ready", ":Ready=True", "Pod:=True", "Pod:Ready="} {
+		_, err := config.NewWaitRuleFromString(val)
+		require.Error(t, err, "value: %s", val)
+	}
+}
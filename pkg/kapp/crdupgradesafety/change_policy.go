@@ -0,0 +1,105 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package crdupgradesafety
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// ChangePolicy declares schema changes that are allowed for
+// specific fields even if they would otherwise be deemed unsafe
+// (or unknown) by ChangeValidator
+type ChangePolicy struct {
+	Fields []ChangePolicyField `json:"fields"`
+}
+
+// ChangePolicyField lists JSON schema keywords (e.g. "maximum")
+// that are allowed to change for fields matching Path
+type ChangePolicyField struct {
+	// Path is a flattened field path (e.g. "^.spec.replicas").
+	// "*" matches any sequence of characters, including "."
+	Path string `json:"path"`
+	// AllowedChanges is a list of JSON schema keywords
+	AllowedChanges []string `json:"allowedChanges"`
+}
+
+// Validate checks that policy only references
+// valid field paths and known JSON schema keywords
+func (p ChangePolicy) Validate() error {
+	var errs []error
+	for i, field := range p.Fields {
+		if !strings.HasPrefix(field.Path, "^") {
+			errs = append(errs, fmt.Errorf("fields[%d]: expected path %q to start with '^' (e.g. \"^.spec.replicas\")", i, field.Path))
+		}
+		if len(field.AllowedChanges) == 0 {
+			errs = append(errs, fmt.Errorf("fields[%d]: expected at least one allowed change", i))
+		}
+		for _, keyword := range field.AllowedChanges {
+			if _, found := schemaKeywordFields[keyword]; !found {
+				errs = append(errs, fmt.Errorf("fields[%d]: unknown JSON schema keyword %q", i, keyword))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("validating change policy: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ChangePolicyValidation returns a ChangeValidation that treats changes
+// to keywords allowed by the policy as handled. It should be placed first
+// among validations so that allowed changes are not reported by others.
+func ChangePolicyValidation(policy ChangePolicy) ChangeValidation {
+	type matcher struct {
+		matches  func(string) bool
+		keywords []string
+	}
+
+	var matchers []matcher
+	for _, field := range policy.Fields {
+		matchers = append(matchers, matcher{ignorePathsMatcher([]string{field.Path}), field.AllowedChanges})
+	}
+
+	return func(diff FieldDiff) (bool, error) {
+		var keywords []string
+		for _, m := range matchers {
+			if m.matches(diff.Field) {
+				keywords = append(keywords, m.keywords...)
+			}
+		}
+		if len(keywords) == 0 {
+			return false, nil
+		}
+
+		// Similar to other validations, reset allowed keywords
+		// so that following validations only see remaining changes
+		for _, keyword := range keywords {
+			idx := schemaKeywordFields[keyword]
+			oldVal := reflect.ValueOf(diff.Old).Elem().Field(idx)
+			newVal := reflect.ValueOf(diff.New).Elem().Field(idx)
+			oldVal.Set(reflect.Zero(oldVal.Type()))
+			newVal.Set(reflect.Zero(newVal.Type()))
+		}
+		return reflect.DeepEqual(diff.Old, diff.New), nil
+	}
+}
+
+// schemaKeywordFields maps JSON schema keywords
+// to field indexes of v1.JSONSchemaProps
+var schemaKeywordFields = func() map[string]int {
+	fields := map[string]int{}
+	typ := reflect.TypeOf(v1.JSONSchemaProps{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+	return fields
+}()
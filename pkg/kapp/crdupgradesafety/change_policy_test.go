@@ -0,0 +1,132 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package crdupgradesafety_test
+
+import (
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/crdupgradesafety"
+	"carvel.dev/kapp/pkg/kapp/preflight"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestChangePolicyAllowsMaximumDecrease(t *testing.T) {
+	crd := func(maximum float64) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]v1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]v1.JSONSchemaProps{
+										"replicas": {Type: "integer", Maximum: ptr.To(maximum)},
+										"size":     {Type: "integer", Maximum: ptr.To(maximum)},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	validator := crdupgradesafety.NewValidatorWithConfig(crdupgradesafety.PreflightConfig{
+		ChangePolicy: crdupgradesafety.ChangePolicy{
+			Fields: []crdupgradesafety.ChangePolicyField{
+				{Path: "^.spec.replicas", AllowedChanges: []string{"maximum"}},
+			},
+		},
+	})
+
+	err := validator.Validate(crd(10), crd(5))
+	require.Error(t, err)
+
+	report := crdupgradesafety.NewValidationReport(err)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "^.spec.size", report.Findings[0].Field)
+	assert.Contains(t, report.Findings[0].Message, "maximum constraint decreased from 10 to 5")
+
+	err = crdupgradesafety.NewDefaultValidator().Validate(crd(10), crd(5))
+	require.Error(t, err)
+	assert.Len(t, crdupgradesafety.NewValidationReport(err).Findings, 2)
+}
+
+func TestChangePolicyValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy crdupgradesafety.ChangePolicy
+		err    string
+	}{
+		{
+			name: "valid policy",
+			policy: crdupgradesafety.ChangePolicy{Fields: []crdupgradesafety.ChangePolicyField{
+				{Path: "^.spec.*", AllowedChanges: []string{"maximum", "maxLength", "enum"}},
+			}},
+		},
+		{
+			name: "path without prefix",
+			policy: crdupgradesafety.ChangePolicy{Fields: []crdupgradesafety.ChangePolicyField{
+				{Path: ".spec.replicas", AllowedChanges: []string{"maximum"}},
+			}},
+			err: `fields[0]: expected path ".spec.replicas" to start with '^'`,
+		},
+		{
+			name: "no allowed changes",
+			policy: crdupgradesafety.ChangePolicy{Fields: []crdupgradesafety.ChangePolicyField{
+				{Path: "^.spec.replicas"},
+			}},
+			err: "fields[0]: expected at least one allowed change",
+		},
+		{
+			name: "unknown keyword",
+			policy: crdupgradesafety.ChangePolicy{Fields: []crdupgradesafety.ChangePolicyField{
+				{Path: "^.spec.replicas", AllowedChanges: []string{"maximum"}},
+				{Path: "^.spec.size", AllowedChanges: []string{"max"}},
+			}},
+			err: `fields[1]: unknown JSON schema keyword "max"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestPreflightSetConfigChangePolicy(t *testing.T) {
+	p := crdupgradesafety.NewPreflight(nil, nil, true)
+
+	err := p.SetConfig(preflight.CheckConfig{
+		"changePolicy": map[string]any{
+			"fields": []any{
+				map[string]any{"path": "^.spec.replicas", "allowedChanges": []any{"maximum"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	err = p.SetConfig(preflight.CheckConfig{
+		"changePolicy": map[string]any{
+			"fields": []any{
+				map[string]any{"path": "^.spec.replicas", "allowedChanges": []any{"maximm"}},
+			},
+		},
+	})
+	require.ErrorContains(t, err, `validating change policy: fields[0]: unknown JSON schema keyword "maximm"`)
+}
@@ -5,9 +5,13 @@ package crdupgradesafety
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -31,6 +35,21 @@ type ChangeValidation func(diff FieldDiff) (bool, error)
 // has been fully handled (i.e the only change was to enum values)
 // - An error if either of the above validations are not satisfied
 func EnumChangeValidation(diff FieldDiff) (bool, error) {
+	return enumChangeValidation(diff, false)
+}
+
+// EnumChangeValidationWithAllowedAdditions is a variant of EnumChangeValidation
+// that allows enums to be added to the given fields (e.g. "^.spec.mode")
+// even if they did not previously have enum restrictions. This is useful
+// for fields that were intentionally left unconstrained.
+func EnumChangeValidationWithAllowedAdditions(fields []string) ChangeValidation {
+	allowedFields := sets.NewString(fields...)
+	return func(diff FieldDiff) (bool, error) {
+		return enumChangeValidation(diff, allowedFields.Has(diff.Field))
+	}
+}
+
+func enumChangeValidation(diff FieldDiff, allowAddition bool) (bool, error) {
 	// This function resets the enum values for the
 	// old and new field and compares them to determine
 	// if there are any additional changes that should be
@@ -47,6 +66,9 @@ func EnumChangeValidation(diff FieldDiff) (bool, error) {
 	}
 
 	if len(diff.Old.Enum) == 0 && len(diff.New.Enum) > 0 {
+		if allowAddition {
+			return handled(), nil
+		}
 		return handled(), fmt.Errorf("enums added when there were no enum restrictions previously")
 	}
 
@@ -82,16 +104,24 @@ func EnumChangeValidation(diff FieldDiff) (bool, error) {
 // has been fully handled (i.e. the only change was to required field values)
 // - An error if either of the above criteria are not met
 func RequiredFieldChangeValidation(diff FieldDiff) (bool, error) {
+	return requiredFieldChangeValidation(diff, false)
+}
+
+// RequiredFieldWithDefaultChangeValidation is a relaxed variant of
+// RequiredFieldChangeValidation that allows a field to become required
+// as long as its property schema specifies a default value, since
+// existing resources without the field will get the default value.
+func RequiredFieldWithDefaultChangeValidation(diff FieldDiff) (bool, error) {
+	return requiredFieldChangeValidation(diff, true)
+}
+
+func requiredFieldChangeValidation(diff FieldDiff, allowWithDefault bool) (bool, error) {
 	handled := func() bool {
 		diff.Old.Required = []string{}
 		diff.New.Required = []string{}
 		return reflect.DeepEqual(diff.Old, diff.New)
 	}
 
-	if len(diff.Old.Required) == 0 && len(diff.New.Required) > 0 {
-		return handled(), fmt.Errorf("new values added as required when previously no required fields existed: %+v", diff.New.Required)
-	}
-
 	oldSet := sets.NewString()
 	for _, requiredField := range diff.Old.Required {
 		if !oldSet.Has(requiredField) {
@@ -107,6 +137,18 @@ func RequiredFieldChangeValidation(diff FieldDiff) (bool, error) {
 	}
 
 	diffSet := newSet.Difference(oldSet)
+	if allowWithDefault {
+		for _, requiredField := range diffSet.UnsortedList() {
+			if propertyHasDefault(diff.NewWithProperties, requiredField) {
+				diffSet.Delete(requiredField)
+			}
+		}
+	}
+
+	if len(diff.Old.Required) == 0 && diffSet.Len() > 0 {
+		return handled(), fmt.Errorf("new values added as required when previously no required fields existed: %+v", diffSet.List())
+	}
+
 	if diffSet.Len() > 0 {
 		return handled(), fmt.Errorf("new required fields added: %+v", diffSet.UnsortedList())
 	}
@@ -114,6 +156,14 @@ func RequiredFieldChangeValidation(diff FieldDiff) (bool, error) {
 	return handled(), nil
 }
 
+func propertyHasDefault(schema *v1.JSONSchemaProps, property string) bool {
+	if schema == nil {
+		return false
+	}
+	propSchema, found := schema.Properties[property]
+	return found && propSchema.Default != nil
+}
+
 // MinimumChangeValidation adds a validation check to ensure that
 // existing fields can have their minimum constraints updated in a CRD schema
 // based on the following:
@@ -376,6 +426,9 @@ func MaximumPropertiesChangeValidation(diff FieldDiff) (bool, error) {
 // default value present
 // - Default value of a field cannot be changed
 // - Existing default value for a field cannot be removed
+// Defaults are compared as JSON values (not bytes), so that
+// object level defaults (e.g. default for a whole subobject)
+// are not affected by key order or formatting.
 // This function returns:
 // - A boolean representation of whether or not the change
 // has been fully handled (i.e. the only change was to a field's default value)
@@ -389,18 +442,22 @@ func DefaultValueChangeValidation(diff FieldDiff) (bool, error) {
 
 	switch {
 	case diff.Old.Default == nil && diff.New.Default != nil:
-		newDefault := diff.New.Default
-		return handled(), fmt.Errorf("new value added as default when previously no default value existed: %+v", newDefault)
+		newDefault := diff.New.Default.Raw
+		return handled(), fmt.Errorf("new value added as default when previously no default value existed: %s", newDefault)
 
 	case diff.Old.Default != nil && diff.New.Default == nil:
 		oldDefault := diff.Old.Default.Raw
-		return handled(), fmt.Errorf("default value has been removed when previously a default value existed: %+v", oldDefault)
+		return handled(), fmt.Errorf("default value has been removed when previously a default value existed: %s", oldDefault)
 
 	case diff.Old.Default != nil && diff.New.Default != nil:
 		oldDefault := diff.Old.Default.Raw
 		newDefault := diff.New.Default.Raw
-		if !bytes.Equal(diff.Old.Default.Raw, diff.New.Default.Raw) {
-			return handled(), fmt.Errorf("default value has been changed from %+v to %+v", oldDefault, newDefault)
+		if changedKeys, equal := compareDefaults(oldDefault, newDefault); !equal {
+			if len(changedKeys) > 0 {
+				return handled(), fmt.Errorf("default value has been changed from %s to %s (changed keys: %s)",
+					oldDefault, newDefault, strings.Join(changedKeys, ", "))
+			}
+			return handled(), fmt.Errorf("default value has been changed from %s to %s", oldDefault, newDefault)
 		}
 		fallthrough
 	default:
@@ -408,12 +465,381 @@ func DefaultValueChangeValidation(diff FieldDiff) (bool, error) {
 	}
 }
 
+// compareDefaults compares two raw default values as JSON. When
+// both values are objects, paths of changed keys are returned.
+// Values that are not valid JSON are compared as bytes.
+func compareDefaults(oldRaw, newRaw []byte) ([]string, bool) {
+	var oldVal, newVal interface{}
+	if json.Unmarshal(oldRaw, &oldVal) != nil || json.Unmarshal(newRaw, &newVal) != nil {
+		return nil, bytes.Equal(oldRaw, newRaw)
+	}
+	if reflect.DeepEqual(oldVal, newVal) {
+		return nil, true
+	}
+	return changedDefaultKeys("", oldVal, newVal), false
+}
+
+func changedDefaultKeys(path string, oldVal, newVal interface{}) []string {
+	oldObj, oldIsObj := oldVal.(map[string]interface{})
+	newObj, newIsObj := newVal.(map[string]interface{})
+	if !oldIsObj || !newIsObj {
+		if path == "" || reflect.DeepEqual(oldVal, newVal) {
+			return nil
+		}
+		return []string{path}
+	}
+
+	keys := sets.New[string]()
+	for key := range oldObj {
+		keys.Insert(key)
+	}
+	for key := range newObj {
+		keys.Insert(key)
+	}
+
+	var changed []string
+	for _, key := range sets.List(keys) {
+		keyPath := path + "." + key
+		oldKeyVal, inOld := oldObj[key]
+		newKeyVal, inNew := newObj[key]
+		if !inOld || !inNew {
+			changed = append(changed, keyPath)
+			continue
+		}
+		changed = append(changed, changedDefaultKeys(keyPath, oldKeyVal, newKeyVal)...)
+	}
+	return changed
+}
+
+// AdditionalPropertiesChangeValidation ensures that a field that allowed
+// arbitrary additional properties (additionalProperties: true) is not
+// narrowed down to a typed schema, since existing values of other types
+// would no longer be valid. Changes within additionalProperties schemas
+// are not evaluated here since FlattenSchema represents them as their
+// own fields (e.g. "^.spec.labels[*]").
+// This function returns:
+// - A boolean representation of whether or not the change
+// has been fully handled (i.e. the only change was to additionalProperties)
+// - An error if additionalProperties were narrowed from true to a schema
+func AdditionalPropertiesChangeValidation(diff FieldDiff) (bool, error) {
+	handled := func() bool {
+		diff.Old.AdditionalProperties = nil
+		diff.New.AdditionalProperties = nil
+		return reflect.DeepEqual(diff.Old, diff.New)
+	}
+
+	oldAP := diff.Old.AdditionalProperties
+	newAP := diff.New.AdditionalProperties
+
+	switch {
+	case reflect.DeepEqual(oldAP, newAP):
+		return handled(), nil
+
+	case allowsAnyAdditionalProperties(oldAP) && newAP != nil && newAP.Schema != nil:
+		return handled(), fmt.Errorf("additionalProperties narrowed from allowing any value to a schema")
+
+	case oldAP != nil && newAP != nil && oldAP.Schema != nil && newAP.Schema != nil:
+		return handled(), nil
+
+	default:
+		return false, nil
+	}
+}
+
+// IntOrStringChangeValidation ensures that x-kubernetes-int-or-string
+// is not disabled for a field. Fields with the flag set accept both
+// integers and strings, hence restricting them to a single type
+// breaks existing objects storing the other type.
+// Since toggling the flag is accompanied by a type change
+// (a type must not be set while the flag is enabled),
+// changes to type are considered part of the toggle.
+// This function returns:
+// - A boolean representation of whether or not the change
+// has been fully handled (i.e. the only change was toggling x-kubernetes-int-or-string)
+// - An error if x-kubernetes-int-or-string was disabled
+func IntOrStringChangeValidation(diff FieldDiff) (bool, error) {
+	// Type is only reset when the flag was toggled so that
+	// type changes of other fields are still reported
+	handled := func(toggled bool) bool {
+		diff.Old.XIntOrString = false
+		diff.New.XIntOrString = false
+		if toggled {
+			diff.Old.Type = ""
+			diff.New.Type = ""
+		}
+		return reflect.DeepEqual(diff.Old, diff.New)
+	}
+
+	switch {
+	case diff.Old.XIntOrString == diff.New.XIntOrString:
+		return handled(false), nil
+	case diff.Old.XIntOrString && !diff.New.XIntOrString:
+		return handled(true), fmt.Errorf("x-kubernetes-int-or-string disabled, field now only accepts type %q", diff.New.Type)
+	default:
+		return handled(true), nil
+	}
+}
+
+// EmbeddedResourceChangeValidation ensures that x-kubernetes-embedded-resource
+// is not toggled for a field. Fields with the flag set carry embedded
+// Kubernetes objects, for which apiVersion, kind and metadata are validated
+// and pruned differently, hence enabling or disabling it changes how
+// existing objects are handled.
+// This function returns:
+// - A boolean representation of whether or not the change
+// has been fully handled (i.e. the only change was to x-kubernetes-embedded-resource)
+// - An error if x-kubernetes-embedded-resource was enabled or disabled
+func EmbeddedResourceChangeValidation(diff FieldDiff) (bool, error) {
+	handled := func() bool {
+		diff.Old.XEmbeddedResource = false
+		diff.New.XEmbeddedResource = false
+		return reflect.DeepEqual(diff.Old, diff.New)
+	}
+
+	switch {
+	case diff.Old.XEmbeddedResource && !diff.New.XEmbeddedResource:
+		return handled(), fmt.Errorf("x-kubernetes-embedded-resource disabled")
+	case !diff.Old.XEmbeddedResource && diff.New.XEmbeddedResource:
+		return handled(), fmt.Errorf("x-kubernetes-embedded-resource enabled")
+	default:
+		return handled(), nil
+	}
+}
+
+// ErrFieldBecameUnstructured is returned by PreserveUnknownFieldsChangeValidation
+// when a structured object field starts preserving unknown fields
+var ErrFieldBecameUnstructured = errors.New("previously structured object field became unstructured " +
+	"(x-kubernetes-preserve-unknown-fields enabled), unknown fields will no longer be pruned")
+
+// PreserveUnknownFieldsChangeValidation detects loosening of a previously
+// structured object field to an effectively unstructured one by enabling
+// x-kubernetes-preserve-unknown-fields. Such change does not break existing
+// objects, but stops the API server from pruning unknown fields, hence it is
+// reported as ErrFieldBecameUnstructured so that its severity can be adjusted.
+// Disabling x-kubernetes-preserve-unknown-fields is not handled by this validation.
+// This function returns:
+// - A boolean representation of whether or not the change
+// has been fully handled (i.e. the only change was to x-kubernetes-preserve-unknown-fields)
+// - An error if an object field started preserving unknown fields
+func PreserveUnknownFieldsChangeValidation(diff FieldDiff) (bool, error) {
+	oldPreserves := diff.Old.XPreserveUnknownFields != nil && *diff.Old.XPreserveUnknownFields
+	newPreserves := diff.New.XPreserveUnknownFields != nil && *diff.New.XPreserveUnknownFields
+
+	if oldPreserves || !newPreserves || diff.Old.Type != "object" {
+		return false, nil
+	}
+
+	diff.Old.XPreserveUnknownFields = nil
+	diff.New.XPreserveUnknownFields = nil
+	return reflect.DeepEqual(diff.Old, diff.New), ErrFieldBecameUnstructured
+}
+
+func allowsAnyAdditionalProperties(ap *v1.JSONSchemaPropsOrBool) bool {
+	return ap != nil && ap.Allows && ap.Schema == nil
+}
+
+var transitionRuleRegexp = regexp.MustCompile(`\boldSelf\b`)
+
+// IsTransitionRule returns whether or not the provided
+// x-kubernetes-validations rule is a transition rule,
+// i.e. a rule that references the previous value of
+// the object via "oldSelf"
+func IsTransitionRule(rule v1.ValidationRule) bool {
+	return transitionRuleRegexp.MatchString(rule.Rule)
+}
+
+func withoutTransitionRules(rules v1.ValidationRules) v1.ValidationRules {
+	var result v1.ValidationRules
+	for _, rule := range rules {
+		if !IsTransitionRule(rule) {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// TransitionRuleChangeValidation adds a validation check to ensure that
+// no new CEL transition rules (x-kubernetes-validations rules referencing "oldSelf")
+// are added to existing fields in a CRD schema. Transition rules impose
+// new constraints on updates to existing objects and may break existing update flows.
+// Removal of transition rules is allowed.
+// Changes to non-transition rules are not handled by this validation.
+// This function returns:
+// - A boolean representation of whether or not the change
+// has been fully handled (i.e. the only change was to transition rules)
+// - An error if any transition rules were added
+func TransitionRuleChangeValidation(diff FieldDiff) (bool, error) {
+	return transitionRuleChangeValidation(diff, true)
+}
+
+// TransitionRuleRemovalChangeValidation is a stricter variant of
+// TransitionRuleChangeValidation that also fails when existing transition
+// rules are removed. Transition rules are commonly used to make fields
+// immutable (e.g. "self == oldSelf"), and controllers may rely on that.
+func TransitionRuleRemovalChangeValidation(diff FieldDiff) (bool, error) {
+	return transitionRuleChangeValidation(diff, false)
+}
+
+func transitionRuleChangeValidation(diff FieldDiff, allowRemoval bool) (bool, error) {
+	oldRules := diff.Old.XValidations
+	newRules := diff.New.XValidations
+
+	handled := func() bool {
+		diff.Old.XValidations = withoutTransitionRules(oldRules)
+		diff.New.XValidations = withoutTransitionRules(newRules)
+		return reflect.DeepEqual(diff.Old, diff.New)
+	}
+
+	oldSet := sets.NewString()
+	for _, rule := range oldRules {
+		if IsTransitionRule(rule) {
+			oldSet.Insert(rule.Rule)
+		}
+	}
+
+	newSet := sets.NewString()
+	for _, rule := range newRules {
+		if IsTransitionRule(rule) {
+			newSet.Insert(rule.Rule)
+		}
+	}
+
+	var errs []error
+
+	if addedSet := newSet.Difference(oldSet); addedSet.Len() > 0 {
+		errs = append(errs, fmt.Errorf("new transition rules added: %+v", addedSet.List()))
+	}
+
+	if removedSet := oldSet.Difference(newSet); !allowRemoval && removedSet.Len() > 0 {
+		errs = append(errs, fmt.Errorf("transition rules removed: %+v", removedSet.List()))
+	}
+
+	return handled(), errors.Join(errs...)
+}
+
+// CompositeBranchChangeValidation adds a validation check to ensure that
+// no branches are removed from anyOf or oneOf schemas in a CRD schema,
+// as that narrows the set of accepted values. Branches are compared by
+// structural equality, hence reordering branches is allowed.
+// - Branches can be added to existing anyOf schemas
+// - Branches cannot be added to oneOf schemas, since existing
+// values may start matching more than one branch
+// - anyOf or oneOf cannot be added when previously no branches existed
+// - anyOf or oneOf can be removed entirely, since that only widens accepted values
+// This function returns:
+// - A boolean representation of whether or not the change
+// has been fully handled (i.e. the only change was to anyOf or oneOf branches)
+// - An error if accepted values were narrowed
+func CompositeBranchChangeValidation(diff FieldDiff) (bool, error) {
+	handled := func() bool {
+		diff.Old.AnyOf = nil
+		diff.New.AnyOf = nil
+		diff.Old.OneOf = nil
+		diff.New.OneOf = nil
+		return reflect.DeepEqual(diff.Old, diff.New)
+	}
+
+	var errs []error
+
+	for _, composite := range []struct {
+		keyword       string
+		old, new      []v1.JSONSchemaProps
+		allowAddition bool
+	}{
+		{"anyOf", diff.Old.AnyOf, diff.New.AnyOf, true},
+		{"oneOf", diff.Old.OneOf, diff.New.OneOf, false},
+	} {
+		if len(composite.new) == 0 {
+			continue
+		}
+		if len(composite.old) == 0 {
+			errs = append(errs, fmt.Errorf("%s added when previously no branches existed: %s",
+				composite.keyword, branchesString(composite.new)))
+			continue
+		}
+		if removed := missingBranches(composite.old, composite.new); len(removed) > 0 {
+			errs = append(errs, fmt.Errorf("%s branches removed: %s", composite.keyword, branchesString(removed)))
+		}
+		if added := missingBranches(composite.new, composite.old); len(added) > 0 && !composite.allowAddition {
+			errs = append(errs, fmt.Errorf("new %s branches added: %s", composite.keyword, branchesString(added)))
+		}
+	}
+
+	return handled(), errors.Join(errs...)
+}
+
+// compositeBranchPathRegexp matches schema paths
+// that go through branches of composite schemas
+var compositeBranchPathRegexp = regexp.MustCompile(`\.(allOf|anyOf|oneOf)\[\d+\]|\.not(\.|$)`)
+
+// missingBranches returns branches that are not structurally
+// equal to any of the branches in other list
+func missingBranches(branches, other []v1.JSONSchemaProps) []v1.JSONSchemaProps {
+	var result []v1.JSONSchemaProps
+	for _, branch := range branches {
+		found := false
+		for _, otherBranch := range other {
+			if reflect.DeepEqual(branch, otherBranch) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, branch)
+		}
+	}
+	return result
+}
+
+func branchesString(branches []v1.JSONSchemaProps) string {
+	bs, err := json.Marshal(branches)
+	if err != nil {
+		return fmt.Sprintf("%+v", branches)
+	}
+	return string(bs)
+}
+
+// Severity represents how a failed validation should be treated
+type Severity string
+
+const (
+	// SeverityError causes a failed validation to be reported as an error
+	SeverityError Severity = "error"
+	// SeverityWarning causes a failed validation to be reported as a warning
+	SeverityWarning Severity = "warning"
+)
+
+// SeverityFunc is a function that accepts the field path and
+// error of a failed validation and returns the Severity that
+// failure should be reported with
+type SeverityFunc func(fieldPath string, err error) Severity
+
 // ChangeValidator is a Validation implementation focused on
 // handling updates to existing fields in a CRD
 type ChangeValidator struct {
 	// Validations is a slice of ChangeValidations
 	// to run against each changed field
 	Validations []ChangeValidation
+
+	// SpecOnly limits validations to fields under "^.spec".
+	// Changes to all other fields (e.g. "^.status", which
+	// is controller owned) are not validated
+	SpecOnly bool
+
+	// IgnorePaths excludes fields matching any of the given
+	// glob patterns (e.g. "^.status.*") from validation.
+	// "*" matches any sequence of characters, including "."
+	IgnorePaths []string
+
+	// SeverityFunc is consulted when a validation fails
+	// to determine if the failure should be reported as an error
+	// or a warning. Defaults to always reporting errors
+	SeverityFunc SeverityFunc
+
+	// ReportRemovedVersions processes versions missing from the new CRD
+	// as full removals, reporting each field the removed version contained
+	// (e.g. for auditing). By default removed versions are skipped
+	ReportRemovedVersions bool
 }
 
 func (cv *ChangeValidator) Name() string {
@@ -425,36 +851,103 @@ func (cv *ChangeValidator) Name() string {
 // each version of a CRD. As such the following is assumed:
 // - Validating the removal of versions during an update is handled outside of this
 // validator. If a version in the existing version of the CRD does not exist in the new
-// version that version of the CRD is skipped in this validator.
+// version that version of the CRD is skipped in this validator, unless that version
+// was both served and the storage version, which is always unsafe. When ReportRemovedVersions
+// is set, each field of the removed version is additionally reported.
 // - Removal of existing fields is unsafe. Regardless of whether or not this is handled
 // by a validator outside this one, if a field is present in a version provided by the existing CRD
 // but not present in the same version provided by the new CRD this validation will fail.
 //
 // Additionally, any changes that are not validated and handled by the known ChangeValidations
-// are deemed as unsafe and returns an error.
+// are deemed as unsafe and returns an error. Fields only present in the new CRD (e.g. fields
+// added under an ancestor with x-kubernetes-preserve-unknown-fields) are not validated themselves
+// since they did not previously exist; only resulting changes to existing fields (e.g. required list)
+// are validated.
+//
+// Failures downgraded to warnings via SeverityFunc are not returned; use ValidateWithWarnings
+// to retrieve them.
 func (cv *ChangeValidator) Validate(old, new v1.CustomResourceDefinition) error {
+	_, err := cv.ValidateWithWarnings(old, new)
+	return err
+}
+
+// ValidateWithWarnings performs the same validation as Validate,
+// additionally returning failures that SeverityFunc downgraded to warnings
+func (cv *ChangeValidator) ValidateWithWarnings(old, new v1.CustomResourceDefinition) ([]error, error) {
 	errs := []error{}
+	warnings := []error{}
+
+	report := func(field string, err error) {
+		if cv.SeverityFunc != nil && cv.SeverityFunc(field, err) == SeverityWarning {
+			warnings = append(warnings, err)
+			return
+		}
+		errs = append(errs, err)
+	}
+
+	ignored := ignorePathsMatcher(cv.IgnorePaths)
+
+	// Deprecated preserveUnknownFields disables structural schema
+	// enforcement, hence it should never be re-enabled
+	if !old.Spec.PreserveUnknownFields && new.Spec.PreserveUnknownFields {
+		errs = append(errs, errors.New("spec.preserveUnknownFields changed from false to true"))
+	}
+
 	for _, version := range old.Spec.Versions {
 		newVersion := manifestcomparators.GetVersionByName(&new, version.Name)
 		if newVersion == nil {
-			// if the new version doesn't exist skip this version
+			// Removing version that is both served and used for storage
+			// leaves existing resources inaccessible, hence it is always
+			// reported as an error regardless of SeverityFunc
+			if version.Served && version.Storage {
+				errs = append(errs, fmt.Errorf("version %q removed while it was served and the storage version, "+
+					"existing resources stored in this version will become inaccessible", version.Name))
+			}
+			if cv.ReportRemovedVersions {
+				for _, field := range removedVersionFields(version) {
+					if cv.SpecOnly && !isSpecField(field) || ignored(field) {
+						continue
+					}
+					report(field, &FieldError{Version: version.Name, Field: field, Err: errors.New("field removed along with version")})
+				}
+			}
+			// otherwise if the new version doesn't exist skip this version
 			continue
 		}
+
+		for _, jsonPath := range removedSelectableFields(version.SelectableFields, newVersion.SelectableFields) {
+			field := "^" + jsonPath
+			if cv.SpecOnly && !isSpecField(field) || ignored(field) {
+				continue
+			}
+			report(field, fmt.Errorf("version %q, selectable field %q removed", version.Name, jsonPath))
+		}
+
 		flatOld := FlattenSchema(version.Schema.OpenAPIV3Schema)
 		flatNew := FlattenSchema(newVersion.Schema.OpenAPIV3Schema)
 
 		diffs, err := CalculateFlatSchemaDiff(flatOld, flatNew)
 		if err != nil {
-			errs = append(errs, fmt.Errorf("calculating schema diff for CRD version %q", version.Name))
+			errs = append(errs, fmt.Errorf("calculating schema diff for CRD version %q: %w", version.Name, err))
 			continue
 		}
 
+		for field := range diffs {
+			if ignored(field) {
+				delete(diffs, field)
+			}
+		}
+
 		for field, diff := range diffs {
+			if cv.SpecOnly && !isSpecField(field) {
+				continue
+			}
+
 			handled := false
 			for _, validation := range cv.Validations {
 				ok, err := validation(diff)
 				if err != nil {
-					errs = append(errs, fmt.Errorf("version %q, field %q: %w", version.Name, field, err))
+					report(field, &FieldError{Version: version.Name, Field: field, Err: err})
 				}
 				if ok {
 					handled = true
@@ -463,20 +956,82 @@ func (cv *ChangeValidator) Validate(old, new v1.CustomResourceDefinition) error
 			}
 
 			if !handled {
-				errs = append(errs, fmt.Errorf("version %q, field %q has unknown change, refusing to determine that change is safe", version.Name, field))
+				report(field, &FieldError{Version: version.Name, Field: field,
+					Err: errors.New("unknown change, refusing to determine that change is safe")})
 			}
 		}
 	}
 
 	if len(errs) > 0 {
-		return errors.Join(errs...)
+		return warnings, errors.Join(errs...)
+	}
+	return warnings, nil
+}
+
+// removedVersionFields returns sorted flattened
+// paths of all fields contained in version schema
+func removedVersionFields(version v1.CustomResourceDefinitionVersion) []string {
+	if version.Schema == nil {
+		return nil
+	}
+	var fields []string
+	for field := range FlattenSchema(version.Schema.OpenAPIV3Schema) {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// removedSelectableFields returns JSONPaths of selectable fields
+// that are present in old but missing from new
+func removedSelectableFields(old, new []v1.SelectableField) []string {
+	newPaths := map[string]struct{}{}
+	for _, field := range new {
+		newPaths[field.JSONPath] = struct{}{}
+	}
+
+	var removed []string
+	for _, field := range old {
+		if _, found := newPaths[field.JSONPath]; !found {
+			removed = append(removed, field.JSONPath)
+		}
+	}
+	return removed
+}
+
+func isSpecField(field string) bool {
+	return field == "^.spec" || strings.HasPrefix(field, "^.spec.")
+}
+
+// ignorePathsMatcher returns a func reporting whether a field
+// matches any of the given glob patterns
+func ignorePathsMatcher(patterns []string) func(string) bool {
+	var regexps []*regexp.Regexp
+	for _, pattern := range patterns {
+		expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+		regexps = append(regexps, regexp.MustCompile("^"+expr+"$"))
+	}
+
+	return func(field string) bool {
+		for _, re := range regexps {
+			if re.MatchString(field) {
+				return true
+			}
+		}
+		return false
 	}
-	return nil
 }
 
 type FieldDiff struct {
-	Old *v1.JSONSchemaProps
-	New *v1.JSONSchemaProps
+	// Field is the flattened path of the field (e.g. "^.spec.foo")
+	Field string
+	Old   *v1.JSONSchemaProps
+	New   *v1.JSONSchemaProps
+	// NewWithProperties is the new schema of the field including its
+	// child properties. Since the required list lives on the parent of the
+	// properties it references, validations can use it to inspect
+	// schemas (e.g. defaults) of the required properties.
+	NewWithProperties *v1.JSONSchemaProps
 }
 
 // FlatSchema is a flat representation of a CRD schema.
@@ -506,7 +1061,12 @@ type FlatSchema map[string]*v1.JSONSchemaProps
 //	   "^.spec.bar": {},
 //	}
 //
-// where "^" represents the "root" schema
+// where "^" represents the "root" schema.
+//
+// Branches of composite schemas (allOf, anyOf, oneOf, not) share
+// location with the schema that contains them, hence they are
+// kept as part of that schema and only contribute fields
+// at locations that are not otherwise defined.
 func FlattenSchema(schema *v1.JSONSchemaProps) FlatSchema {
 	fieldMap := map[string]*v1.JSONSchemaProps{}
 
@@ -514,8 +1074,12 @@ func FlattenSchema(schema *v1.JSONSchemaProps) FlatSchema {
 		field.NewPath("^"),
 		field.NewPath("^"),
 		nil,
-		func(s *v1.JSONSchemaProps, _, simpleLocation *field.Path, _ []*v1.JSONSchemaProps) bool {
-			fieldMap[simpleLocation.String()] = s.DeepCopy()
+		func(s *v1.JSONSchemaProps, fldPath, simpleLocation *field.Path, _ []*v1.JSONSchemaProps) bool {
+			location := simpleLocation.String()
+			if _, found := fieldMap[location]; found && compositeBranchPathRegexp.MatchString(fldPath.String()) {
+				return false
+			}
+			fieldMap[location] = s.DeepCopy()
 			return false
 		})
 
@@ -524,13 +1088,21 @@ func FlattenSchema(schema *v1.JSONSchemaProps) FlatSchema {
 
 // CalculateFlatSchemaDiff finds fields in a FlatSchema that are different
 // and returns a mapping of field --> old and new field schemas. If a field
-// exists in the old FlatSchema but not the new an empty diff mapping and an error is returned.
+// exists in the old FlatSchema but not the new an empty diff mapping and an error
+// listing all removed fields (grouped by removed subtree) is returned.
 func CalculateFlatSchemaDiff(o, n FlatSchema) (map[string]FieldDiff, error) {
-	diffMap := map[string]FieldDiff{}
-	for field, schema := range o {
+	var removed []string
+	for field := range o {
 		if _, ok := n[field]; !ok {
-			return diffMap, fmt.Errorf("field %q in existing not found in new", field)
+			removed = append(removed, field)
 		}
+	}
+	if len(removed) > 0 {
+		return map[string]FieldDiff{}, removedFieldsError(removed)
+	}
+
+	diffMap := map[string]FieldDiff{}
+	for field, schema := range o {
 		newSchema := n[field]
 
 		// Copy the schemas and remove any child properties for comparison.
@@ -544,10 +1116,51 @@ func CalculateFlatSchemaDiff(o, n FlatSchema) (map[string]FieldDiff, error) {
 		newCopy.Properties = nil
 		if !reflect.DeepEqual(oldCopy, newCopy) {
 			diffMap[field] = FieldDiff{
-				Old: oldCopy,
-				New: newCopy,
+				Field:             field,
+				Old:               oldCopy,
+				New:               newCopy,
+				NewWithProperties: newSchema.DeepCopy(),
 			}
 		}
 	}
 	return diffMap, nil
 }
+
+// removedFieldsError groups removed fields by their top most
+// removed ancestor so that whole removed subtrees are reported together
+func removedFieldsError(removed []string) error {
+	sort.Strings(removed)
+
+	var roots []string
+	descendants := map[string][]string{}
+
+	for _, field := range removed {
+		var root string
+		for _, r := range roots {
+			if strings.HasPrefix(field, r+".") || strings.HasPrefix(field, r+"[") {
+				root = r
+				break
+			}
+		}
+		if root == "" {
+			roots = append(roots, field)
+			continue
+		}
+		descendants[root] = append(descendants[root], field)
+	}
+
+	var descs []string
+	for _, root := range roots {
+		desc := fmt.Sprintf("%q", root)
+		if len(descendants[root]) > 0 {
+			var quoted []string
+			for _, field := range descendants[root] {
+				quoted = append(quoted, fmt.Sprintf("%q", field))
+			}
+			desc += fmt.Sprintf(" (including %s)", strings.Join(quoted, ", "))
+		}
+		descs = append(descs, desc)
+	}
+
+	return fmt.Errorf("fields in existing not found in new: %s", strings.Join(descs, ", "))
+}
@@ -5,10 +5,13 @@ package crdupgradesafety_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"carvel.dev/kapp/pkg/kapp/crdupgradesafety"
+	"carvel.dev/kapp/pkg/kapp/preflight"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/utils/pointer"
 )
@@ -134,6 +137,53 @@ func TestEnumChangeValidation(t *testing.T) {
 	}
 }
 
+func TestEnumChangeValidationWithAllowedAdditions(t *testing.T) {
+	validation := crdupgradesafety.EnumChangeValidationWithAllowedAdditions([]string{"^.spec.mode"})
+
+	for _, tc := range []struct {
+		name         string
+		diff         crdupgradesafety.FieldDiff
+		shouldError  bool
+		shouldHandle bool
+	}{
+		{
+			name: "enums added to allowed field, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Field: "^.spec.mode",
+				Old:   &v1.JSONSchemaProps{},
+				New:   &v1.JSONSchemaProps{Enum: []v1.JSON{{Raw: []byte("foo")}}},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "enums added to other field, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Field: "^.spec.other",
+				Old:   &v1.JSONSchemaProps{},
+				New:   &v1.JSONSchemaProps{Enum: []v1.JSON{{Raw: []byte("foo")}}},
+			},
+			shouldError:  true,
+			shouldHandle: true,
+		},
+		{
+			name: "enums removed from allowed field, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Field: "^.spec.mode",
+				Old:   &v1.JSONSchemaProps{Enum: []v1.JSON{{Raw: []byte("foo")}, {Raw: []byte("bar")}}},
+				New:   &v1.JSONSchemaProps{Enum: []v1.JSON{{Raw: []byte("foo")}}},
+			},
+			shouldError:  true,
+			shouldHandle: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handled, err := validation(tc.diff)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			assert.Equal(t, tc.shouldHandle, handled, "should be handled? - %v", tc.shouldHandle)
+		})
+	}
+}
+
 func TestCalculateFlatSchemaDiff(t *testing.T) {
 	for _, tc := range []struct {
 		name         string
@@ -164,8 +214,10 @@ func TestCalculateFlatSchemaDiff(t *testing.T) {
 			},
 			expectedDiff: map[string]crdupgradesafety.FieldDiff{
 				"foo": {
-					Old: &v1.JSONSchemaProps{},
-					New: &v1.JSONSchemaProps{ID: "bar"},
+					Field:             "foo",
+					Old:               &v1.JSONSchemaProps{},
+					New:               &v1.JSONSchemaProps{ID: "bar"},
+					NewWithProperties: &v1.JSONSchemaProps{ID: "bar"},
 				},
 			},
 		},
@@ -207,6 +259,43 @@ func TestCalculateFlatSchemaDiff(t *testing.T) {
 	}
 }
 
+func TestCalculateFlatSchemaDiffRemovedSubtree(t *testing.T) {
+	old := crdupgradesafety.FlattenSchema(&v1.JSONSchemaProps{
+		Properties: map[string]v1.JSONSchemaProps{
+			"spec": {
+				Properties: map[string]v1.JSONSchemaProps{
+					"foo": {
+						Properties: map[string]v1.JSONSchemaProps{
+							"bar": {},
+							"baz": {
+								Properties: map[string]v1.JSONSchemaProps{
+									"qux": {},
+								},
+							},
+						},
+					},
+					"keep":  {},
+					"other": {},
+				},
+			},
+		},
+	})
+	new := crdupgradesafety.FlattenSchema(&v1.JSONSchemaProps{
+		Properties: map[string]v1.JSONSchemaProps{
+			"spec": {
+				Properties: map[string]v1.JSONSchemaProps{
+					"keep": {},
+				},
+			},
+		},
+	})
+
+	diff, err := crdupgradesafety.CalculateFlatSchemaDiff(old, new)
+	require.EqualError(t, err, `fields in existing not found in new: `+
+		`"^.spec.foo" (including "^.spec.foo.bar", "^.spec.foo.baz", "^.spec.foo.baz.qux"), "^.spec.other"`)
+	assert.Empty(t, diff)
+}
+
 func TestFlattenSchema(t *testing.T) {
 	schema := &v1.JSONSchemaProps{
 		Properties: map[string]v1.JSONSchemaProps{
@@ -234,6 +323,31 @@ func TestFlattenSchema(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestFlattenSchemaCompositeBranches(t *testing.T) {
+	schema := &v1.JSONSchemaProps{
+		Properties: map[string]v1.JSONSchemaProps{
+			"foo": {
+				AnyOf: []v1.JSONSchemaProps{
+					{Type: "string"},
+					{Type: "object", Properties: map[string]v1.JSONSchemaProps{"bar": {Type: "integer"}}},
+				},
+			},
+		},
+	}
+
+	foo := schema.Properties["foo"]
+	foobar := schema.Properties["foo"].AnyOf[1].Properties["bar"]
+	expected := crdupgradesafety.FlatSchema{
+		"^":         schema,
+		"^.foo":     &foo,
+		"^.foo.bar": &foobar,
+	}
+
+	actual := crdupgradesafety.FlattenSchema(schema)
+
+	assert.Equal(t, expected, actual)
+}
+
 func TestChangeValidator(t *testing.T) {
 	for _, tc := range []struct {
 		name            string
@@ -1315,3 +1429,1228 @@ func TestDefaultChangeValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultChangeValidationObjectDefaults(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		oldDefault string
+		newDefault string
+		err        string
+	}{
+		{
+			name:       "object default added",
+			newDefault: `{"limits":{"cpu":"1"}}`,
+			err:        `new value added as default when previously no default value existed: {"limits":{"cpu":"1"}}`,
+		},
+		{
+			name:       "object default with reordered keys and formatting, no error",
+			oldDefault: `{"limits":{"cpu":"1","memory":"1Gi"},"enabled":true}`,
+			newDefault: `{"enabled": true, "limits": {"memory": "1Gi", "cpu": "1"}}`,
+		},
+		{
+			name:       "nested key within object default changed",
+			oldDefault: `{"limits":{"cpu":"1","memory":"1Gi"},"enabled":true}`,
+			newDefault: `{"limits":{"cpu":"2","memory":"1Gi"},"enabled":true}`,
+			err: `default value has been changed from {"limits":{"cpu":"1","memory":"1Gi"},"enabled":true} ` +
+				`to {"limits":{"cpu":"2","memory":"1Gi"},"enabled":true} (changed keys: .limits.cpu)`,
+		},
+		{
+			name:       "nested keys within object default added and removed",
+			oldDefault: `{"limits":{"cpu":"1"}}`,
+			newDefault: `{"limits":{"memory":"1Gi"},"enabled":true}`,
+			err: `default value has been changed from {"limits":{"cpu":"1"}} ` +
+				`to {"limits":{"memory":"1Gi"},"enabled":true} (changed keys: .enabled, .limits.cpu, .limits.memory)`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			diff := crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "object"},
+				New: &v1.JSONSchemaProps{Type: "object"},
+			}
+			if tc.oldDefault != "" {
+				diff.Old.Default = &v1.JSON{Raw: []byte(tc.oldDefault)}
+			}
+			if tc.newDefault != "" {
+				diff.New.Default = &v1.JSON{Raw: []byte(tc.newDefault)}
+			}
+
+			handled, err := crdupgradesafety.DefaultValueChangeValidation(diff)
+			assert.True(t, handled)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestTransitionRuleChangeValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		diff          crdupgradesafety.FieldDiff
+		shouldError   bool
+		shouldHandle  bool
+		expectedRules v1.ValidationRules
+	}{
+		{
+			name: "no change in transition rules, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self == oldSelf"}},
+				},
+				New: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self == oldSelf"}},
+				},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "transition rule added, no other changes, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{},
+				New: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self == oldSelf", Message: "immutable"}},
+				},
+			},
+			shouldHandle: true,
+			shouldError:  true,
+		},
+		{
+			name: "transition rule removed, no other changes, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self == oldSelf"}},
+				},
+				New: &v1.JSONSchemaProps{},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "plain validation rule added, no error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{},
+				New: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self.size() < 10"}},
+				},
+			},
+			expectedRules: v1.ValidationRules{{Rule: "self.size() < 10"}},
+		},
+		{
+			name: "rule referencing a field named similar to oldSelf is not a transition rule, no error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{},
+				New: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self.oldSelfName != ''"}},
+				},
+			},
+			expectedRules: v1.ValidationRules{{Rule: "self.oldSelfName != ''"}},
+		},
+		{
+			name: "transition rule added alongside plain rule, error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{},
+				New: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{
+						{Rule: "self.size() < 10"},
+						{Rule: "!has(oldSelf.foo) || has(self.foo)"},
+					},
+				},
+			},
+			shouldError:   true,
+			expectedRules: v1.ValidationRules{{Rule: "self.size() < 10"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handled, err := crdupgradesafety.TransitionRuleChangeValidation(tc.diff)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			assert.Equal(t, tc.shouldHandle, handled, "should be handled? - %v", tc.shouldHandle)
+			assert.Empty(t, tc.diff.Old.XValidations)
+			assert.Equal(t, tc.expectedRules, tc.diff.New.XValidations)
+		})
+	}
+}
+
+func TestTransitionRuleRemovalChangeValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		diff         crdupgradesafety.FieldDiff
+		err          string
+		shouldHandle bool
+	}{
+		{
+			name: "transition rule removed, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self == oldSelf", Message: "immutable"}},
+				},
+				New: &v1.JSONSchemaProps{},
+			},
+			err:          "transition rules removed: [self == oldSelf]",
+			shouldHandle: true,
+		},
+		{
+			name: "transition rule replaced, error for both, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self == oldSelf"}},
+				},
+				New: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self >= oldSelf"}},
+				},
+			},
+			err:          "new transition rules added: [self >= oldSelf]\ntransition rules removed: [self == oldSelf]",
+			shouldHandle: true,
+		},
+		{
+			name: "plain validation rule removed, no error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{
+					XValidations: v1.ValidationRules{{Rule: "self.size() < 10"}},
+				},
+				New: &v1.JSONSchemaProps{},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handled, err := crdupgradesafety.TransitionRuleRemovalChangeValidation(tc.diff)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+			assert.Equal(t, tc.shouldHandle, handled, "should be handled? - %v", tc.shouldHandle)
+		})
+	}
+}
+
+func TestCompositeBranchChangeValidation(t *testing.T) {
+	str := v1.JSONSchemaProps{Type: "string"}
+	integer := v1.JSONSchemaProps{Type: "integer"}
+	boolean := v1.JSONSchemaProps{Type: "boolean"}
+
+	for _, tc := range []struct {
+		name         string
+		diff         crdupgradesafety.FieldDiff
+		err          string
+		shouldHandle bool
+	}{
+		{
+			name: "anyOf branches reordered, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{str, integer}},
+				New: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{integer, str}},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "anyOf branch added, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{str, integer}},
+				New: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{str, integer, boolean}},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "anyOf branch removed, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{str, integer}},
+				New: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{integer}},
+			},
+			err:          `anyOf branches removed: [{"type":"string"}]`,
+			shouldHandle: true,
+		},
+		{
+			name: "anyOf branch changed, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{str, integer}},
+				New: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{{Type: "string", MaxLength: pointer.Int64(10)}, integer}},
+			},
+			err:          `anyOf branches removed: [{"type":"string"}]`,
+			shouldHandle: true,
+		},
+		{
+			name: "oneOf branch removed, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{OneOf: []v1.JSONSchemaProps{str, integer}},
+				New: &v1.JSONSchemaProps{OneOf: []v1.JSONSchemaProps{str}},
+			},
+			err:          `oneOf branches removed: [{"type":"integer"}]`,
+			shouldHandle: true,
+		},
+		{
+			name: "oneOf branch added, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{OneOf: []v1.JSONSchemaProps{str}},
+				New: &v1.JSONSchemaProps{OneOf: []v1.JSONSchemaProps{str, integer}},
+			},
+			err:          `new oneOf branches added: [{"type":"integer"}]`,
+			shouldHandle: true,
+		},
+		{
+			name: "anyOf added when none existed, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{},
+				New: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{str}},
+			},
+			err:          `anyOf added when previously no branches existed: [{"type":"string"}]`,
+			shouldHandle: true,
+		},
+		{
+			name: "anyOf removed entirely, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{str, integer}},
+				New: &v1.JSONSchemaProps{},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "anyOf branch added alongside other changes, no error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{str}},
+				New: &v1.JSONSchemaProps{AnyOf: []v1.JSONSchemaProps{str, integer}, Description: "new"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handled, err := crdupgradesafety.CompositeBranchChangeValidation(tc.diff)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+			assert.Equal(t, tc.shouldHandle, handled, "should be handled? - %v", tc.shouldHandle)
+		})
+	}
+}
+
+func TestChangeValidatorCompositeBranches(t *testing.T) {
+	crd := func(branches ...v1.JSONSchemaProps) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]v1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]v1.JSONSchemaProps{
+										"port": {XIntOrString: true, AnyOf: branches},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		}
+	}
+	str := v1.JSONSchemaProps{Type: "string"}
+	integer := v1.JSONSchemaProps{Type: "integer"}
+
+	validator := crdupgradesafety.NewDefaultValidator()
+
+	require.NoError(t, validator.Validate(crd(str, integer), crd(integer, str)))
+
+	err := validator.Validate(crd(str, integer), crd(integer))
+	require.ErrorContains(t, err, `version "v1", field "^.spec.port": anyOf branches removed: [{"type":"string"}]`)
+}
+
+func TestChangeValidatorSpecOnly(t *testing.T) {
+	crdWithMaxLength := func(specMaxLength, statusMaxLength int64) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &v1.CustomResourceValidation{
+							OpenAPIV3Schema: &v1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]v1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"name": {Type: "string", MaxLength: pointer.Int64(specMaxLength)},
+										},
+									},
+									"status": {
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"name": {Type: "string", MaxLength: pointer.Int64(statusMaxLength)},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		specOnly    bool
+		old         v1.CustomResourceDefinition
+		new         v1.CustomResourceDefinition
+		shouldError bool
+	}{
+		{
+			name:        "status field tightened, spec only not set, error",
+			old:         crdWithMaxLength(10, 10),
+			new:         crdWithMaxLength(10, 5),
+			shouldError: true,
+		},
+		{
+			name:     "status field tightened, spec only set, no error",
+			specOnly: true,
+			old:      crdWithMaxLength(10, 10),
+			new:      crdWithMaxLength(10, 5),
+		},
+		{
+			name:        "spec field tightened, spec only set, error",
+			specOnly:    true,
+			old:         crdWithMaxLength(10, 10),
+			new:         crdWithMaxLength(5, 10),
+			shouldError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			changeValidator := &crdupgradesafety.ChangeValidator{
+				Validations: []crdupgradesafety.ChangeValidation{
+					crdupgradesafety.MaximumLengthChangeValidation,
+				},
+				SpecOnly: tc.specOnly,
+			}
+			err := changeValidator.Validate(tc.old, tc.new)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+		})
+	}
+}
+
+func TestChangeValidatorSeverityFunc(t *testing.T) {
+	crdWithMaxLength := func(experimentalMaxLength, nameMaxLength int64) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &v1.CustomResourceValidation{
+							OpenAPIV3Schema: &v1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]v1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"experimental": {
+												Type: "object",
+												Properties: map[string]v1.JSONSchemaProps{
+													"flag": {Type: "string", MaxLength: pointer.Int64(experimentalMaxLength)},
+												},
+											},
+											"name": {Type: "string", MaxLength: pointer.Int64(nameMaxLength)},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	experimentalAsWarning := func(fieldPath string, _ error) crdupgradesafety.Severity {
+		if strings.HasPrefix(fieldPath, "^.spec.experimental") {
+			return crdupgradesafety.SeverityWarning
+		}
+		return crdupgradesafety.SeverityError
+	}
+
+	for _, tc := range []struct {
+		name             string
+		severityFunc     crdupgradesafety.SeverityFunc
+		old              v1.CustomResourceDefinition
+		new              v1.CustomResourceDefinition
+		shouldError      bool
+		expectedWarnings int
+	}{
+		{
+			name:        "experimental field tightened, no severity func, error",
+			old:         crdWithMaxLength(10, 10),
+			new:         crdWithMaxLength(5, 10),
+			shouldError: true,
+		},
+		{
+			name:             "experimental field tightened, downgraded to warning, no error",
+			severityFunc:     experimentalAsWarning,
+			old:              crdWithMaxLength(10, 10),
+			new:              crdWithMaxLength(5, 10),
+			expectedWarnings: 1,
+		},
+		{
+			name:             "experimental and other field tightened, only experimental downgraded to warning, error",
+			severityFunc:     experimentalAsWarning,
+			old:              crdWithMaxLength(10, 10),
+			new:              crdWithMaxLength(5, 5),
+			shouldError:      true,
+			expectedWarnings: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			changeValidator := &crdupgradesafety.ChangeValidator{
+				Validations: []crdupgradesafety.ChangeValidation{
+					crdupgradesafety.MaximumLengthChangeValidation,
+				},
+				SeverityFunc: tc.severityFunc,
+			}
+			warnings, err := changeValidator.ValidateWithWarnings(tc.old, tc.new)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			assert.Len(t, warnings, tc.expectedWarnings)
+			for _, warning := range warnings {
+				assert.Contains(t, warning.Error(), "^.spec.experimental.flag")
+			}
+		})
+	}
+}
+
+func TestChangeValidatorSelectableFields(t *testing.T) {
+	crdWithSelectableFields := func(jsonPaths ...string) v1.CustomResourceDefinition {
+		selectableFields := []v1.SelectableField{}
+		for _, jsonPath := range jsonPaths {
+			selectableFields = append(selectableFields, v1.SelectableField{JSONPath: jsonPath})
+		}
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &v1.CustomResourceValidation{
+							OpenAPIV3Schema: &v1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]v1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"color": {Type: "string"},
+											"size":  {Type: "string"},
+										},
+									},
+								},
+							},
+						},
+						SelectableFields: selectableFields,
+					},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		old         v1.CustomResourceDefinition
+		new         v1.CustomResourceDefinition
+		shouldError bool
+	}{
+		{
+			name: "no selectable fields changes, no error",
+			old:  crdWithSelectableFields(".spec.color"),
+			new:  crdWithSelectableFields(".spec.color"),
+		},
+		{
+			name: "selectable field added, no error",
+			old:  crdWithSelectableFields(".spec.color"),
+			new:  crdWithSelectableFields(".spec.color", ".spec.size"),
+		},
+		{
+			name:        "selectable field removed, error",
+			old:         crdWithSelectableFields(".spec.color", ".spec.size"),
+			new:         crdWithSelectableFields(".spec.color"),
+			shouldError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			changeValidator := &crdupgradesafety.ChangeValidator{}
+			err := changeValidator.Validate(tc.old, tc.new)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			if tc.shouldError {
+				assert.ErrorContains(t, err, `version "v1alpha1", selectable field ".spec.size" removed`)
+			}
+		})
+	}
+}
+
+func TestRequiredFieldWithDefaultChangeValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		diff         crdupgradesafety.FieldDiff
+		shouldError  bool
+		shouldHandle bool
+	}{
+		{
+			name: "new required field with default, no other changes, should be handled, no error",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Required: []string{"foo"}},
+				New: &v1.JSONSchemaProps{Required: []string{"foo", "bar"}},
+				NewWithProperties: &v1.JSONSchemaProps{
+					Required: []string{"foo", "bar"},
+					Properties: map[string]v1.JSONSchemaProps{
+						"foo": {Type: "string"},
+						"bar": {Type: "string", Default: &v1.JSON{Raw: []byte(`"baz"`)}},
+					},
+				},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "new required field without default, no other changes, should be handled, error",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Required: []string{"foo"}},
+				New: &v1.JSONSchemaProps{Required: []string{"foo", "bar"}},
+				NewWithProperties: &v1.JSONSchemaProps{
+					Required: []string{"foo", "bar"},
+					Properties: map[string]v1.JSONSchemaProps{
+						"foo": {Type: "string", Default: &v1.JSON{Raw: []byte(`"foo"`)}},
+						"bar": {Type: "string"},
+					},
+				},
+			},
+			shouldError:  true,
+			shouldHandle: true,
+		},
+		{
+			name: "required fields with defaults added when none existed, should be handled, no error",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{},
+				New: &v1.JSONSchemaProps{Required: []string{"bar"}},
+				NewWithProperties: &v1.JSONSchemaProps{
+					Required: []string{"bar"},
+					Properties: map[string]v1.JSONSchemaProps{
+						"bar": {Type: "integer", Default: &v1.JSON{Raw: []byte(`1`)}},
+					},
+				},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "required field added without parent schema, should be handled, error",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Required: []string{"foo"}},
+				New: &v1.JSONSchemaProps{Required: []string{"foo", "bar"}},
+			},
+			shouldError:  true,
+			shouldHandle: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handled, err := crdupgradesafety.RequiredFieldWithDefaultChangeValidation(tc.diff)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			assert.Equal(t, tc.shouldHandle, handled, "should be handled? - %v", tc.shouldHandle)
+		})
+	}
+}
+
+func TestRequiredFieldWithDefaultFlattenedPaths(t *testing.T) {
+	crd := func(required []string, withDefault bool) v1.CustomResourceDefinition {
+		nested := v1.JSONSchemaProps{Type: "string"}
+		if withDefault {
+			nested.Default = &v1.JSON{Raw: []byte(`"value"`)}
+		}
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &v1.CustomResourceValidation{
+							OpenAPIV3Schema: &v1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]v1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"config": {
+												Type:     "object",
+												Required: required,
+												Properties: map[string]v1.JSONSchemaProps{
+													"existing": {Type: "string"},
+													"nested":   nested,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	changeValidator := &crdupgradesafety.ChangeValidator{
+		Validations: []crdupgradesafety.ChangeValidation{
+			crdupgradesafety.RequiredFieldWithDefaultChangeValidation,
+			crdupgradesafety.DefaultValueChangeValidation,
+		},
+	}
+
+	// default of ^.spec.config.nested is found via parent ^.spec.config schema
+	err := changeValidator.Validate(crd([]string{"existing"}, true), crd([]string{"existing", "nested"}, true))
+	assert.NoError(t, err)
+
+	err = changeValidator.Validate(crd([]string{"existing"}, false), crd([]string{"existing", "nested"}, false))
+	assert.ErrorContains(t, err, `version "v1alpha1", field "^.spec.config": new required fields added: [nested]`)
+}
+
+func TestAdditionalPropertiesChangeValidation(t *testing.T) {
+	typedSchema := func(typ string) *v1.JSONSchemaPropsOrBool {
+		return &v1.JSONSchemaPropsOrBool{Allows: true, Schema: &v1.JSONSchemaProps{Type: typ}}
+	}
+
+	for _, tc := range []struct {
+		name         string
+		diff         crdupgradesafety.FieldDiff
+		shouldError  bool
+		shouldHandle bool
+	}{
+		{
+			name: "no change in additionalProperties, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AdditionalProperties: &v1.JSONSchemaPropsOrBool{Allows: true}},
+				New: &v1.JSONSchemaProps{AdditionalProperties: &v1.JSONSchemaPropsOrBool{Allows: true}},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "additionalProperties narrowed from true to a typed schema, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AdditionalProperties: &v1.JSONSchemaPropsOrBool{Allows: true}},
+				New: &v1.JSONSchemaProps{AdditionalProperties: typedSchema("string")},
+			},
+			shouldError:  true,
+			shouldHandle: true,
+		},
+		{
+			name: "additionalProperties schema changed, no error, marked as handled (checked via flattened field)",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AdditionalProperties: typedSchema("string")},
+				New: &v1.JSONSchemaProps{AdditionalProperties: typedSchema("integer")},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "additionalProperties narrowed from true to a typed schema with other changes, error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AdditionalProperties: &v1.JSONSchemaPropsOrBool{Allows: true}},
+				New: &v1.JSONSchemaProps{
+					AdditionalProperties: typedSchema("string"),
+					Description:          "changed",
+				},
+			},
+			shouldError: true,
+		},
+		{
+			name: "additionalProperties disallowed, no error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{AdditionalProperties: &v1.JSONSchemaPropsOrBool{Allows: true}},
+				New: &v1.JSONSchemaProps{AdditionalProperties: &v1.JSONSchemaPropsOrBool{Allows: false}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handled, err := crdupgradesafety.AdditionalPropertiesChangeValidation(tc.diff)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			assert.Equal(t, tc.shouldHandle, handled, "should be handled? - %v", tc.shouldHandle)
+		})
+	}
+}
+
+func TestEnumAdditionAllowedFieldsConfig(t *testing.T) {
+	crd := func(enum []v1.JSON) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &v1.CustomResourceValidation{
+							OpenAPIV3Schema: &v1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]v1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"mode":  {Type: "string", Enum: enum},
+											"other": {Type: "string", Enum: enum},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	validator := crdupgradesafety.NewValidatorWithConfig(crdupgradesafety.PreflightConfig{
+		AllowEnumAdditionFields: []string{"^.spec.mode"},
+	})
+
+	err := validator.Validate(crd(nil), crd([]v1.JSON{{Raw: []byte(`"fast"`)}}))
+	assert.ErrorContains(t, err, `field "^.spec.other": enums added when there were no enum restrictions previously`)
+	assert.NotContains(t, err.Error(), `"^.spec.mode"`)
+}
+
+func TestChangeValidatorPreserveUnknownFields(t *testing.T) {
+	crd := func(preserveUnknownFields bool) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{PreserveUnknownFields: preserveUnknownFields},
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		old         bool
+		new         bool
+		shouldError bool
+	}{
+		{name: "unchanged false, no error", old: false, new: false},
+		{name: "unchanged true, no error", old: true, new: true},
+		{name: "changed from true to false, no error", old: true, new: false},
+		{name: "changed from false to true, error", old: false, new: true, shouldError: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			changeValidator := &crdupgradesafety.ChangeValidator{}
+			err := changeValidator.Validate(crd(tc.old), crd(tc.new))
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			if tc.shouldError {
+				assert.ErrorContains(t, err, "spec.preserveUnknownFields changed from false to true")
+			}
+		})
+	}
+}
+
+func TestChangeValidatorServedStorageVersionRemoved(t *testing.T) {
+	crd := func(versions ...v1.CustomResourceDefinitionVersion) v1.CustomResourceDefinition {
+		for i := range versions {
+			versions[i].Schema = &v1.CustomResourceValidation{OpenAPIV3Schema: &v1.JSONSchemaProps{Type: "object"}}
+		}
+		return v1.CustomResourceDefinition{Spec: v1.CustomResourceDefinitionSpec{Versions: versions}}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		old         v1.CustomResourceDefinition
+		new         v1.CustomResourceDefinition
+		shouldError bool
+	}{
+		{
+			name:        "served and storage version removed, error",
+			old:         crd(v1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true, Storage: true}),
+			new:         crd(v1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true}),
+			shouldError: true,
+		},
+		{
+			name: "served non-storage version removed, no error",
+			old: crd(
+				v1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true},
+				v1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true},
+			),
+			new: crd(v1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true}),
+		},
+		{
+			name: "not served storage version removed, no error",
+			old:  crd(v1.CustomResourceDefinitionVersion{Name: "v1alpha1", Storage: true}),
+			new:  crd(v1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true}),
+		},
+		{
+			name: "served and storage version kept, no error",
+			old:  crd(v1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true}),
+			new:  crd(v1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			changeValidator := &crdupgradesafety.ChangeValidator{
+				SeverityFunc: func(string, error) crdupgradesafety.Severity { return crdupgradesafety.SeverityWarning },
+			}
+			err := changeValidator.Validate(tc.old, tc.new)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			if tc.shouldError {
+				assert.ErrorContains(t, err, `version "v1alpha1" removed while it was served and the storage version`)
+			}
+		})
+	}
+}
+
+func TestChangeValidatorReportRemovedVersions(t *testing.T) {
+	old := v1.CustomResourceDefinition{
+		Spec: v1.CustomResourceDefinitionSpec{
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{
+					Name:   "v1alpha1",
+					Served: true,
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]v1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]v1.JSONSchemaProps{
+										"size": {Type: "integer"},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema:  &v1.CustomResourceValidation{OpenAPIV3Schema: &v1.JSONSchemaProps{Type: "object"}},
+				},
+			},
+		},
+	}
+	new := old.DeepCopy()
+	new.Spec.Versions = new.Spec.Versions[1:]
+
+	require.NoError(t, (&crdupgradesafety.ChangeValidator{}).Validate(old, *new))
+
+	err := (&crdupgradesafety.ChangeValidator{ReportRemovedVersions: true}).Validate(old, *new)
+	require.Error(t, err)
+
+	var fields []string
+	for _, finding := range crdupgradesafety.NewValidationReport(err).Findings {
+		assert.Equal(t, "v1alpha1", finding.Version)
+		assert.Equal(t, "field removed along with version", finding.Message)
+		fields = append(fields, finding.Field)
+	}
+	assert.Equal(t, []string{"^", "^.spec", "^.spec.size"}, fields)
+
+	err = (&crdupgradesafety.ChangeValidator{ReportRemovedVersions: true, SpecOnly: true}).Validate(old, *new)
+	require.Error(t, err)
+	assert.Len(t, crdupgradesafety.NewValidationReport(err).Findings, 2)
+}
+
+func TestChangeValidatorIgnorePaths(t *testing.T) {
+	crdWithMaxLength := func(specMaxLength, statusMaxLength int64) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &v1.CustomResourceValidation{
+							OpenAPIV3Schema: &v1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]v1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"name": {Type: "string", MaxLength: pointer.Int64(specMaxLength)},
+										},
+									},
+									"status": {
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"name": {Type: "string", MaxLength: pointer.Int64(statusMaxLength)},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		ignorePaths []string
+		old         v1.CustomResourceDefinition
+		new         v1.CustomResourceDefinition
+		shouldError bool
+	}{
+		{
+			name:        "status field tightened, no ignored paths, error",
+			old:         crdWithMaxLength(10, 10),
+			new:         crdWithMaxLength(10, 5),
+			shouldError: true,
+		},
+		{
+			name:        "status field tightened, status paths ignored, no error",
+			ignorePaths: []string{"^.status.*"},
+			old:         crdWithMaxLength(10, 10),
+			new:         crdWithMaxLength(10, 5),
+		},
+		{
+			name:        "status field tightened, exact status path ignored, no error",
+			ignorePaths: []string{"^.status.name"},
+			old:         crdWithMaxLength(10, 10),
+			new:         crdWithMaxLength(10, 5),
+		},
+		{
+			name:        "spec field tightened, status paths ignored, error",
+			ignorePaths: []string{"^.status.*"},
+			old:         crdWithMaxLength(10, 10),
+			new:         crdWithMaxLength(5, 10),
+			shouldError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			changeValidator := &crdupgradesafety.ChangeValidator{
+				Validations: []crdupgradesafety.ChangeValidation{
+					crdupgradesafety.MaximumLengthChangeValidation,
+				},
+				IgnorePaths: tc.ignorePaths,
+			}
+			err := changeValidator.Validate(tc.old, tc.new)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+		})
+	}
+}
+
+func TestIntOrStringChangeValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		diff         crdupgradesafety.FieldDiff
+		shouldError  bool
+		shouldHandle bool
+	}{
+		{
+			name: "no change in x-kubernetes-int-or-string, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{XIntOrString: true},
+				New: &v1.JSONSchemaProps{XIntOrString: true},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "no change in x-kubernetes-int-or-string with type change, no error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "string"},
+				New: &v1.JSONSchemaProps{Type: "integer"},
+			},
+		},
+		{
+			name: "x-kubernetes-int-or-string disabled, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{XIntOrString: true},
+				New: &v1.JSONSchemaProps{Type: "string"},
+			},
+			shouldError:  true,
+			shouldHandle: true,
+		},
+		{
+			name: "x-kubernetes-int-or-string enabled, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "integer"},
+				New: &v1.JSONSchemaProps{XIntOrString: true},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "x-kubernetes-int-or-string enabled with other changes, no error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "integer"},
+				New: &v1.JSONSchemaProps{XIntOrString: true, Description: "changed"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handled, err := crdupgradesafety.IntOrStringChangeValidation(tc.diff)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			assert.Equal(t, tc.shouldHandle, handled, "should be handled? - %v", tc.shouldHandle)
+		})
+	}
+}
+
+func TestEmbeddedResourceChangeValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		diff         crdupgradesafety.FieldDiff
+		shouldError  bool
+		shouldHandle bool
+	}{
+		{
+			name: "no change in x-kubernetes-embedded-resource, no error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "object", XEmbeddedResource: true},
+				New: &v1.JSONSchemaProps{Type: "object", XEmbeddedResource: true},
+			},
+			shouldHandle: true,
+		},
+		{
+			name: "x-kubernetes-embedded-resource disabled, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "object", XEmbeddedResource: true},
+				New: &v1.JSONSchemaProps{Type: "object"},
+			},
+			shouldError:  true,
+			shouldHandle: true,
+		},
+		{
+			name: "x-kubernetes-embedded-resource enabled, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "object"},
+				New: &v1.JSONSchemaProps{Type: "object", XEmbeddedResource: true},
+			},
+			shouldError:  true,
+			shouldHandle: true,
+		},
+		{
+			name: "x-kubernetes-embedded-resource disabled with other changes, error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "object", XEmbeddedResource: true},
+				New: &v1.JSONSchemaProps{Type: "object", Description: "changed"},
+			},
+			shouldError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handled, err := crdupgradesafety.EmbeddedResourceChangeValidation(tc.diff)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			assert.Equal(t, tc.shouldHandle, handled, "should be handled? - %v", tc.shouldHandle)
+		})
+	}
+}
+
+func TestChangeValidatorFieldsAddedUnderPreserveUnknownFields(t *testing.T) {
+	crd := func(spec v1.JSONSchemaProps) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Type:       "object",
+							Properties: map[string]v1.JSONSchemaProps{"spec": spec},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	old := crd(v1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: pointer.Bool(true)})
+
+	added := crd(v1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: pointer.Bool(true),
+		Properties: map[string]v1.JSONSchemaProps{
+			"foo": {Type: "string", Default: &v1.JSON{Raw: []byte(`"default"`)}},
+			"bar": {Type: "object", Properties: map[string]v1.JSONSchemaProps{
+				"baz": {Type: "integer", Minimum: pointer.Float64(1)},
+			}},
+		},
+	})
+	require.NoError(t, crdupgradesafety.NewDefaultValidator().Validate(old, added))
+
+	// Values of previously unknown fields may not be present
+	// in existing resources, hence they cannot become required
+	addedRequired := crd(v1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: pointer.Bool(true),
+		Properties: map[string]v1.JSONSchemaProps{"foo": {Type: "string"}},
+		Required:   []string{"foo"},
+	})
+	err := crdupgradesafety.NewDefaultValidator().Validate(old, addedRequired)
+	require.ErrorContains(t, err, `version "v1", field "^.spec": new values added as required when previously no required fields existed: [foo]`)
+}
+
+func TestPreserveUnknownFieldsChangeValidation(t *testing.T) {
+	preserve := true
+
+	for _, tc := range []struct {
+		name         string
+		diff         crdupgradesafety.FieldDiff
+		shouldError  bool
+		shouldHandle bool
+	}{
+		{
+			name: "typed object field becomes preserve-unknown, error, marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "object"},
+				New: &v1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: &preserve},
+			},
+			shouldError:  true,
+			shouldHandle: true,
+		},
+		{
+			name: "typed object field becomes preserve-unknown with other changes, error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "object"},
+				New: &v1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: &preserve, Description: "changed"},
+			},
+			shouldError: true,
+		},
+		{
+			name: "preserve-unknown object field unchanged, no error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: &preserve},
+				New: &v1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: &preserve, Description: "changed"},
+			},
+		},
+		{
+			name: "preserve-unknown disabled, no error, not marked as handled",
+			diff: crdupgradesafety.FieldDiff{
+				Old: &v1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: &preserve},
+				New: &v1.JSONSchemaProps{Type: "object"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handled, err := crdupgradesafety.PreserveUnknownFieldsChangeValidation(tc.diff)
+			assert.Equal(t, tc.shouldError, err != nil, "should error? - %v", tc.shouldError)
+			assert.Equal(t, tc.shouldHandle, handled, "should be handled? - %v", tc.shouldHandle)
+		})
+	}
+}
+
+func TestUnstructuredFieldSeverity(t *testing.T) {
+	preserve := true
+	crd := func(specSchema v1.JSONSchemaProps) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Type:       "object",
+							Properties: map[string]v1.JSONSchemaProps{"spec": specSchema},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	typedSpec := v1.JSONSchemaProps{
+		Type:       "object",
+		Properties: map[string]v1.JSONSchemaProps{"replicas": {Type: "integer"}},
+	}
+	unstructuredSpec := *typedSpec.DeepCopy()
+	unstructuredSpec.XPreserveUnknownFields = &preserve
+
+	warnings, err := crdupgradesafety.NewDefaultValidator().ValidateWithWarnings(crd(typedSpec), crd(unstructuredSpec))
+	require.ErrorIs(t, err, crdupgradesafety.ErrFieldBecameUnstructured)
+	require.Empty(t, warnings)
+
+	validator := crdupgradesafety.NewValidatorWithConfig(crdupgradesafety.PreflightConfig{
+		UnstructuredFieldSeverity: crdupgradesafety.SeverityWarning,
+	})
+	warnings, err = validator.ValidateWithWarnings(crd(typedSpec), crd(unstructuredSpec))
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], crdupgradesafety.ErrFieldBecameUnstructured)
+	require.Contains(t, warnings[0].Error(), `field "^.spec"`)
+
+	p := crdupgradesafety.NewPreflight(nil, nil, true)
+	err = p.SetConfig(preflight.CheckConfig{"unstructuredFieldSeverity": "info"})
+	require.ErrorContains(t, err, `unknown unstructuredFieldSeverity "info"`)
+}
@@ -5,58 +5,185 @@ package crdupgradesafety
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
 	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
 	"carvel.dev/kapp/pkg/kapp/preflight"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/pflag"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-var _ preflight.Check = (*Preflight)(nil)
+var _ preflight.CheckWithFlags = (*Preflight)(nil)
+
+const (
+	// FailureModeError fails preflight when CRD upgrade is unsafe
+	FailureModeError = "error"
+	// FailureModeWarn only shows warnings when CRD upgrade is unsafe
+	FailureModeWarn = "warn"
+)
 
 // Preflight is an implementation of preflight.Check
 // to make it easier to add crd upgrade validation
 // as a preflight check
 type Preflight struct {
 	depsFactory cmdcore.DepsFactory
+	ui          ui.UI
 	enabled     bool
 	validator   *Validator
+	failureMode string
+}
+
+// PreflightConfig is the configuration of the
+// CRD upgrade safety preflight check
+type PreflightConfig struct {
+	// AllowRequiredFieldsWithDefault relaxes required field validation
+	// to allow fields to become required if they specify a default value
+	AllowRequiredFieldsWithDefault bool `json:"allowRequiredFieldsWithDefault"`
+	// AllowEnumAdditionFields lists fields (e.g. "^.spec.mode") that are
+	// allowed to get enum restrictions when they previously had none
+	AllowEnumAdditionFields []string `json:"allowEnumAdditionFields"`
+	// ValidateNames enables validation of CRD names. Changes to kind
+	// or plural name fail validation; removal of short names
+	// or categories is reported as a warning
+	ValidateNames bool `json:"validateNames"`
+	// ChangePolicy lists schema keywords that are allowed
+	// to change for specific fields
+	ChangePolicy ChangePolicy `json:"changePolicy"`
+	// UnstructuredFieldSeverity sets how structured object fields becoming
+	// unstructured (x-kubernetes-preserve-unknown-fields enabled) are reported
+	// ("error" or "warning"). Defaults to "error"
+	UnstructuredFieldSeverity Severity `json:"unstructuredFieldSeverity"`
+	// RequireDeprecationBeforeRemoval fails validation when a served
+	// version is removed without being marked deprecated in existing CRD
+	RequireDeprecationBeforeRemoval bool `json:"requireDeprecationBeforeRemoval"`
+	// ReportRemovedVersionFields reports each field of versions
+	// removed in new CRD (see ChangeValidator.ReportRemovedVersions)
+	ReportRemovedVersionFields bool `json:"reportRemovedVersionFields"`
+	// DisallowTransitionRuleRemoval fails validation when existing
+	// transition rules (e.g. ones making fields immutable) are removed
+	DisallowTransitionRuleRemoval bool `json:"disallowTransitionRuleRemoval"`
+}
+
+// Validate checks that config only references known values
+func (c PreflightConfig) Validate() error {
+	switch c.UnstructuredFieldSeverity {
+	case "", SeverityError, SeverityWarning:
+	default:
+		return fmt.Errorf("unknown unstructuredFieldSeverity %q, expected one of [%s, %s]",
+			c.UnstructuredFieldSeverity, SeverityError, SeverityWarning)
+	}
+	return c.ChangePolicy.Validate()
 }
 
-func NewPreflight(df cmdcore.DepsFactory, enabled bool) *Preflight {
+// NewDefaultValidator returns a Validator configured with
+// the default set of CRD upgrade safety validations
+func NewDefaultValidator() *Validator {
+	return NewValidatorWithConfig(PreflightConfig{})
+}
+
+// NewValidatorWithConfig returns a Validator configured with the default
+// set of CRD upgrade safety validations adjusted based on given config
+func NewValidatorWithConfig(cfg PreflightConfig) *Validator {
+	requiredFieldChangeValidation := RequiredFieldChangeValidation
+	if cfg.AllowRequiredFieldsWithDefault {
+		requiredFieldChangeValidation = RequiredFieldWithDefaultChangeValidation
+	}
+
+	enumChangeValidation := EnumChangeValidation
+	if len(cfg.AllowEnumAdditionFields) > 0 {
+		enumChangeValidation = EnumChangeValidationWithAllowedAdditions(cfg.AllowEnumAdditionFields)
+	}
+
+	transitionRuleChangeValidation := TransitionRuleChangeValidation
+	if cfg.DisallowTransitionRuleRemoval {
+		transitionRuleChangeValidation = TransitionRuleRemovalChangeValidation
+	}
+
+	changeValidations := []ChangeValidation{
+		enumChangeValidation,
+		requiredFieldChangeValidation,
+		MinimumChangeValidation,
+		MinimumItemsChangeValidation,
+		MinimumLengthChangeValidation,
+		MinimumPropertiesChangeValidation,
+		MaximumChangeValidation,
+		MaximumLengthChangeValidation,
+		MaximumItemsChangeValidation,
+		MaximumPropertiesChangeValidation,
+		DefaultValueChangeValidation,
+		transitionRuleChangeValidation,
+		CompositeBranchChangeValidation,
+		AdditionalPropertiesChangeValidation,
+		IntOrStringChangeValidation,
+		EmbeddedResourceChangeValidation,
+		PreserveUnknownFieldsChangeValidation,
+	}
+	if len(cfg.ChangePolicy.Fields) > 0 {
+		changeValidations = append([]ChangeValidation{ChangePolicyValidation(cfg.ChangePolicy)}, changeValidations...)
+	}
+
+	changeValidator := &ChangeValidator{
+		Validations:           changeValidations,
+		ReportRemovedVersions: cfg.ReportRemovedVersionFields,
+	}
+	if cfg.UnstructuredFieldSeverity == SeverityWarning {
+		changeValidator.SeverityFunc = func(_ string, err error) Severity {
+			if errors.Is(err, ErrFieldBecameUnstructured) {
+				return SeverityWarning
+			}
+			return SeverityError
+		}
+	}
+
+	validator := &Validator{
+		Validations: []Validation{
+			NewValidationFunc("NoScopeChange", NoScopeChange),
+			NewValidationFunc("NoStoredVersionRemoved", NoStoredVersionRemoved),
+			NewValidationFunc("ServedStorageVersion", ServedStorageVersion),
+			NewValidationFunc("NoExistingFieldRemoved", NoExistingFieldRemoved),
+			&StorageVersionChangeValidator{},
+			NewValidationFunc("NoInvalidDefaults", NoInvalidDefaults),
+			NewValidationFunc("NoPrinterColumnChange", NoPrinterColumnChange),
+			NewValidationFunc("NoScaleSubresourceChange", NoScaleSubresourceChange),
+			changeValidator,
+		},
+	}
+
+	if cfg.RequireDeprecationBeforeRemoval {
+		validator.Validations = append(validator.Validations,
+			NewValidationFunc("NoUndeprecatedServedVersionRemoved", NoUndeprecatedServedVersionRemoved))
+	}
+
+	if cfg.ValidateNames {
+		validator.Validations = append(validator.Validations, &NamesValidator{})
+	}
+
+	return validator
+}
+
+func NewPreflight(df cmdcore.DepsFactory, ui ui.UI, enabled bool) *Preflight {
 	return &Preflight{
 		depsFactory: df,
+		ui:          ui,
 		enabled:     enabled,
-		validator: &Validator{
-			Validations: []Validation{
-				NewValidationFunc("NoScopeChange", NoScopeChange),
-				NewValidationFunc("NoStoredVersionRemoved", NoStoredVersionRemoved),
-				NewValidationFunc("NoExistingFieldRemoved", NoExistingFieldRemoved),
-				&ChangeValidator{
-					Validations: []ChangeValidation{
-						EnumChangeValidation,
-						RequiredFieldChangeValidation,
-						MinimumChangeValidation,
-						MinimumItemsChangeValidation,
-						MinimumLengthChangeValidation,
-						MinimumPropertiesChangeValidation,
-						MaximumChangeValidation,
-						MaximumLengthChangeValidation,
-						MaximumItemsChangeValidation,
-						MaximumPropertiesChangeValidation,
-						DefaultValueChangeValidation,
-					},
-				},
-			},
-		},
+		validator:   NewDefaultValidator(),
+		failureMode: FailureModeError,
 	}
 }
 
+func (p *Preflight) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&p.failureMode, "preflight-crd-upgrade-safety", FailureModeError,
+		fmt.Sprintf("Set how unsafe CRD upgrades found by CRDUpgradeSafety preflight check are reported (%s, %s)", FailureModeError, FailureModeWarn))
+}
+
 func (p *Preflight) Enabled() bool {
 	return p.enabled
 }
@@ -65,11 +192,35 @@ func (p *Preflight) SetEnabled(enabled bool) {
 	p.enabled = enabled
 }
 
-func (p *Preflight) SetConfig(_ preflight.CheckConfig) error {
+func (p *Preflight) SetConfig(cfg preflight.CheckConfig) error {
+	pCfg := PreflightConfig{}
+	cfgBytes, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("converting CheckConfig to bytes: %w", err)
+	}
+
+	err = json.Unmarshal(cfgBytes, &pCfg)
+	if err != nil {
+		return fmt.Errorf("parsing crd upgrade safety preflight config: %w", err)
+	}
+
+	err = pCfg.Validate()
+	if err != nil {
+		return fmt.Errorf("parsing crd upgrade safety preflight config: %w", err)
+	}
+
+	p.validator = NewValidatorWithConfig(pCfg)
 	return nil
 }
 
 func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph) error {
+	switch p.failureMode {
+	case FailureModeError, FailureModeWarn:
+	default:
+		return fmt.Errorf("unknown CRD upgrade safety failure mode %q, expected one of [%s, %s]",
+			p.failureMode, FailureModeError, FailureModeWarn)
+	}
+
 	dCli, err := p.depsFactory.DynamicClient(cmdcore.DynamicClientOpts{})
 	if err != nil {
 		return fmt.Errorf("getting dynamic client: %w", err)
@@ -115,14 +266,22 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 			return fmt.Errorf("couldn't convert new CRD resource to a CRD object: %w", err)
 		}
 
-		if err = p.validator.Validate(*oldCRD, *newCRD); err != nil {
+		warnings, err := p.validator.ValidateWithWarnings(*oldCRD, *newCRD)
+		for _, warning := range warnings {
+			p.ui.PrintLinef("Warning: %s", warning)
+		}
+		if err != nil {
 			validateErrs = append(validateErrs, err)
 		}
 	}
 
 	if len(validateErrs) > 0 {
-		baseErr := errors.New("validation for safe CRD upgrades failed")
-		return errors.Join(append([]error{baseErr}, validateErrs...)...)
+		report := NewValidationReport(errors.Join(validateErrs...))
+		if p.failureMode == FailureModeWarn {
+			p.ui.PrintLinef("Warning: validation for safe CRD upgrades failed:\n%s", strings.TrimSuffix(report.String(), "\n"))
+			return nil
+		}
+		return fmt.Errorf("validation for safe CRD upgrades failed:\n%s", report)
 	}
 
 	return nil
@@ -0,0 +1,160 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package crdupgradesafety
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError is returned by Validator for
+// each validation that failed for a CRD
+type ValidationError struct {
+	CRD        string
+	Validation string
+	Err        error
+}
+
+var _ error = &ValidationError{}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("CustomResourceDefinition %s failed upgrade safety validation. %q validation failed: %s",
+		e.CRD, e.Validation, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// FieldError is a validation failure specific
+// to a field within a version of a CRD
+type FieldError struct {
+	Version string
+	Field   string
+	Err     error
+}
+
+var _ error = &FieldError{}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("version %q, field %q: %s", e.Version, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// ValidationFinding is a single validation failure
+// with its location within a CRD, if known
+type ValidationFinding struct {
+	CRD        string
+	Validation string
+	Version    string
+	Field      string
+	Message    string
+}
+
+// ValidationReport is a structured representation
+// of CRD upgrade safety validation failures
+type ValidationReport struct {
+	Findings []ValidationFinding
+}
+
+// NewValidationReport builds a report from errors
+// returned by Validator (possibly joined together)
+func NewValidationReport(err error) ValidationReport {
+	report := ValidationReport{}
+	if err != nil {
+		report.collect(err, ValidationFinding{})
+	}
+	return report
+}
+
+func (r *ValidationReport) collect(err error, finding ValidationFinding) {
+	switch typedErr := err.(type) {
+	case *ValidationError:
+		finding.CRD = typedErr.CRD
+		finding.Validation = typedErr.Validation
+		r.collect(typedErr.Err, finding)
+
+	case *FieldError:
+		finding.Version = typedErr.Version
+		finding.Field = typedErr.Field
+		r.collect(typedErr.Err, finding)
+
+	case interface{ Unwrap() []error }:
+		for _, err := range typedErr.Unwrap() {
+			r.collect(err, finding)
+		}
+
+	default:
+		finding.Message = err.Error()
+		r.Findings = append(r.Findings, finding)
+	}
+}
+
+// String renders findings grouped by CRD name,
+// then version, then field path. For example:
+//
+//	CustomResourceDefinition foos.example.com:
+//	  - scope changed from "Namespaced" to "Cluster" (NoScopeChange)
+//	  version "v1":
+//	    field "^.spec.size":
+//	      - maximum constraint decreased from 10 to 5 (ChangeValidator)
+func (r ValidationReport) String() string {
+	byCRD := map[string][]ValidationFinding{}
+	for _, finding := range r.Findings {
+		byCRD[finding.CRD] = append(byCRD[finding.CRD], finding)
+	}
+
+	var sb strings.Builder
+
+	for _, crd := range sortedKeys(byCRD) {
+		fmt.Fprintf(&sb, "CustomResourceDefinition %s:\n", crd)
+
+		byVersion := map[string][]ValidationFinding{}
+		for _, finding := range byCRD[crd] {
+			byVersion[finding.Version] = append(byVersion[finding.Version], finding)
+		}
+
+		// Findings not associated with a version are shown first,
+		// since sorting places the empty version before others
+		for _, version := range sortedKeys(byVersion) {
+			indent := "  "
+			if version != "" {
+				fmt.Fprintf(&sb, "%sversion %q:\n", indent, version)
+				indent += "  "
+			}
+
+			byField := map[string][]ValidationFinding{}
+			for _, finding := range byVersion[version] {
+				byField[finding.Field] = append(byField[finding.Field], finding)
+			}
+
+			for _, field := range sortedKeys(byField) {
+				fieldIndent := indent
+				if field != "" {
+					fmt.Fprintf(&sb, "%sfield %q:\n", fieldIndent, field)
+					fieldIndent += "  "
+				}
+				for _, finding := range byField[field] {
+					msg := strings.ReplaceAll(finding.Message, "\n", "\n"+fieldIndent+"  ")
+					fmt.Fprintf(&sb, "%s- %s", fieldIndent, msg)
+					if finding.Validation != "" {
+						fmt.Fprintf(&sb, " (%s)", finding.Validation)
+					}
+					sb.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func sortedKeys(m map[string][]ValidationFinding) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
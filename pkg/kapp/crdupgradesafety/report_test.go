@@ -0,0 +1,98 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package crdupgradesafety_test
+
+import (
+	"errors"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/crdupgradesafety"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestValidationReport(t *testing.T) {
+	err := errors.Join(
+		&crdupgradesafety.ValidationError{
+			CRD:        "foos.example.com",
+			Validation: "ChangeValidator",
+			Err: errors.Join(
+				&crdupgradesafety.FieldError{Version: "v1", Field: "^.spec.size", Err: errors.New("maximum constraint decreased from 10 to 5")},
+				&crdupgradesafety.FieldError{Version: "v1alpha1", Field: "^.spec.mode", Err: errors.New("enum values removed: [a]")},
+				&crdupgradesafety.FieldError{Version: "v1", Field: "^.spec.mode", Err: errors.New("enum values removed: [b]")},
+			),
+		},
+		&crdupgradesafety.ValidationError{
+			CRD:        "bars.example.com",
+			Validation: "NoScopeChange",
+			Err:        errors.New(`scope changed from "Namespaced" to "Cluster"`),
+		},
+		&crdupgradesafety.ValidationError{
+			CRD:        "foos.example.com",
+			Validation: "NoStoredVersionRemoved",
+			Err:        errors.New(`stored version "v1beta1" removed`),
+		},
+	)
+
+	report := crdupgradesafety.NewValidationReport(err)
+	assert.Len(t, report.Findings, 5)
+
+	expected := `CustomResourceDefinition bars.example.com:
+  - scope changed from "Namespaced" to "Cluster" (NoScopeChange)
+CustomResourceDefinition foos.example.com:
+  - stored version "v1beta1" removed (NoStoredVersionRemoved)
+  version "v1":
+    field "^.spec.mode":
+      - enum values removed: [b] (ChangeValidator)
+    field "^.spec.size":
+      - maximum constraint decreased from 10 to 5 (ChangeValidator)
+  version "v1alpha1":
+    field "^.spec.mode":
+      - enum values removed: [a] (ChangeValidator)
+`
+	assert.Equal(t, expected, report.String())
+}
+
+func TestValidationReportFromValidator(t *testing.T) {
+	newCRD := func(maximum float64) v1.CustomResourceDefinition {
+		return v1.CustomResourceDefinition{
+			Spec: v1.CustomResourceDefinitionSpec{
+				Versions: []v1.CustomResourceDefinitionVersion{{
+					Name: "v1",
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]v1.JSONSchemaProps{
+								"size": {Type: "integer", Maximum: pointer.Float64(maximum)},
+							},
+						},
+					},
+				}},
+			},
+		}
+	}
+	oldCRD := newCRD(10)
+	oldCRD.Name = "foos.example.com"
+	updatedCRD := newCRD(5)
+	updatedCRD.Name = "foos.example.com"
+
+	validator := &crdupgradesafety.Validator{
+		Validations: []crdupgradesafety.Validation{
+			&crdupgradesafety.ChangeValidator{
+				Validations: []crdupgradesafety.ChangeValidation{crdupgradesafety.MaximumChangeValidation},
+			},
+		},
+	}
+
+	err := validator.Validate(oldCRD, updatedCRD)
+	assert.Error(t, err)
+
+	expected := `CustomResourceDefinition foos.example.com:
+  version "v1":
+    field "^.size":
+      - maximum constraint decreased from 10 to 5 (ChangeValidator)
+`
+	assert.Equal(t, expected, crdupgradesafety.NewValidationReport(err).String())
+}
@@ -4,13 +4,18 @@
 package crdupgradesafety
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 )
 
 // Validation is a representation of a validation to run
@@ -50,24 +55,53 @@ func (vf *ValidationFunc) Validate(old, new v1.CustomResourceDefinition) error {
 	return vf.validateFunc(old, new)
 }
 
+// WarningValidation is a Validation that is also able
+// to report non-fatal failures as warnings
+type WarningValidation interface {
+	Validation
+	// ValidateWithWarnings returns warnings in addition to
+	// an error that means validation has failed
+	ValidateWithWarnings(old, new v1.CustomResourceDefinition) ([]error, error)
+}
+
 type Validator struct {
 	Validations []Validation
 }
 
 func (v *Validator) Validate(old, new v1.CustomResourceDefinition) error {
+	_, err := v.ValidateWithWarnings(old, new)
+	return err
+}
+
+// ValidateWithWarnings performs the same validation as Validate,
+// additionally returning warnings reported by validations
+// that implement WarningValidation
+func (v *Validator) ValidateWithWarnings(old, new v1.CustomResourceDefinition) ([]error, error) {
+	warnings := []error{}
 	validateErrs := []error{}
 	for _, validation := range v.Validations {
-		if err := validation.Validate(old, new); err != nil {
-			formattedErr := fmt.Errorf("CustomResourceDefinition %s failed upgrade safety validation. %q validation failed: %w",
-				new.Name, validation.Name(), err)
+		var validationWarnings []error
+		var err error
+
+		if warningValidation, ok := validation.(WarningValidation); ok {
+			validationWarnings, err = warningValidation.ValidateWithWarnings(old, new)
+		} else {
+			err = validation.Validate(old, new)
+		}
+
+		for _, warning := range validationWarnings {
+			warnings = append(warnings, fmt.Errorf("CustomResourceDefinition %s upgrade safety %q validation warning: %w",
+				new.Name, validation.Name(), warning))
+		}
 
-			validateErrs = append(validateErrs, formattedErr)
+		if err != nil {
+			validateErrs = append(validateErrs, &ValidationError{CRD: new.Name, Validation: validation.Name(), Err: err})
 		}
 	}
 	if len(validateErrs) > 0 {
-		return errors.Join(validateErrs...)
+		return warnings, errors.Join(validateErrs...)
 	}
-	return nil
+	return warnings, nil
 }
 
 func NoScopeChange(old, new v1.CustomResourceDefinition) error {
@@ -94,6 +128,50 @@ func NoStoredVersionRemoved(old, new v1.CustomResourceDefinition) error {
 	return nil
 }
 
+// NoUndeprecatedServedVersionRemoved checks that served versions
+// are only removed once they were marked deprecated in the old CRD
+func NoUndeprecatedServedVersionRemoved(old, new v1.CustomResourceDefinition) error {
+	newVersions := sets.New[string]()
+	for _, version := range new.Spec.Versions {
+		newVersions.Insert(version.Name)
+	}
+
+	var errs []error
+	for _, version := range old.Spec.Versions {
+		if version.Served && !version.Deprecated && !newVersions.Has(version.Name) {
+			errs = append(errs, fmt.Errorf("served version %q removed without being deprecated first", version.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ServedStorageVersion checks that the new CRD has exactly
+// one storage version and that this version is served
+func ServedStorageVersion(_, new v1.CustomResourceDefinition) error {
+	storageVersions := []v1.CustomResourceDefinitionVersion{}
+	for _, version := range new.Spec.Versions {
+		if version.Storage {
+			storageVersions = append(storageVersions, version)
+		}
+	}
+
+	switch len(storageVersions) {
+	case 0:
+		return errors.New("no storage version specified")
+	case 1:
+		if !storageVersions[0].Served {
+			return fmt.Errorf("storage version %q is not served", storageVersions[0].Name)
+		}
+		return nil
+	default:
+		names := []string{}
+		for _, version := range storageVersions {
+			names = append(names, version.Name)
+		}
+		return fmt.Errorf("multiple storage versions specified: %s", strings.Join(names, ", "))
+	}
+}
+
 func NoExistingFieldRemoved(old, new v1.CustomResourceDefinition) error {
 	reg := manifestcomparators.NewRegistry()
 	err := reg.AddComparator(manifestcomparators.NoFieldRemoval())
@@ -119,3 +197,235 @@ func NoExistingFieldRemoved(old, new v1.CustomResourceDefinition) error {
 
 	return nil
 }
+
+// NamesValidator compares names of the existing and new CRD.
+// Changes to kind or plural name are breaking and reported as errors.
+// Removed short names and categories break existing kubectl usage
+// (e.g. "kubectl get <category>") and are reported as warnings.
+type NamesValidator struct{}
+
+var _ WarningValidation = &NamesValidator{}
+
+func (nv *NamesValidator) Name() string {
+	return "NamesValidator"
+}
+
+func (nv *NamesValidator) Validate(old, new v1.CustomResourceDefinition) error {
+	_, err := nv.ValidateWithWarnings(old, new)
+	return err
+}
+
+func (nv *NamesValidator) ValidateWithWarnings(old, new v1.CustomResourceDefinition) ([]error, error) {
+	oldNames := old.Spec.Names
+	newNames := new.Spec.Names
+
+	errs := []error{}
+	if oldNames.Kind != newNames.Kind {
+		errs = append(errs, fmt.Errorf("kind changed from %q to %q", oldNames.Kind, newNames.Kind))
+	}
+	if oldNames.Plural != newNames.Plural {
+		errs = append(errs, fmt.Errorf("plural name changed from %q to %q", oldNames.Plural, newNames.Plural))
+	}
+
+	warnings := []error{}
+	for _, shortName := range sets.List(sets.New(oldNames.ShortNames...).Difference(sets.New(newNames.ShortNames...))) {
+		warnings = append(warnings, fmt.Errorf("short name %q removed", shortName))
+	}
+	for _, category := range sets.List(sets.New(oldNames.Categories...).Difference(sets.New(newNames.Categories...))) {
+		warnings = append(warnings, fmt.Errorf("category %q removed", category))
+	}
+
+	return warnings, errors.Join(errs...)
+}
+
+// StorageVersionChangeValidator compares the storage version of the
+// existing and new CRD. Moving the storage version to another existing
+// version is allowed, but objects persisted in the previous storage
+// version need to be migrated, hence it is reported as a warning.
+// Removal of the previous storage version is left to NoStoredVersionRemoved.
+type StorageVersionChangeValidator struct{}
+
+var _ WarningValidation = &StorageVersionChangeValidator{}
+
+func (sv *StorageVersionChangeValidator) Name() string {
+	return "StorageVersionChange"
+}
+
+func (sv *StorageVersionChangeValidator) Validate(old, new v1.CustomResourceDefinition) error {
+	_, err := sv.ValidateWithWarnings(old, new)
+	return err
+}
+
+func (sv *StorageVersionChangeValidator) ValidateWithWarnings(old, new v1.CustomResourceDefinition) ([]error, error) {
+	oldStorageVersion := storageVersionName(old)
+	newStorageVersion := storageVersionName(new)
+
+	if oldStorageVersion == "" || newStorageVersion == "" || oldStorageVersion == newStorageVersion {
+		return nil, nil
+	}
+	if manifestcomparators.GetVersionByName(&new, oldStorageVersion) == nil {
+		return nil, nil
+	}
+
+	return []error{fmt.Errorf("storage version changed from %q to %q, existing objects stored as %q require storage migration",
+		oldStorageVersion, newStorageVersion, oldStorageVersion)}, nil
+}
+
+func storageVersionName(crd v1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+// NoPrinterColumnChange checks that additional printer columns of
+// existing versions are neither removed nor change their type, since
+// clients parsing table output (e.g. "kubectl get") may rely on them
+func NoPrinterColumnChange(old, new v1.CustomResourceDefinition) error {
+	errs := []error{}
+
+	for _, version := range old.Spec.Versions {
+		newVersion := manifestcomparators.GetVersionByName(&new, version.Name)
+		if newVersion == nil {
+			continue
+		}
+
+		newColumns := map[string]v1.CustomResourceColumnDefinition{}
+		for _, column := range newVersion.AdditionalPrinterColumns {
+			newColumns[column.Name] = column
+		}
+
+		for _, column := range version.AdditionalPrinterColumns {
+			newColumn, found := newColumns[column.Name]
+			switch {
+			case !found:
+				errs = append(errs, fmt.Errorf("version %q, printer column %q removed", version.Name, column.Name))
+			case newColumn.Type != column.Type:
+				errs = append(errs, fmt.Errorf("version %q, printer column %q type changed from %q to %q",
+					version.Name, column.Name, column.Type, newColumn.Type))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NoScaleSubresourceChange checks that scale subresource paths of existing
+// versions are neither removed nor changed, since HPAs and "kubectl scale"
+// rely on them to read and update replica counts
+func NoScaleSubresourceChange(old, new v1.CustomResourceDefinition) error {
+	errs := []error{}
+
+	for _, version := range old.Spec.Versions {
+		newVersion := manifestcomparators.GetVersionByName(&new, version.Name)
+		if newVersion == nil || version.Subresources == nil || version.Subresources.Scale == nil {
+			continue
+		}
+
+		if newVersion.Subresources == nil || newVersion.Subresources.Scale == nil {
+			errs = append(errs, fmt.Errorf("version %q, scale subresource removed", version.Name))
+			continue
+		}
+
+		oldScale, newScale := version.Subresources.Scale, newVersion.Subresources.Scale
+
+		paths := []struct {
+			name     string
+			old, new string
+		}{
+			{"specReplicasPath", oldScale.SpecReplicasPath, newScale.SpecReplicasPath},
+			{"statusReplicasPath", oldScale.StatusReplicasPath, newScale.StatusReplicasPath},
+			{"labelSelectorPath", ptr.Deref(oldScale.LabelSelectorPath, ""), ptr.Deref(newScale.LabelSelectorPath, "")},
+		}
+
+		for _, path := range paths {
+			if path.old != path.new {
+				errs = append(errs, fmt.Errorf("version %q, scale subresource %s changed from %q to %q",
+					version.Name, path.name, path.old, path.new))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NoInvalidDefaults checks that defaults specified in the new CRD
+// satisfy constraints (enum, minimum, maximum, pattern) of their own field.
+// Otherwise objects relying on defaulting may be rejected unexpectedly.
+func NoInvalidDefaults(_, new v1.CustomResourceDefinition) error {
+	errs := []error{}
+
+	for _, version := range new.Spec.Versions {
+		if version.Schema == nil {
+			continue
+		}
+
+		flatSchema := FlattenSchema(version.Schema.OpenAPIV3Schema)
+
+		fields := []string{}
+		for field := range flatSchema {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			err := validateDefault(flatSchema[field])
+			if err != nil {
+				errs = append(errs, &FieldError{Version: version.Name, Field: field, Err: err})
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateDefault(schema *v1.JSONSchemaProps) error {
+	if schema.Default == nil {
+		return nil
+	}
+
+	var defaultVal interface{}
+	err := json.Unmarshal(schema.Default.Raw, &defaultVal)
+	if err != nil {
+		return fmt.Errorf("parsing default: %w", err)
+	}
+
+	if len(schema.Enum) > 0 {
+		var found bool
+		for _, enum := range schema.Enum {
+			var enumVal interface{}
+			if json.Unmarshal(enum.Raw, &enumVal) == nil && reflect.DeepEqual(defaultVal, enumVal) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("default %s is not one of enum values", schema.Default.Raw)
+		}
+	}
+
+	if num, ok := defaultVal.(float64); ok {
+		if schema.Minimum != nil {
+			if num < *schema.Minimum || (schema.ExclusiveMinimum && num == *schema.Minimum) {
+				return fmt.Errorf("default %v is less than minimum %v", num, *schema.Minimum)
+			}
+		}
+		if schema.Maximum != nil {
+			if num > *schema.Maximum || (schema.ExclusiveMaximum && num == *schema.Maximum) {
+				return fmt.Errorf("default %v is greater than maximum %v", num, *schema.Maximum)
+			}
+		}
+	}
+
+	if str, ok := defaultVal.(string); ok && len(schema.Pattern) > 0 {
+		// Invalid patterns are rejected by the API server itself
+		re, err := regexp.Compile(schema.Pattern)
+		if err == nil && !re.MatchString(str) {
+			return fmt.Errorf("default %q does not match pattern %q", str, schema.Pattern)
+		}
+	}
+
+	return nil
+}
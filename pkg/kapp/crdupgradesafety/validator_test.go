@@ -176,6 +176,171 @@ func TestNoStoredVersionRemoved(t *testing.T) {
 	}
 }
 
+func TestNoUndeprecatedServedVersionRemoved(t *testing.T) {
+	crd := func(versions ...apiextensionsv1.CustomResourceDefinitionVersion) apiextensionsv1.CustomResourceDefinition {
+		return apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{Versions: versions},
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		old  apiextensionsv1.CustomResourceDefinition
+		new  apiextensionsv1.CustomResourceDefinition
+		err  string
+	}{
+		{
+			name: "no version removed, no error",
+			old:  crd(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true}),
+			new: crd(
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true, Deprecated: true},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true},
+			),
+		},
+		{
+			name: "deprecated served version removed, no error",
+			old: crd(
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true, Deprecated: true},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true},
+			),
+			new: crd(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true}),
+		},
+		{
+			name: "unserved version removed, no error",
+			old: crd(
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1"},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true},
+			),
+			new: crd(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true}),
+		},
+		{
+			name: "served version removed without deprecation, error",
+			old: crd(
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true},
+			),
+			new: crd(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true}),
+			err: `served version "v1alpha1" removed without being deprecated first`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NoUndeprecatedServedVersionRemoved(tc.old, tc.new)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestRequireDeprecationBeforeRemoval(t *testing.T) {
+	schema := &apiextensionsv1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Type: "object"},
+	}
+	old := apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Schema: schema},
+				{Name: "v1", Served: true, Storage: true, Schema: schema},
+			},
+		},
+	}
+	new := old.DeepCopy()
+	new.Spec.Versions = new.Spec.Versions[1:]
+
+	require.NoError(t, NewDefaultValidator().Validate(old, *new))
+
+	err := NewValidatorWithConfig(PreflightConfig{RequireDeprecationBeforeRemoval: true}).Validate(old, *new)
+	require.ErrorContains(t, err, `served version "v1alpha1" removed without being deprecated first`)
+}
+
+func TestDisallowTransitionRuleRemoval(t *testing.T) {
+	crd := func(rules apiextensionsv1.ValidationRules) apiextensionsv1.CustomResourceDefinition {
+		return apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										"name": {Type: "string", XValidations: rules},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		}
+	}
+	old := crd(apiextensionsv1.ValidationRules{{Rule: "self == oldSelf", Message: "name is immutable"}})
+	new := crd(nil)
+
+	require.NoError(t, NewDefaultValidator().Validate(old, new))
+
+	err := NewValidatorWithConfig(PreflightConfig{DisallowTransitionRuleRemoval: true}).Validate(old, new)
+	require.ErrorContains(t, err, `version "v1", field "^.spec.name": transition rules removed: [self == oldSelf]`)
+}
+
+func TestServedStorageVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		versions []apiextensionsv1.CustomResourceDefinitionVersion
+		err      string
+	}{
+		{
+			name: "single served storage version, no error",
+			versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+		{
+			name: "no storage version, error",
+			versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: true},
+			},
+			err: "no storage version specified",
+		},
+		{
+			name: "multiple storage versions, error",
+			versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Storage: true},
+				{Name: "v1", Served: true, Storage: true},
+			},
+			err: "multiple storage versions specified: v1alpha1, v1",
+		},
+		{
+			name: "storage version not served, error",
+			versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: false, Storage: true},
+			},
+			err: `storage version "v1" is not served`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			newCRD := apiextensionsv1.CustomResourceDefinition{
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{Versions: tc.versions},
+			}
+			err := ServedStorageVersion(apiextensionsv1.CustomResourceDefinition{}, newCRD)
+			if len(tc.err) > 0 {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestNoExistingFieldRemoved(t *testing.T) {
 	for _, tc := range []struct {
 		name        string
@@ -336,3 +501,369 @@ func TestNoExistingFieldRemoved(t *testing.T) {
 		})
 	}
 }
+
+func TestNamesValidator(t *testing.T) {
+	crd := func(names apiextensionsv1.CustomResourceDefinitionNames) apiextensionsv1.CustomResourceDefinition {
+		return apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{Names: names},
+		}
+	}
+
+	baseNames := apiextensionsv1.CustomResourceDefinitionNames{
+		Kind:       "Widget",
+		Plural:     "widgets",
+		ShortNames: []string{"wd", "wdg"},
+		Categories: []string{"all"},
+	}
+
+	for _, tc := range []struct {
+		name             string
+		new              apiextensionsv1.CustomResourceDefinitionNames
+		expectedErr      string
+		expectedWarnings []string
+	}{
+		{
+			name: "no names changes, no error, no warnings",
+			new:  baseNames,
+		},
+		{
+			name: "kind changed, error",
+			new: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:       "Gadget",
+				Plural:     "widgets",
+				ShortNames: []string{"wd", "wdg"},
+				Categories: []string{"all"},
+			},
+			expectedErr: `kind changed from "Widget" to "Gadget"`,
+		},
+		{
+			name: "short name removed, no error, warning",
+			new: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:       "Widget",
+				Plural:     "widgets",
+				ShortNames: []string{"wd"},
+				Categories: []string{"all"},
+			},
+			expectedWarnings: []string{`short name "wdg" removed`},
+		},
+		{
+			name: "category removed and short name added, no error, warning",
+			new: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:       "Widget",
+				Plural:     "widgets",
+				ShortNames: []string{"wd", "wdg", "w"},
+			},
+			expectedWarnings: []string{`category "all" removed`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := (&NamesValidator{}).ValidateWithWarnings(crd(baseNames), crd(tc.new))
+			if len(tc.expectedErr) > 0 {
+				require.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			warningMsgs := []string{}
+			for _, warning := range warnings {
+				warningMsgs = append(warningMsgs, warning.Error())
+			}
+			assert.ElementsMatch(t, tc.expectedWarnings, warningMsgs)
+		})
+	}
+}
+
+func TestValidatorWithWarnings(t *testing.T) {
+	old := apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Widget", Plural: "widgets", ShortNames: []string{"wd"}},
+		},
+	}
+	new := old.DeepCopy()
+	new.Name = "widgets.example.com"
+	new.Spec.Names.ShortNames = nil
+
+	validator := &Validator{Validations: []Validation{&NamesValidator{}}}
+
+	warnings, err := validator.ValidateWithWarnings(old, *new)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.EqualError(t, warnings[0], `CustomResourceDefinition widgets.example.com upgrade safety "NamesValidator" validation warning: short name "wd" removed`)
+}
+
+func TestStorageVersionChangeValidator(t *testing.T) {
+	crd := func(versions ...apiextensionsv1.CustomResourceDefinitionVersion) apiextensionsv1.CustomResourceDefinition {
+		return apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{Versions: versions},
+		}
+	}
+
+	oldCRD := crd(
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true, Storage: true},
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true},
+	)
+
+	for _, tc := range []struct {
+		name             string
+		new              apiextensionsv1.CustomResourceDefinition
+		expectedWarnings []string
+	}{
+		{
+			name: "storage version unchanged, no warning",
+			new: crd(
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true, Storage: true},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true},
+			),
+		},
+		{
+			name: "storage version moved from v1alpha1 to v1beta1, warning",
+			new: crd(
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true},
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true, Storage: true},
+			),
+			expectedWarnings: []string{`storage version changed from "v1alpha1" to "v1beta1", existing objects stored as "v1alpha1" require storage migration`},
+		},
+		{
+			name: "previous storage version removed, no warning",
+			new: crd(
+				apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true, Storage: true},
+			),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := (&StorageVersionChangeValidator{}).ValidateWithWarnings(oldCRD, tc.new)
+			require.NoError(t, err)
+
+			warningMsgs := []string{}
+			for _, warning := range warnings {
+				warningMsgs = append(warningMsgs, warning.Error())
+			}
+			assert.ElementsMatch(t, tc.expectedWarnings, warningMsgs)
+		})
+	}
+}
+
+func TestNoInvalidDefaults(t *testing.T) {
+	crd := func(props apiextensionsv1.JSONSchemaProps) apiextensionsv1.CustomResourceDefinition {
+		return apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &apiextensionsv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"spec": {
+										Type:       "object",
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{"field": props},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	minimum := float64(1)
+
+	for _, tc := range []struct {
+		name  string
+		props apiextensionsv1.JSONSchemaProps
+		err   string
+	}{
+		{
+			name: "default within enum, no error",
+			props: apiextensionsv1.JSONSchemaProps{
+				Type:    "string",
+				Enum:    []apiextensionsv1.JSON{{Raw: []byte(`"a"`)}, {Raw: []byte(`"b"`)}},
+				Default: &apiextensionsv1.JSON{Raw: []byte(`"b"`)},
+			},
+		},
+		{
+			name: "default outside enum, error",
+			props: apiextensionsv1.JSONSchemaProps{
+				Type:    "string",
+				Enum:    []apiextensionsv1.JSON{{Raw: []byte(`"a"`)}, {Raw: []byte(`"b"`)}},
+				Default: &apiextensionsv1.JSON{Raw: []byte(`"c"`)},
+			},
+			err: `version "v1alpha1", field "^.spec.field": default "c" is not one of enum values`,
+		},
+		{
+			name: "default above minimum, no error",
+			props: apiextensionsv1.JSONSchemaProps{
+				Type:    "integer",
+				Minimum: &minimum,
+				Default: &apiextensionsv1.JSON{Raw: []byte(`5`)},
+			},
+		},
+		{
+			name: "default below minimum, error",
+			props: apiextensionsv1.JSONSchemaProps{
+				Type:    "integer",
+				Minimum: &minimum,
+				Default: &apiextensionsv1.JSON{Raw: []byte(`0`)},
+			},
+			err: `version "v1alpha1", field "^.spec.field": default 0 is less than minimum 1`,
+		},
+		{
+			name: "default equal to exclusive minimum, error",
+			props: apiextensionsv1.JSONSchemaProps{
+				Type:             "integer",
+				Minimum:          &minimum,
+				ExclusiveMinimum: true,
+				Default:          &apiextensionsv1.JSON{Raw: []byte(`1`)},
+			},
+			err: `version "v1alpha1", field "^.spec.field": default 1 is less than minimum 1`,
+		},
+		{
+			name: "default not matching pattern, error",
+			props: apiextensionsv1.JSONSchemaProps{
+				Type:    "string",
+				Pattern: "^[a-z]+$",
+				Default: &apiextensionsv1.JSON{Raw: []byte(`"ABC"`)},
+			},
+			err: `version "v1alpha1", field "^.spec.field": default "ABC" does not match pattern "^[a-z]+$"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NoInvalidDefaults(apiextensionsv1.CustomResourceDefinition{}, crd(tc.props))
+			if len(tc.err) > 0 {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNoPrinterColumnChange(t *testing.T) {
+	crd := func(versions map[string][]apiextensionsv1.CustomResourceColumnDefinition) apiextensionsv1.CustomResourceDefinition {
+		crd := apiextensionsv1.CustomResourceDefinition{}
+		for _, name := range []string{"v1alpha1", "v1"} {
+			if columns, found := versions[name]; found {
+				crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{
+					Name:                     name,
+					AdditionalPrinterColumns: columns,
+				})
+			}
+		}
+		return crd
+	}
+	replicas := apiextensionsv1.CustomResourceColumnDefinition{Name: "Replicas", Type: "integer", JSONPath: ".spec.replicas"}
+	replicasStr := apiextensionsv1.CustomResourceColumnDefinition{Name: "Replicas", Type: "string", JSONPath: ".spec.replicas"}
+	age := apiextensionsv1.CustomResourceColumnDefinition{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"}
+
+	for _, tc := range []struct {
+		name string
+		old  map[string][]apiextensionsv1.CustomResourceColumnDefinition
+		new  map[string][]apiextensionsv1.CustomResourceColumnDefinition
+		err  string
+	}{
+		{
+			name: "no printer column changes, no error",
+			old:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1": {replicas, age}},
+			new:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1": {age, replicas}},
+		},
+		{
+			name: "printer column added, no error",
+			old:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1": {replicas}},
+			new:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1": {replicas, age}},
+		},
+		{
+			name: "version removed, no error",
+			old:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1alpha1": {replicas}, "v1": {replicas}},
+			new:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1": {replicas}},
+		},
+		{
+			name: "printer column type changed, error",
+			old:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1alpha1": {replicas}, "v1": {replicas}},
+			new:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1alpha1": {replicas}, "v1": {replicasStr}},
+			err:  `version "v1", printer column "Replicas" type changed from "integer" to "string"`,
+		},
+		{
+			name: "printer column removed, error",
+			old:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1": {replicas, age}},
+			new:  map[string][]apiextensionsv1.CustomResourceColumnDefinition{"v1": {replicas}},
+			err:  `version "v1", printer column "Age" removed`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NoPrinterColumnChange(crd(tc.old), crd(tc.new))
+			if len(tc.err) > 0 {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNoScaleSubresourceChange(t *testing.T) {
+	crd := func(scale *apiextensionsv1.CustomResourceSubresourceScale) apiextensionsv1.CustomResourceDefinition {
+		version := apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1"}
+		if scale != nil {
+			version.Subresources = &apiextensionsv1.CustomResourceSubresources{Scale: scale}
+		}
+		return apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{version},
+			},
+		}
+	}
+	scale := func(specPath, statusPath, selectorPath string) *apiextensionsv1.CustomResourceSubresourceScale {
+		result := &apiextensionsv1.CustomResourceSubresourceScale{SpecReplicasPath: specPath, StatusReplicasPath: statusPath}
+		if len(selectorPath) > 0 {
+			result.LabelSelectorPath = &selectorPath
+		}
+		return result
+	}
+
+	for _, tc := range []struct {
+		name string
+		old  *apiextensionsv1.CustomResourceSubresourceScale
+		new  *apiextensionsv1.CustomResourceSubresourceScale
+		err  string
+	}{
+		{
+			name: "no scale subresource, no error",
+		},
+		{
+			name: "scale subresource added, no error",
+			new:  scale(".spec.replicas", ".status.replicas", ""),
+		},
+		{
+			name: "no scale subresource changes, no error",
+			old:  scale(".spec.replicas", ".status.replicas", ".status.selector"),
+			new:  scale(".spec.replicas", ".status.replicas", ".status.selector"),
+		},
+		{
+			name: "specReplicasPath changed, error",
+			old:  scale(".spec.replicas", ".status.replicas", ""),
+			new:  scale(".spec.size", ".status.replicas", ""),
+			err:  `version "v1", scale subresource specReplicasPath changed from ".spec.replicas" to ".spec.size"`,
+		},
+		{
+			name: "labelSelectorPath removed, error",
+			old:  scale(".spec.replicas", ".status.replicas", ".status.selector"),
+			new:  scale(".spec.replicas", ".status.replicas", ""),
+			err:  `version "v1", scale subresource labelSelectorPath changed from ".status.selector" to ""`,
+		},
+		{
+			name: "scale subresource removed, error",
+			old:  scale(".spec.replicas", ".status.replicas", ""),
+			err:  `version "v1", scale subresource removed`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NoScaleSubresourceChange(crd(tc.old), crd(tc.new))
+			if len(tc.err) > 0 {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package deprecatedapis
+
+import (
+	"fmt"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Deprecation describes deprecated API version of a kind
+type Deprecation struct {
+	GroupVersionKind schema.GroupVersionKind
+	// DeprecatedIn is Kubernetes version in which API version was
+	// deprecated (nil if deprecation does not depend on cluster version)
+	DeprecatedIn *version.Version
+	// RemovedIn is Kubernetes version in which API
+	// version is no longer served (nil if not scheduled)
+	RemovedIn *version.Version
+	// Replacement is kind in API version that should be used instead
+	Replacement schema.GroupVersionKind
+	// Message overrides default description (e.g. CRD deprecation warning)
+	Message string
+}
+
+// String describes deprecation similarly to
+// warnings returned by Kubernetes API server
+func (d Deprecation) String() string {
+	if len(d.Message) > 0 {
+		if !d.Replacement.Empty() {
+			return fmt.Sprintf("%s (use %s)", d.Message, gvkString(d.Replacement))
+		}
+		return d.Message
+	}
+
+	msg := gvkString(d.GroupVersionKind) + " is deprecated"
+	if d.DeprecatedIn != nil {
+		msg += fmt.Sprintf(" in v%s+", d.DeprecatedIn)
+	}
+	if d.RemovedIn != nil {
+		msg += fmt.Sprintf(", unavailable in v%s+", d.RemovedIn)
+	}
+	if !d.Replacement.Empty() {
+		msg += "; use " + gvkString(d.Replacement)
+	}
+	return msg
+}
+
+func gvkString(gvk schema.GroupVersionKind) string {
+	return gvk.GroupVersion().String() + " " + gvk.Kind
+}
+
+// Deprecations finds deprecated API versions
+// applicable to a particular cluster
+type Deprecations struct {
+	serverVersion *version.Version
+	byGVK         map[schema.GroupVersionKind]Deprecation
+}
+
+// NewDeprecations returns deprecations of built-in Kubernetes APIs applicable
+// to given server version (all are applicable if version is unknown) combined
+// with versions marked as deprecated by given CustomResourceDefinitions
+func NewDeprecations(serverVersion *version.Version, crds []ctlres.Resource) (Deprecations, error) {
+	deprecations := Deprecations{
+		serverVersion: serverVersion,
+		byGVK:         map[schema.GroupVersionKind]Deprecation{},
+	}
+
+	for _, dep := range KubernetesDeprecations {
+		if serverVersion == nil || serverVersion.AtLeast(dep.DeprecatedIn) {
+			deprecations.byGVK[dep.GroupVersionKind] = dep
+		}
+	}
+
+	for _, res := range crds {
+		crd := apiextv1.CustomResourceDefinition{}
+		err := res.AsUncheckedTypedObj(&crd)
+		if err != nil {
+			return Deprecations{}, fmt.Errorf("Converting resource %s to CustomResourceDefinition: %w", res.Description(), err)
+		}
+		for _, dep := range crdDeprecations(crd) {
+			deprecations.byGVK[dep.GroupVersionKind] = dep
+		}
+	}
+
+	return deprecations, nil
+}
+
+// Find returns deprecation for given API version of a kind, if any
+func (d Deprecations) Find(gvk schema.GroupVersionKind) (Deprecation, bool) {
+	dep, found := d.byGVK[gvk]
+	return dep, found
+}
+
+// Removed returns true if API version is no longer served by cluster.
+// False is returned if server version is unknown.
+func (d Deprecations) Removed(dep Deprecation) bool {
+	return d.serverVersion != nil && dep.RemovedIn != nil && d.serverVersion.AtLeast(dep.RemovedIn)
+}
+
+func crdDeprecations(crd apiextv1.CustomResourceDefinition) []Deprecation {
+	// Prefer storage version as replacement, otherwise
+	// first served version that is not deprecated
+	var replacement string
+	for _, ver := range crd.Spec.Versions {
+		if ver.Served && !ver.Deprecated && (len(replacement) == 0 || ver.Storage) {
+			replacement = ver.Name
+		}
+	}
+
+	var result []Deprecation
+	for _, ver := range crd.Spec.Versions {
+		if !ver.Deprecated {
+			continue
+		}
+		dep := Deprecation{
+			GroupVersionKind: schema.GroupVersionKind{Group: crd.Spec.Group, Version: ver.Name, Kind: crd.Spec.Names.Kind},
+		}
+		if ver.DeprecationWarning != nil {
+			dep.Message = *ver.DeprecationWarning
+		}
+		if len(replacement) > 0 {
+			dep.Replacement = dep.GroupVersionKind.GroupKind().WithVersion(replacement)
+		}
+		result = append(result, dep)
+	}
+	return result
+}
+
+func kubernetesDeprecation(apiVersion, kind string, deprecatedIn, removedIn uint, replacementAPIVersion string) Deprecation {
+	dep := Deprecation{
+		GroupVersionKind: schema.FromAPIVersionAndKind(apiVersion, kind),
+		DeprecatedIn:     version.MajorMinor(1, deprecatedIn),
+	}
+	if removedIn > 0 {
+		dep.RemovedIn = version.MajorMinor(1, removedIn)
+	}
+	if len(replacementAPIVersion) > 0 {
+		dep.Replacement = schema.FromAPIVersionAndKind(replacementAPIVersion, kind)
+	}
+	return dep
+}
+
+// KubernetesDeprecations lists deprecated API versions of built-in
+// Kubernetes kinds (based on https://kubernetes.io/docs/reference/using-api/deprecation-guide/)
+var KubernetesDeprecations = []Deprecation{
+	kubernetesDeprecation("extensions/v1beta1", "Deployment", 9, 16, "apps/v1"),
+	kubernetesDeprecation("extensions/v1beta1", "DaemonSet", 9, 16, "apps/v1"),
+	kubernetesDeprecation("extensions/v1beta1", "ReplicaSet", 9, 16, "apps/v1"),
+	kubernetesDeprecation("extensions/v1beta1", "NetworkPolicy", 9, 16, "networking.k8s.io/v1"),
+	kubernetesDeprecation("extensions/v1beta1", "PodSecurityPolicy", 10, 16, "policy/v1beta1"),
+	kubernetesDeprecation("extensions/v1beta1", "Ingress", 14, 22, "networking.k8s.io/v1"),
+	kubernetesDeprecation("apps/v1beta1", "Deployment", 9, 16, "apps/v1"),
+	kubernetesDeprecation("apps/v1beta1", "StatefulSet", 9, 16, "apps/v1"),
+	kubernetesDeprecation("apps/v1beta2", "Deployment", 9, 16, "apps/v1"),
+	kubernetesDeprecation("apps/v1beta2", "DaemonSet", 9, 16, "apps/v1"),
+	kubernetesDeprecation("apps/v1beta2", "ReplicaSet", 9, 16, "apps/v1"),
+	kubernetesDeprecation("apps/v1beta2", "StatefulSet", 9, 16, "apps/v1"),
+	kubernetesDeprecation("networking.k8s.io/v1beta1", "Ingress", 19, 22, "networking.k8s.io/v1"),
+	kubernetesDeprecation("networking.k8s.io/v1beta1", "IngressClass", 19, 22, "networking.k8s.io/v1"),
+	kubernetesDeprecation("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", 16, 22, "apiextensions.k8s.io/v1"),
+	kubernetesDeprecation("apiregistration.k8s.io/v1beta1", "APIService", 19, 22, "apiregistration.k8s.io/v1"),
+	kubernetesDeprecation("admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", 16, 22, "admissionregistration.k8s.io/v1"),
+	kubernetesDeprecation("admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", 16, 22, "admissionregistration.k8s.io/v1"),
+	kubernetesDeprecation("rbac.authorization.k8s.io/v1beta1", "ClusterRole", 17, 22, "rbac.authorization.k8s.io/v1"),
+	kubernetesDeprecation("rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", 17, 22, "rbac.authorization.k8s.io/v1"),
+	kubernetesDeprecation("rbac.authorization.k8s.io/v1beta1", "Role", 17, 22, "rbac.authorization.k8s.io/v1"),
+	kubernetesDeprecation("rbac.authorization.k8s.io/v1beta1", "RoleBinding", 17, 22, "rbac.authorization.k8s.io/v1"),
+	kubernetesDeprecation("scheduling.k8s.io/v1beta1", "PriorityClass", 14, 22, "scheduling.k8s.io/v1"),
+	kubernetesDeprecation("storage.k8s.io/v1beta1", "CSIDriver", 19, 22, "storage.k8s.io/v1"),
+	kubernetesDeprecation("storage.k8s.io/v1beta1", "CSINode", 17, 22, "storage.k8s.io/v1"),
+	kubernetesDeprecation("storage.k8s.io/v1beta1", "StorageClass", 19, 22, "storage.k8s.io/v1"),
+	kubernetesDeprecation("storage.k8s.io/v1beta1", "VolumeAttachment", 19, 22, "storage.k8s.io/v1"),
+	kubernetesDeprecation("storage.k8s.io/v1beta1", "CSIStorageCapacity", 24, 27, "storage.k8s.io/v1"),
+	kubernetesDeprecation("coordination.k8s.io/v1beta1", "Lease", 19, 22, "coordination.k8s.io/v1"),
+	kubernetesDeprecation("certificates.k8s.io/v1beta1", "CertificateSigningRequest", 19, 22, "certificates.k8s.io/v1"),
+	kubernetesDeprecation("batch/v1beta1", "CronJob", 21, 25, "batch/v1"),
+	kubernetesDeprecation("discovery.k8s.io/v1beta1", "EndpointSlice", 21, 25, "discovery.k8s.io/v1"),
+	kubernetesDeprecation("events.k8s.io/v1beta1", "Event", 19, 25, "events.k8s.io/v1"),
+	kubernetesDeprecation("autoscaling/v2beta1", "HorizontalPodAutoscaler", 22, 25, "autoscaling/v2"),
+	kubernetesDeprecation("autoscaling/v2beta2", "HorizontalPodAutoscaler", 23, 26, "autoscaling/v2"),
+	kubernetesDeprecation("policy/v1beta1", "PodDisruptionBudget", 21, 25, "policy/v1"),
+	kubernetesDeprecation("policy/v1beta1", "PodSecurityPolicy", 21, 25, ""),
+	kubernetesDeprecation("node.k8s.io/v1beta1", "RuntimeClass", 20, 25, "node.k8s.io/v1"),
+	kubernetesDeprecation("flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", 23, 26, "flowcontrol.apiserver.k8s.io/v1"),
+	kubernetesDeprecation("flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", 23, 26, "flowcontrol.apiserver.k8s.io/v1"),
+	kubernetesDeprecation("flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", 26, 29, "flowcontrol.apiserver.k8s.io/v1"),
+	kubernetesDeprecation("flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", 26, 29, "flowcontrol.apiserver.k8s.io/v1"),
+	kubernetesDeprecation("flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", 29, 32, "flowcontrol.apiserver.k8s.io/v1"),
+	kubernetesDeprecation("flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", 29, 32, "flowcontrol.apiserver.k8s.io/v1"),
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package deprecatedapis_test
+
+import (
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/deprecatedapis"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestDeprecationsKubernetes(t *testing.T) {
+	cronJobV1beta1 := schema.GroupVersionKind{Group: "batch", Version: "v1beta1", Kind: "CronJob"}
+
+	for _, tc := range []struct {
+		name          string
+		serverVersion *version.Version
+		expected      string
+		removed       bool
+	}{
+		{
+			name:          "not yet deprecated",
+			serverVersion: version.MajorMinor(1, 20),
+		},
+		{
+			name:          "deprecated",
+			serverVersion: version.MajorMinor(1, 21),
+			expected:      "batch/v1beta1 CronJob is deprecated in v1.21+, unavailable in v1.25+; use batch/v1 CronJob",
+		},
+		{
+			name:          "removed",
+			serverVersion: version.MajorMinor(1, 29),
+			expected:      "batch/v1beta1 CronJob is deprecated in v1.21+, unavailable in v1.25+; use batch/v1 CronJob",
+			removed:       true,
+		},
+		{
+			name:     "unknown server version",
+			expected: "batch/v1beta1 CronJob is deprecated in v1.21+, unavailable in v1.25+; use batch/v1 CronJob",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			deprecations, err := deprecatedapis.NewDeprecations(tc.serverVersion, nil)
+			require.NoError(t, err)
+
+			dep, found := deprecations.Find(cronJobV1beta1)
+			if tc.expected == "" {
+				require.False(t, found)
+				return
+			}
+			require.True(t, found)
+			assert.Equal(t, tc.expected, dep.String())
+			assert.Equal(t, tc.removed, deprecations.Removed(dep))
+
+			_, found = deprecations.Find(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"})
+			require.False(t, found)
+		})
+	}
+}
+
+func TestDeprecationsCRD(t *testing.T) {
+	crd := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: false
+    deprecated: true
+  - name: v1beta1
+    served: true
+    storage: false
+    deprecated: true
+    deprecationWarning: example.com/v1beta1 Widget is going away soon
+  - name: v1
+    served: true
+    storage: true
+`))
+
+	deprecations, err := deprecatedapis.NewDeprecations(version.MajorMinor(1, 29), []ctlres.Resource{crd})
+	require.NoError(t, err)
+
+	dep, found := deprecations.Find(schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "Widget"})
+	require.True(t, found)
+	assert.Equal(t, "example.com/v1alpha1 Widget is deprecated; use example.com/v1 Widget", dep.String())
+	assert.False(t, deprecations.Removed(dep))
+
+	dep, found = deprecations.Find(schema.GroupVersionKind{Group: "example.com", Version: "v1beta1", Kind: "Widget"})
+	require.True(t, found)
+	assert.Equal(t, "example.com/v1beta1 Widget is going away soon (use example.com/v1 Widget)", dep.String())
+
+	_, found = deprecations.Find(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	require.False(t, found)
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package deprecatedapis
+
+import (
+	"context"
+	"fmt"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/preflight"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+var _ preflight.Check = (*Preflight)(nil)
+
+// Preflight is an implementation of preflight.Check
+// that warns about resources using API versions that
+// are deprecated or removed in the target cluster
+type Preflight struct {
+	depsFactory cmdcore.DepsFactory
+	ui          ui.UI
+	enabled     bool
+}
+
+func NewPreflight(depsFactory cmdcore.DepsFactory, ui ui.UI, enabled bool) *Preflight {
+	return &Preflight{depsFactory: depsFactory, ui: ui, enabled: enabled}
+}
+
+func (p *Preflight) Enabled() bool {
+	return p.enabled
+}
+
+func (p *Preflight) SetEnabled(enabled bool) {
+	p.enabled = enabled
+}
+
+func (p *Preflight) SetConfig(_ preflight.CheckConfig) error {
+	return nil
+}
+
+func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph) error {
+	coreClient, err := p.depsFactory.CoreClient()
+	if err != nil {
+		return fmt.Errorf("getting core client: %w", err)
+	}
+
+	serverVersionInfo, err := coreClient.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("getting server version: %w", err)
+	}
+
+	serverVersion, err := version.ParseGeneric(serverVersionInfo.GitVersion)
+	if err != nil {
+		return fmt.Errorf("parsing server version %q: %w", serverVersionInfo.GitVersion, err)
+	}
+
+	crds, err := p.crds(ctx, changeGraph)
+	if err != nil {
+		return err
+	}
+
+	deprecations, err := NewDeprecations(version.MajorMinor(serverVersion.Major(), serverVersion.Minor()), crds)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changeGraph.All() {
+		if change.Change.Op() != ctldgraph.ActualChangeOpUpsert {
+			continue
+		}
+		res := change.Change.Resource()
+
+		dep, found := deprecations.Find(res.GroupVersion().WithKind(res.Kind()))
+		if !found {
+			continue
+		}
+		if deprecations.Removed(dep) {
+			p.ui.PrintLinef("Warning: %s: %s (not served by cluster v%s)", res.Description(), dep, serverVersion)
+		} else {
+			p.ui.PrintLinef("Warning: %s: %s", res.Description(), dep)
+		}
+	}
+
+	return nil
+}
+
+// crds returns CustomResourceDefinitions in the cluster followed
+// by ones that are about to be applied, so that latter take precedence.
+// CRDs in the cluster are skipped if caller is not allowed to list them.
+func (p *Preflight) crds(ctx context.Context, changeGraph *ctldgraph.ChangeGraph) ([]ctlres.Resource, error) {
+	dynamicClient, err := p.depsFactory.DynamicClient(cmdcore.DynamicClientOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("getting dynamic client: %w", err)
+	}
+
+	var crds []ctlres.Resource
+
+	crdList, err := dynamicClient.Resource(apiextv1.SchemeGroupVersion.WithResource("customresourcedefinitions")).
+		List(ctx, metav1.ListOptions{})
+	switch {
+	case err == nil:
+		for _, item := range crdList.Items {
+			crds = append(crds, ctlres.NewResourceUnstructured(item, ctlres.ResourceType{}))
+		}
+	case apierrors.IsForbidden(err):
+		p.ui.PrintLinef("Warning: Skipping deprecations of CustomResourceDefinitions in the cluster: %s", err)
+	default:
+		return nil, fmt.Errorf("listing CustomResourceDefinitions: %w", err)
+	}
+
+	for _, change := range changeGraph.All() {
+		res := change.Change.Resource()
+		if change.Change.Op() == ctldgraph.ActualChangeOpUpsert &&
+			res.GroupVersion().WithKind(res.Kind()) == apiextv1.SchemeGroupVersion.WithKind("CustomResourceDefinition") {
+			crds = append(crds, res)
+		}
+	}
+
+	return crds, nil
+}
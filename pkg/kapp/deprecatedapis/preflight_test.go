@@ -0,0 +1,92 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package deprecatedapis_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"carvel.dev/kapp/pkg/kapp/deprecatedapis"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+func TestPreflightForbiddenToListCRDs(t *testing.T) {
+	cronJob := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "batch/v1beta1", "kind": "CronJob", "metadata": {"name": "cj", "namespace": "default"}}`))
+
+	changeGraph, err := ctldgraph.NewChangeGraph([]ctldgraph.ActualChange{
+		actualChange{cronJob, ctldgraph.ActualChangeOpUpsert},
+	}, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	check := deprecatedapis.NewPreflight(fakeDepsFactory{}, ui.NewWriterUI(out, out, ui.NewNoopLogger()), true)
+
+	require.NoError(t, check.Run(context.Background(), changeGraph))
+	require.Contains(t, out.String(), "Warning: Skipping deprecations of CustomResourceDefinitions in the cluster")
+	require.Contains(t, out.String(), "Warning: cronjob/cj (batch/v1beta1) namespace: default: "+
+		"batch/v1beta1 CronJob is deprecated in v1.21+, unavailable in v1.25+; use batch/v1 CronJob (not served by cluster v1.29.0)")
+}
+
+type actualChange struct {
+	res ctlres.Resource
+	op  ctldgraph.ActualChangeOp
+}
+
+func (a actualChange) Resource() ctlres.Resource    { return a.res }
+func (a actualChange) Op() ctldgraph.ActualChangeOp { return a.op }
+
+type fakeDepsFactory struct {
+	cmdcore.DepsFactory
+}
+
+func (fakeDepsFactory) CoreClient() (kubernetes.Interface, error) { return fakeCoreClient{}, nil }
+func (fakeDepsFactory) DynamicClient(cmdcore.DynamicClientOpts) (dynamic.Interface, error) {
+	return fakeDynamicClient{}, nil
+}
+
+type fakeCoreClient struct {
+	kubernetes.Interface
+}
+
+func (fakeCoreClient) Discovery() discovery.DiscoveryInterface { return fakeDiscovery{} }
+
+type fakeDiscovery struct {
+	discovery.DiscoveryInterface
+}
+
+func (fakeDiscovery) ServerVersion() (*k8sversion.Info, error) {
+	return &k8sversion.Info{GitVersion: "v1.29.0"}, nil
+}
+
+// fakeDynamicClient forbids listing any resource
+type fakeDynamicClient struct {
+	dynamic.Interface
+}
+
+func (fakeDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return fakeResourceClient{gvr: gvr}
+}
+
+type fakeResourceClient struct {
+	dynamic.NamespaceableResourceInterface
+	gvr schema.GroupVersionResource
+}
+
+func (c fakeResourceClient) List(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return nil, apierrors.NewForbidden(c.gvr.GroupResource(), "", nil)
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+var (
+	// Matches runs of characters that may be used in resource names
+	anonymizerTokenRegexp = regexp.MustCompile(`[A-Za-z0-9._-]+`)
+)
+
+// Anonymizer replaces resource names and namespaces with stable
+// hashed placeholders (same name always results in same placeholder).
+// Only whole tokens are replaced, hence a name that is a part of
+// a larger identifier (e.g. name 'app' in 'app-config') is kept as is.
+type Anonymizer struct {
+	replacements map[string]string
+}
+
+func NewAnonymizer(resources []ctlres.Resource) *Anonymizer {
+	a := &Anonymizer{replacements: map[string]string{}}
+
+	// Register namespaces first so that a value that is both
+	// a namespace and a name is consistently shown as a namespace
+	for _, res := range resources {
+		a.add(res.Namespace(), "ns")
+	}
+	for _, res := range resources {
+		a.add(res.Name(), "name")
+	}
+
+	return a
+}
+
+func (a *Anonymizer) add(val, prefix string) {
+	if len(val) == 0 {
+		return
+	}
+	if _, found := a.replacements[val]; found {
+		return
+	}
+	a.replacements[val] = fmt.Sprintf("%s-%x", prefix, sha256.Sum256([]byte(val)))[:len(prefix)+1+10]
+}
+
+// Value returns placeholder for a single name or namespace
+func (a *Anonymizer) Value(val string) string {
+	if replacement, found := a.replacements[val]; found {
+		return replacement
+	}
+	return val
+}
+
+// String replaces all known names and namespaces found in given text
+func (a *Anonymizer) String(str string) string {
+	return anonymizerTokenRegexp.ReplaceAllStringFunc(str, a.Value)
+}
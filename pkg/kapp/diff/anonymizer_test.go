@@ -0,0 +1,67 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymizer(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "app-config", "namespace": "team-a"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "app", "namespace": "team-a"}}`)),
+	}
+
+	anonymizer := ctldiff.NewAnonymizer(resources)
+
+	configName := anonymizer.Value("app-config")
+	appName := anonymizer.Value("app")
+	ns := anonymizer.Value("team-a")
+
+	require.True(t, strings.HasPrefix(configName, "name-"), configName)
+	require.True(t, strings.HasPrefix(appName, "name-"), appName)
+	require.True(t, strings.HasPrefix(ns, "ns-"), ns)
+	require.NotEqual(t, configName, appName, "Expected different names to get different placeholders")
+
+	// Placeholders are stable across anonymizers
+	require.Equal(t, appName, ctldiff.NewAnonymizer(resources[1:]).Value("app"))
+
+	// Unknown values are kept
+	require.Equal(t, "ConfigMap", anonymizer.Value("ConfigMap"))
+
+	text := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: team-a
+spec:
+  template:
+    spec:
+      volumes:
+      - configMap:
+          name: app-config
+`
+	expected := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ` + appName + `
+  namespace: ` + ns + `
+spec:
+  template:
+    spec:
+      volumes:
+      - configMap:
+          name: ` + configName + `
+`
+	require.Equal(t, expected, anonymizer.String(text))
+	require.Equal(t, "deployment/"+appName+" (apps/v1) namespace: "+ns,
+		anonymizer.String("deployment/app (apps/v1) namespace: team-a"))
+}
@@ -53,6 +53,33 @@ func (d ConfigurableTextDiff) Masked(rules []ctlconf.DiffMaskRule) (TextDiff, er
 	return d.calculate(existingRes, newRes), nil
 }
 
+// MaskedFieldChanges returns field level changes
+// between masked existing and new resources
+func (d ConfigurableTextDiff) MaskedFieldChanges(rules []ctlconf.DiffMaskRule) ([]FieldChange, error) {
+	if d.ignored {
+		return nil, nil
+	}
+
+	var existingRes, newRes ctlres.Resource
+	var err error
+
+	if d.existingRes != nil {
+		existingRes, err = NewMaskedResource(d.existingRes, rules).Resource()
+		if err != nil {
+			return nil, fmt.Errorf("Masking existing resource: %w", err)
+		}
+	}
+
+	if d.newRes != nil {
+		newRes, err = NewMaskedResource(d.newRes, rules).Resource()
+		if err != nil {
+			return nil, fmt.Errorf("Masking new resource: %w", err)
+		}
+	}
+
+	return NewFieldChanges(existingRes, newRes), nil
+}
+
 func (d ConfigurableTextDiff) calculate(existingRes, newRes ctlres.Resource) TextDiff {
 	existingLines := []string{}
 	newLines := []string{}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	FieldChangeOpAdd     = "add"
+	FieldChangeOpRemove  = "remove"
+	FieldChangeOpReplace = "replace"
+)
+
+// FieldChange is a change to a single field of a resource.
+// Path is a JSON pointer (e.g. "/spec/replicas").
+type FieldChange struct {
+	Op       string      `json:"op"`
+	Path     string      `json:"path"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+// NewFieldChanges returns field level changes between existing
+// and new resource. Missing resource is treated as an empty object.
+func NewFieldChanges(existingRes, newRes ctlres.Resource) []FieldChange {
+	existingObj := map[string]interface{}{}
+	if existingRes != nil {
+		existingObj = existingRes.UnstructuredObject()
+	}
+	newObj := map[string]interface{}{}
+	if newRes != nil {
+		newObj = newRes.UnstructuredObject()
+	}
+	return fieldChanges(existingObj, newObj, "")
+}
+
+func fieldChanges(left, right interface{}, path string) []FieldChange {
+	switch typedLeft := left.(type) {
+	case map[string]interface{}:
+		typedRight, ok := right.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := map[string]struct{}{}
+		for k := range typedLeft {
+			keys[k] = struct{}{}
+		}
+		for k := range typedRight {
+			keys[k] = struct{}{}
+		}
+
+		var sortedKeys []string
+		for k := range keys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+
+		var changes []FieldChange
+		for _, k := range sortedKeys {
+			keyPath := path + "/" + fieldChangePathEscaper.Replace(k)
+			leftVal, leftFound := typedLeft[k]
+			rightVal, rightFound := typedRight[k]
+
+			switch {
+			case !leftFound:
+				changes = append(changes, FieldChange{Op: FieldChangeOpAdd, Path: keyPath, NewValue: rightVal})
+			case !rightFound:
+				changes = append(changes, FieldChange{Op: FieldChangeOpRemove, Path: keyPath, OldValue: leftVal})
+			default:
+				changes = append(changes, fieldChanges(leftVal, rightVal, keyPath)...)
+			}
+		}
+		return changes
+
+	case []interface{}:
+		typedRight, ok := right.([]interface{})
+		if !ok {
+			break
+		}
+
+		var changes []FieldChange
+		for i := 0; i < len(typedLeft) || i < len(typedRight); i++ {
+			idxPath := fmt.Sprintf("%s/%d", path, i)
+			switch {
+			case i >= len(typedLeft):
+				changes = append(changes, FieldChange{Op: FieldChangeOpAdd, Path: idxPath, NewValue: typedRight[i]})
+			case i >= len(typedRight):
+				changes = append(changes, FieldChange{Op: FieldChangeOpRemove, Path: idxPath, OldValue: typedLeft[i]})
+			default:
+				changes = append(changes, fieldChanges(typedLeft[i], typedRight[i], idxPath)...)
+			}
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(left, right) {
+		return nil
+	}
+	return []FieldChange{{Op: FieldChangeOpReplace, Path: path, OldValue: left, NewValue: right}}
+}
+
+// fieldChangePathEscaper escapes keys as per JSON pointer spec (RFC 6901)
+var fieldChangePathEscaper = strings.NewReplacer("~", "~0", "/", "~1")
@@ -0,0 +1,61 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFieldChanges(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+  annotations:
+    a/b: x
+data:
+  changed: "1"
+  removed: "2"
+  same: "3"
+list: [1, 2, 3]
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+  annotations:
+    a/b: z
+data:
+  changed: "10"
+  added: "4"
+  same: "3"
+list: [1, 5]
+`))
+
+	require.Equal(t, []ctldiff.FieldChange{
+		{Op: "add", Path: "/data/added", NewValue: "4"},
+		{Op: "replace", Path: "/data/changed", OldValue: "1", NewValue: "10"},
+		{Op: "remove", Path: "/data/removed", OldValue: "2"},
+		{Op: "replace", Path: "/list/1", OldValue: float64(2), NewValue: float64(5)},
+		{Op: "remove", Path: "/list/2", OldValue: float64(3)},
+		{Op: "replace", Path: "/metadata/annotations/a~1b", OldValue: "x", NewValue: "z"},
+	}, ctldiff.NewFieldChanges(existingRes, newRes))
+
+	require.Equal(t, []ctldiff.FieldChange{
+		{Op: "remove", Path: "/apiVersion", OldValue: "v1"},
+		{Op: "remove", Path: "/data", OldValue: map[string]interface{}{"changed": "1", "removed": "2", "same": "3"}},
+		{Op: "remove", Path: "/kind", OldValue: "ConfigMap"},
+		{Op: "remove", Path: "/list", OldValue: []interface{}{float64(1), float64(2), float64(3)}},
+		{Op: "remove", Path: "/metadata", OldValue: map[string]interface{}{"name": "my-cm", "annotations": map[string]interface{}{"a/b": "x"}}},
+	}, ctldiff.NewFieldChanges(existingRes, nil))
+
+	require.Nil(t, ctldiff.NewFieldChanges(existingRes, existingRes))
+}
@@ -0,0 +1,21 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// DiffResources returns diff between two resources rendered the same
+// way as changes are shown during deploy (with line numbers and context).
+// Either resource may be nil to represent a missing resource.
+// Empty string is returned if resources are identical.
+func DiffResources(a, b ctlres.Resource) (string, error) {
+	textDiff := NewConfigurableTextDiff(a, b, false, ChangeOpts{})
+	if !textDiff.Full().HasChanges() {
+		return "", nil
+	}
+
+	return NewTextDiffView(textDiff, nil, TextDiffViewOpts{Context: 2, LineNumbers: true}).String(), nil
+}
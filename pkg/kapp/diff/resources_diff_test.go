@@ -0,0 +1,60 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffResources(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+  namespace: default
+data:
+  a: "1"
+  b: "2"
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+  namespace: default
+data:
+  a: "1"
+  b: "20"
+`))
+
+	t.Run("identical resources", func(t *testing.T) {
+		diff, err := ctldiff.DiffResources(existingRes, existingRes.DeepCopy())
+		require.NoError(t, err)
+		require.Empty(t, diff)
+	})
+
+	t.Run("different resources", func(t *testing.T) {
+		diff, err := ctldiff.DiffResources(existingRes, newRes)
+		require.NoError(t, err)
+		require.Equal(t, `  ...
+  2,  2     a: "1"
+  3     -   b: "2"
+      3 +   b: "20"
+  4,  4   kind: ConfigMap
+  5,  5   metadata:
+`, diff)
+	})
+
+	t.Run("missing resource", func(t *testing.T) {
+		diff, err := ctldiff.DiffResources(nil, newRes)
+		require.NoError(t, err)
+		require.Contains(t, diff, `+   b: "20"`)
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	"github.com/k14s/difflib"
+)
+
+const unifiedDiffNullPath = "/dev/null"
+
+// UnifiedDiffView renders diff in unified diff format
+// (as produced by "diff -u") so that it could be consumed
+// by standard tooling (e.g. "patch" or "git apply")
+type UnifiedDiffView struct {
+	diff      *ConfigurableTextDiff
+	maskRules []ctlconf.DiffMaskRule
+	opts      TextDiffViewOpts
+	path      string
+}
+
+// NewUnifiedDiffView returns view of a diff for a file identified by path.
+// Context and Mask options are respected; LineNumbers is not applicable.
+func NewUnifiedDiffView(diff *ConfigurableTextDiff, maskRules []ctlconf.DiffMaskRule,
+	opts TextDiffViewOpts, path string) UnifiedDiffView {
+
+	return UnifiedDiffView{diff, maskRules, opts, path}
+}
+
+// String returns an empty string when there are no changes
+func (v UnifiedDiffView) String() (string, error) {
+	textDiff := v.diff.Full()
+
+	if v.opts.Mask {
+		var err error
+		textDiff, err = v.diff.Masked(v.maskRules)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	recs := textDiff.Records()
+
+	// Trailing newline of YAML produces an empty last line
+	if len(recs) > 0 && recs[len(recs)-1].Payload == "" {
+		recs = recs[:len(recs)-1]
+	}
+
+	if !(TextDiff{recs}).HasChanges() {
+		return "", nil
+	}
+
+	oldPath := "a/" + v.path
+	newPath := "b/" + v.path
+	if v.diff.existingRes == nil {
+		oldPath = unifiedDiffNullPath
+	}
+	if v.diff.newRes == nil {
+		newPath = unifiedDiffNullPath
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldPath, newPath)
+
+	for _, hunk := range v.hunks(recs) {
+		v.writeHunk(&sb, recs, hunk[0], hunk[1])
+	}
+
+	return sb.String(), nil
+}
+
+// hunks returns [start, end) ranges of records that include
+// changed records together with surrounding context records
+func (v UnifiedDiffView) hunks(recs []difflib.DiffRecord) [][2]int {
+	if v.opts.Context < 0 {
+		return [][2]int{{0, len(recs)}}
+	}
+
+	var hunks [][2]int
+
+	for i, rec := range recs {
+		if rec.Delta == difflib.Common {
+			continue
+		}
+
+		start := max(0, i-v.opts.Context)
+		end := min(len(recs), i+v.opts.Context+1)
+
+		if len(hunks) > 0 && hunks[len(hunks)-1][1] >= start {
+			hunks[len(hunks)-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+		}
+	}
+
+	return hunks
+}
+
+func (v UnifiedDiffView) writeHunk(sb *strings.Builder, recs []difflib.DiffRecord, start, end int) {
+	// Line numbers are 1-based and count only lines
+	// of respective side of the diff
+	oldStart, newStart := 1, 1
+	for _, rec := range recs[:start] {
+		if rec.Delta != difflib.RightOnly {
+			oldStart++
+		}
+		if rec.Delta != difflib.LeftOnly {
+			newStart++
+		}
+	}
+
+	var oldCount, newCount int
+	var lines []string
+
+	for _, rec := range recs[start:end] {
+		switch rec.Delta {
+		case difflib.RightOnly:
+			newCount++
+			lines = append(lines, "+"+rec.Payload)
+		case difflib.LeftOnly:
+			oldCount++
+			lines = append(lines, "-"+rec.Payload)
+		case difflib.Common:
+			oldCount++
+			newCount++
+			lines = append(lines, " "+rec.Payload)
+		}
+	}
+
+	// Empty ranges refer to the line after which lines are added/removed
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, line := range lines {
+		sb.WriteString(line + "\n")
+	}
+}
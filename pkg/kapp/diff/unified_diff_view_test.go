@@ -0,0 +1,90 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiffView(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+  namespace: default
+data:
+  a: "1"
+  b: "2"
+  c: "3"
+  d: "4"
+  e: "5"
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+  namespace: default
+data:
+  a: "1"
+  b: "2"
+  c: "30"
+  d: "4"
+  e: "5"
+`))
+
+	path := "default/configmap/my-cm.yaml"
+	opts := ctldiff.TextDiffViewOpts{Context: 1}
+
+	t.Run("single field change", func(t *testing.T) {
+		diff := ctldiff.NewChange(existingRes, newRes, nil, nil, ctldiff.ChangeOpts{}).ConfigurableTextDiff()
+
+		patch, err := ctldiff.NewUnifiedDiffView(diff, nil, opts, path).String()
+		require.NoError(t, err)
+		require.Equal(t, `--- a/default/configmap/my-cm.yaml
++++ b/default/configmap/my-cm.yaml
+@@ -4,3 +4,3 @@
+   b: "2"
+-  c: "3"
++  c: "30"
+   d: "4"
+`, patch)
+	})
+
+	t.Run("added resource", func(t *testing.T) {
+		diff := ctldiff.NewChange(nil, newRes, nil, nil, ctldiff.ChangeOpts{}).ConfigurableTextDiff()
+
+		patch, err := ctldiff.NewUnifiedDiffView(diff, nil, opts, path).String()
+		require.NoError(t, err)
+		require.Equal(t, `--- /dev/null
++++ b/default/configmap/my-cm.yaml
+@@ -0,0 +1,11 @@
++apiVersion: v1
++data:
++  a: "1"
++  b: "2"
++  c: "30"
++  d: "4"
++  e: "5"
++kind: ConfigMap
++metadata:
++  name: my-cm
++  namespace: default
+`, patch)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		diff := ctldiff.NewChange(existingRes, existingRes, nil, nil, ctldiff.ChangeOpts{}).ConfigurableTextDiff()
+
+		patch, err := ctldiff.NewUnifiedDiffView(diff, nil, opts, path).String()
+		require.NoError(t, err)
+		require.Equal(t, "", patch)
+	})
+}
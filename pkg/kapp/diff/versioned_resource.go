@@ -51,7 +51,7 @@ func (d VersionedResource) Version() int {
 	return verInt
 }
 
-func (d VersionedResource) UniqVersionedKey() ctlres.UniqueResourceKey {
+func (d VersionedResource) UniqVersionedKey() ctlres.UnversionedResourceKey {
 	baseName, _ := d.BaseNameAndVersion()
 	return ctlres.NewUniqueResourceKeyWithCustomName(d.res, baseName)
 }
@@ -5,6 +5,7 @@ package diffgraph
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -176,6 +177,26 @@ func (g *ChangeGraph) Linearized() ([][]*Change, []*Change) {
 	}
 }
 
+// ApplyOrder returns all changes sorted such that each change
+// comes after all changes it is waiting for. Changes that cannot
+// be ordered (e.g. due to being blocked) are placed last.
+func (g *ChangeGraph) ApplyOrder() []*Change {
+	linearizedChangeSections, blockedChanges := g.Linearized()
+
+	var result []*Change
+	for _, changes := range linearizedChangeSections {
+		result = append(result, changes...)
+	}
+	return append(result, blockedChanges...)
+}
+
+// DeleteOrder returns all changes in reverse of ApplyOrder
+func (g *ChangeGraph) DeleteOrder() []*Change {
+	result := g.ApplyOrder()
+	slices.Reverse(result)
+	return result
+}
+
 func (g *ChangeGraph) AllMatching(matchFunc func(*Change) bool) []*Change {
 	var result []*Change
 	// Need to do this _only_ at the first level since
@@ -188,6 +209,12 @@ func (g *ChangeGraph) AllMatching(matchFunc func(*Change) bool) []*Change {
 	return result
 }
 
+// Filtered returns a new graph including only changes matching
+// given func. Included changes may still be waiting for excluded ones.
+func (g *ChangeGraph) Filtered(matchFunc func(*Change) bool) *ChangeGraph {
+	return &ChangeGraph{g.AllMatching(matchFunc), g.logger}
+}
+
 func (g *ChangeGraph) RemoveMatching(matchFunc func(*Change) bool) {
 	var result []*Change
 	// Need to do this _only_ at the first level since
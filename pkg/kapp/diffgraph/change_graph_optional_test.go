@@ -145,3 +145,71 @@ changeRuleBindings:
 
 	require.Equal(t, expectedOutput, output)
 }
+
+func TestChangeGraphWithApplyPriorityKinds(t *testing.T) {
+	configYAML := `
+kind: Namespace
+apiVersion: v1
+metadata:
+  name: app1
+---
+kind: Service
+apiVersion: v1
+metadata:
+  name: app
+  namespace: app1
+---
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: app-config
+  namespace: app1
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: app-secret
+  namespace: app1
+`
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	require.NoError(t, err)
+
+	priorityConfig, err := ctlconf.NewApplyPriorityKindsConfig([]string{"Secret", "ConfigMap"})
+	require.NoError(t, err)
+
+	conf = conf.WithConfig(priorityConfig)
+
+	opts := buildGraphOpts{
+		resourcesBs:         configYAML,
+		op:                  ctldgraph.ActualChangeOpUpsert,
+		changeGroupBindings: conf.ChangeGroupBindings(),
+		changeRuleBindings:  conf.ChangeRuleBindings(),
+	}
+
+	graph, err := buildChangeGraphWithOpts(opts, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	// Namespace is still upserted first since
+	// priority kinds do not override default rules
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(upsert) namespace/app1 (v1) cluster
+(upsert) service/app (v1) namespace: app1
+  (upsert) namespace/app1 (v1) cluster
+  (upsert) secret/app-secret (v1) namespace: app1
+    (upsert) namespace/app1 (v1) cluster
+  (upsert) configmap/app-config (v1) namespace: app1
+    (upsert) namespace/app1 (v1) cluster
+    (upsert) secret/app-secret (v1) namespace: app1
+      (upsert) namespace/app1 (v1) cluster
+(upsert) configmap/app-config (v1) namespace: app1
+  (upsert) namespace/app1 (v1) cluster
+  (upsert) secret/app-secret (v1) namespace: app1
+    (upsert) namespace/app1 (v1) cluster
+(upsert) secret/app-secret (v1) namespace: app1
+  (upsert) namespace/app1 (v1) cluster
+`)
+
+	require.Equal(t, expectedOutput, output)
+}
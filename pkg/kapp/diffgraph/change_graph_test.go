@@ -836,6 +836,55 @@ type: kubernetes.io/service-account-token
 	require.Equal(t, expectedOutput, output)
 }
 
+func TestChangeGraphApplyAndDeleteOrder(t *testing.T) {
+	configYAML := `
+kind: Job
+metadata:
+  name: migrations
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/db-migrations"
+    kapp.k14s.io/change-rule: "upsert after upserting apps.big.co/db"
+---
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/deployment"
+    kapp.k14s.io/change-rule: "upsert after upserting apps.big.co/db-migrations"
+---
+kind: StatefulSet
+metadata:
+  name: db
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/db"
+`
+
+	graph, err := buildChangeGraph(configYAML, ctldgraph.ActualChangeOpUpsert, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	descs := func(changes []*ctldgraph.Change) []string {
+		var result []string
+		for _, change := range changes {
+			result = append(result, change.Description())
+		}
+		return result
+	}
+
+	expectedApplyOrder := []string{
+		"(upsert) statefulset/db () cluster",
+		"(upsert) job/migrations () cluster",
+		"(upsert) deployment/app () cluster",
+	}
+	require.Equal(t, expectedApplyOrder, descs(graph.ApplyOrder()))
+
+	expectedDeleteOrder := []string{
+		"(upsert) deployment/app () cluster",
+		"(upsert) job/migrations () cluster",
+		"(upsert) statefulset/db () cluster",
+	}
+	require.Equal(t, expectedDeleteOrder, descs(graph.DeleteOrder()))
+}
+
 func buildChangeGraph(resourcesBs string, op ctldgraph.ActualChangeOp, t *testing.T) (*ctldgraph.ChangeGraph, error) {
 	return buildChangeGraphWithOpts(buildGraphOpts{resourcesBs: resourcesBs, op: op}, t)
 }
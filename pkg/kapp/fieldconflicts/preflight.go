@@ -0,0 +1,156 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fieldconflicts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/preflight"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// DefaultFieldManager is the name of field manager used for dry run
+// server side apply requests unless configured otherwise. It matches
+// default field manager name recorded for updates made by kapp.
+const DefaultFieldManager = "kapp"
+
+var _ preflight.CheckWithFieldManager = (*Preflight)(nil)
+
+// Preflight is an implementation of preflight.Check
+// that reports fields of existing resources that are owned
+// by other field managers and would be changed by kapp
+type Preflight struct {
+	depsFactory  cmdcore.DepsFactory
+	enabled      bool
+	fieldManager string
+}
+
+func NewPreflight(depsFactory cmdcore.DepsFactory, enabled bool) *Preflight {
+	return &Preflight{depsFactory: depsFactory, enabled: enabled, fieldManager: DefaultFieldManager}
+}
+
+func (p *Preflight) Enabled() bool {
+	return p.enabled
+}
+
+func (p *Preflight) SetEnabled(enabled bool) {
+	p.enabled = enabled
+}
+
+// SetFieldManager sets field manager used by kapp for resource
+// writes so that fields owned by it are not reported as conflicts
+func (p *Preflight) SetFieldManager(name string) {
+	p.fieldManager = name
+}
+
+func (p *Preflight) SetConfig(_ preflight.CheckConfig) error {
+	return nil
+}
+
+func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph) error {
+	dynamicClient, err := p.depsFactory.DynamicClient(cmdcore.DynamicClientOpts{})
+	if err != nil {
+		return fmt.Errorf("getting dynamic client: %w", err)
+	}
+
+	mapper, err := p.depsFactory.RESTMapper()
+	if err != nil {
+		return fmt.Errorf("getting RESTMapper: %w", err)
+	}
+
+	validateErrs := []error{}
+	for _, change := range changeGraph.All() {
+		if change.Change.Op() != ctldgraph.ActualChangeOpUpsert {
+			continue
+		}
+		res := change.Change.Resource()
+
+		err := dryRunApply(ctx, dynamicClient, mapper, res, p.fieldManager)
+		for _, conflict := range Conflicts(err) {
+			if conflict.Manager == p.fieldManager {
+				continue
+			}
+			validateErrs = append(validateErrs, fmt.Errorf("%s: field '%s' is owned by field manager '%s'",
+				res.Description(), conflict.Field, conflict.Manager))
+		}
+	}
+
+	if len(validateErrs) > 0 {
+		baseErr := errors.New("validation for field ownership conflicts failed")
+		return errors.Join(append([]error{baseErr}, validateErrs...)...)
+	}
+
+	return nil
+}
+
+// dryRunApply performs a dry run server side apply of a resource
+// without forcing ownership of conflicting fields
+func dryRunApply(ctx context.Context, dynamicClient dynamic.Interface, mapper meta.RESTMapper,
+	res ctlres.Resource, fieldManager string) error {
+	mapping, err := mapper.RESTMapping(res.GroupKind(), res.GroupVersion().Version)
+	if err != nil {
+		return err
+	}
+
+	var resClient dynamic.ResourceInterface = dynamicClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resClient = dynamicClient.Resource(mapping.Resource).Namespace(res.Namespace())
+	}
+
+	obj := &unstructured.Unstructured{Object: res.DeepCopyRaw()}
+	obj.SetManagedFields(nil)
+
+	_, err = resClient.Apply(ctx, res.Name(), obj, metav1.ApplyOptions{
+		DryRun:       []string{metav1.DryRunAll},
+		FieldManager: fieldManager,
+		Force:        false,
+	})
+	return err
+}
+
+// Conflict is a field owned by another field manager
+type Conflict struct {
+	Field   string
+	Manager string
+}
+
+// Conflicts returns field ownership conflicts reported in a
+// server side apply error. Other errors are ignored, as they
+// are not related to field ownership and will surface during apply.
+func Conflicts(err error) []Conflict {
+	var statusErr *apierrors.StatusError
+	if !apierrors.IsConflict(err) || !errors.As(err, &statusErr) || statusErr.ErrStatus.Details == nil {
+		return nil
+	}
+
+	var conflicts []Conflict
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflicts = append(conflicts, Conflict{Field: cause.Field, Manager: conflictManager(cause.Message)})
+	}
+	return conflicts
+}
+
+// conflictManager extracts field manager name from conflict
+// message (e.g. `conflict with "kubectl-edit" using v1`)
+func conflictManager(msg string) string {
+	var manager string
+	_, err := fmt.Sscanf(strings.TrimPrefix(msg, "conflict with "), "%q", &manager)
+	if err != nil {
+		return msg
+	}
+	return manager
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fieldconflicts_test
+
+import (
+	"errors"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/fieldconflicts"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestConflicts(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected []fieldconflicts.Conflict
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name: "unrelated error",
+			err:  errors.New("some error"),
+		},
+		{
+			name: "non apply conflict error",
+			err:  apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "cm", errors.New("object modified")),
+		},
+		{
+			name: "apply conflict error",
+			err: apierrors.NewApplyConflict([]metav1.StatusCause{
+				{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-patch" using v1`, Field: ".data.key"},
+				{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kapp" using v1`, Field: ".data.other"},
+				{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "controller"`, Field: ".spec.replicas"},
+			}, "Apply failed with 3 conflicts"),
+			expected: []fieldconflicts.Conflict{
+				{Field: ".data.key", Manager: "kubectl-patch"},
+				{Field: ".data.other", Manager: "kapp"},
+				{Field: ".spec.replicas", Manager: "controller"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, fieldconflicts.Conflicts(tc.err))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagereferences
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/preflight"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+var _ preflight.Check = (*Preflight)(nil)
+
+var containerFields = []string{"initContainers", "containers", "ephemeralContainers"}
+
+// Preflight is an implementation of preflight.Check
+// that makes sure container image references of
+// workload resources are well-formed
+type Preflight struct {
+	enabled bool
+}
+
+func NewPreflight(enabled bool) *Preflight {
+	return &Preflight{enabled: enabled}
+}
+
+func (p *Preflight) Enabled() bool {
+	return p.enabled
+}
+
+func (p *Preflight) SetEnabled(enabled bool) {
+	p.enabled = enabled
+}
+
+func (p *Preflight) SetConfig(_ preflight.CheckConfig) error {
+	return nil
+}
+
+func (p *Preflight) Run(_ context.Context, changeGraph *ctldgraph.ChangeGraph) error {
+	validateErrs := []error{}
+	for _, change := range changeGraph.All() {
+		if change.Change.Op() != ctldgraph.ActualChangeOpUpsert {
+			continue
+		}
+		res := change.Change.Resource()
+		for _, image := range Images(res) {
+			if _, err := ParseReference(image); err != nil {
+				validateErrs = append(validateErrs, fmt.Errorf("%s: %w", res.Description(), err))
+			}
+		}
+	}
+
+	if len(validateErrs) > 0 {
+		baseErr := errors.New("validation for image references failed")
+		return errors.Join(append([]error{baseErr}, validateErrs...)...)
+	}
+
+	return nil
+}
+
+// Images returns container image references found in the
+// pod spec of a workload resource. Other resources have none.
+func Images(res ctlres.Resource) []string {
+	var images []string
+	for _, container := range ctlres.PodSpecContainers(res, containerFields) {
+		if image, ok := container["image"].(string); ok {
+			images = append(images, image)
+		}
+	}
+	return images
+}
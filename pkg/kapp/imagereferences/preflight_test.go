@@ -0,0 +1,48 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagereferences_test
+
+import (
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/imagereferences"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImages(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		res      string
+		expected []string
+	}{
+		{
+			name: "pod",
+			res: `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "pod"},
+				"spec": {"initContainers": [{"name": "init", "image": "busybox"}], "containers": [{"name": "app", "image": "nginx:1.25"}]}}`,
+			expected: []string{"busybox", "nginx:1.25"},
+		},
+		{
+			name: "deployment",
+			res: `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "deploy"},
+				"spec": {"template": {"spec": {"containers": [{"name": "app", "image": "nginx::latest"}]}}}}`,
+			expected: []string{"nginx::latest"},
+		},
+		{
+			name: "cron job",
+			res: `{"apiVersion": "batch/v1", "kind": "CronJob", "metadata": {"name": "cron"},
+				"spec": {"jobTemplate": {"spec": {"template": {"spec": {"containers": [{"name": "app", "image": "busybox"}]}}}}}}`,
+			expected: []string{"busybox"},
+		},
+		{
+			name: "non workload",
+			res:  `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm"}, "data": {"image": "nginx"}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := ctlres.MustNewResourceFromBytes([]byte(tc.res))
+			assert.Equal(t, tc.expected, imagereferences.Images(res))
+		})
+	}
+}
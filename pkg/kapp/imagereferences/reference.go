@@ -0,0 +1,51 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagereferences
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	maxNameLength = 255
+)
+
+var (
+	// Grammar follows github.com/distribution/reference
+	domainComponent = `(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])`
+	domain          = domainComponent + `(?:\.` + domainComponent + `)*(?::[0-9]+)?`
+	pathComponent   = `[a-z0-9]+(?:(?:[._]|__|[-]+)[a-z0-9]+)*`
+	name            = `(?:` + domain + `/)?` + pathComponent + `(?:/` + pathComponent + `)*`
+	tag             = `[\w][\w.-]{0,127}`
+	digest          = `[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[[:xdigit:]]{32,}`
+
+	referenceRegexp = regexp.MustCompile(`^(` + name + `)(?::(` + tag + `))?(?:@(` + digest + `))?$`)
+)
+
+// Reference is a parsed container image reference
+type Reference struct {
+	Name   string
+	Tag    string
+	Digest string
+}
+
+// ParseReference parses image reference (e.g. registry.io/foo/bar:v1@sha256:...)
+// returning an error if it is not well-formed
+func ParseReference(ref string) (Reference, error) {
+	if len(ref) == 0 {
+		return Reference{}, fmt.Errorf("Expected image reference to be non-empty")
+	}
+
+	matches := referenceRegexp.FindStringSubmatch(ref)
+	if matches == nil {
+		return Reference{}, fmt.Errorf("Expected image reference '%s' to be well-formed", ref)
+	}
+
+	if len(matches[1]) > maxNameLength {
+		return Reference{}, fmt.Errorf("Expected image reference '%s' name to be at most %d characters", ref, maxNameLength)
+	}
+
+	return Reference{Name: matches[1], Tag: matches[2], Digest: matches[3]}, nil
+}
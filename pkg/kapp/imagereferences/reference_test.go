@@ -0,0 +1,74 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagereferences_test
+
+import (
+	"strings"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/imagereferences"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	for _, tc := range []struct {
+		name     string
+		ref      string
+		expected imagereferences.Reference
+		err      string
+	}{
+		{
+			name:     "name only",
+			ref:      "nginx",
+			expected: imagereferences.Reference{Name: "nginx"},
+		},
+		{
+			name:     "name with tag",
+			ref:      "nginx:1.25",
+			expected: imagereferences.Reference{Name: "nginx", Tag: "1.25"},
+		},
+		{
+			name:     "registry with port, tag and digest",
+			ref:      "localhost:5000/foo/bar:v1@" + digest,
+			expected: imagereferences.Reference{Name: "localhost:5000/foo/bar", Tag: "v1", Digest: digest},
+		},
+		{
+			name:     "name with digest",
+			ref:      "index.docker.io/library/nginx@" + digest,
+			expected: imagereferences.Reference{Name: "index.docker.io/library/nginx", Digest: digest},
+		},
+		{
+			name: "double colon",
+			ref:  "nginx::latest",
+			err:  "Expected image reference 'nginx::latest' to be well-formed",
+		},
+		{
+			name: "uppercase repository",
+			ref:  "Nginx:latest",
+			err:  "to be well-formed",
+		},
+		{
+			name: "short digest",
+			ref:  "nginx@sha256:abc",
+			err:  "to be well-formed",
+		},
+		{
+			name: "empty",
+			ref:  "",
+			err:  "Expected image reference to be non-empty",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := imagereferences.ParseReference(tc.ref)
+			if len(tc.err) > 0 {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ref)
+		})
+	}
+}
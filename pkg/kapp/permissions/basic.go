@@ -20,7 +20,7 @@ type BasicValidator struct {
 	mapper              meta.RESTMapper
 }
 
-var _ Validator = (*BasicValidator)(nil)
+var _ ResultsValidator = (*BasicValidator)(nil)
 
 func NewBasicValidator(pv PermissionValidator, mapper meta.RESTMapper) *BasicValidator {
 	return &BasicValidator{
@@ -30,12 +30,25 @@ func NewBasicValidator(pv PermissionValidator, mapper meta.RESTMapper) *BasicVal
 }
 
 func (bv *BasicValidator) Validate(ctx context.Context, res ctlres.Resource, verb string) error {
+	_, err := bv.ValidateWithResults(ctx, res, verb)
+	return err
+}
+
+// ValidateWithResults performs the same validation as Validate,
+// additionally returning outcomes of each performed permission check
+func (bv *BasicValidator) ValidateWithResults(ctx context.Context, res ctlres.Resource, verb string) ([]ValidationResult, error) {
+	pv := newRecordingPermissionValidator(bv.permissionValidator)
+	err := bv.validate(ctx, pv, res, verb)
+	return pv.results, err
+}
+
+func (bv *BasicValidator) validate(ctx context.Context, pv PermissionValidator, res ctlres.Resource, verb string) error {
 	mapping, err := bv.mapper.RESTMapping(res.GroupKind(), res.GroupVersion().Version)
 	if err != nil {
 		return err
 	}
 
-	return bv.permissionValidator.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+	return pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
 		Group:     mapping.Resource.Group,
 		Version:   mapping.Resource.Version,
 		Resource:  mapping.Resource.Resource,
@@ -25,7 +25,7 @@ type BindingValidator struct {
 	mapper              meta.RESTMapper
 }
 
-var _ Validator = (*BindingValidator)(nil)
+var _ ResultsValidator = (*BindingValidator)(nil)
 
 func NewBindingValidator(pv PermissionValidator, rbacClient rbacv1client.RbacV1Interface, mapper meta.RESTMapper) *BindingValidator {
 	return &BindingValidator{
@@ -36,6 +36,19 @@ func NewBindingValidator(pv PermissionValidator, rbacClient rbacv1client.RbacV1I
 }
 
 func (bv *BindingValidator) Validate(ctx context.Context, res ctlres.Resource, verb string) error {
+	_, err := bv.ValidateWithResults(ctx, res, verb)
+	return err
+}
+
+// ValidateWithResults performs the same validation as Validate,
+// additionally returning outcomes of each performed permission check
+func (bv *BindingValidator) ValidateWithResults(ctx context.Context, res ctlres.Resource, verb string) ([]ValidationResult, error) {
+	pv := newRecordingPermissionValidator(bv.permissionValidator)
+	err := bv.validate(ctx, pv, res, verb)
+	return pv.results, err
+}
+
+func (bv *BindingValidator) validate(ctx context.Context, pv PermissionValidator, res ctlres.Resource, verb string) error {
 	mapping, err := bv.mapper.RESTMapping(res.GroupKind(), res.GroupVersion().Version)
 	if err != nil {
 		return err
@@ -46,7 +59,7 @@ func (bv *BindingValidator) Validate(ctx context.Context, res ctlres.Resource, v
 		// do early validation on create / update to see if a user has
 		// the "bind" permissions which allows them to perform
 		// privilege escalation and create any (Cluster)Role
-		err := bv.permissionValidator.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+		err := pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
 			Group:     mapping.Resource.Group,
 			Version:   mapping.Resource.Version,
 			Resource:  mapping.Resource.Resource,
@@ -62,7 +75,7 @@ func (bv *BindingValidator) Validate(ctx context.Context, res ctlres.Resource, v
 		}
 
 		// Check if user has permissions to even create/update the resource
-		err = bv.permissionValidator.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+		err = pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
 			Group:     mapping.Resource.Group,
 			Version:   mapping.Resource.Version,
 			Resource:  mapping.Resource.Resource,
@@ -97,7 +110,7 @@ func (bv *BindingValidator) Validate(ctx context.Context, res ctlres.Resource, v
 				if len(subrule.ResourceNames) > 0 {
 					resourceName = subrule.ResourceNames[0]
 				}
-				err := bv.permissionValidator.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+				err := pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
 					Group:     subrule.APIGroups[0],
 					Resource:  subrule.Resources[0],
 					Namespace: res.Namespace(),
@@ -115,7 +128,7 @@ func (bv *BindingValidator) Validate(ctx context.Context, res ctlres.Resource, v
 			return errors.Join(append([]error{baseErr}, errorSet...)...)
 		}
 	default:
-		return bv.permissionValidator.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+		return pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
 			Group:     mapping.Resource.Group,
 			Version:   mapping.Resource.Version,
 			Resource:  mapping.Resource.Resource,
@@ -15,9 +15,10 @@ var _ Validator = (*CompositeValidator)(nil)
 // CompositeValidator implements Validator and is used
 // for composing multiple validators into a single validator
 // that can handle specifying unique validators for different
-// GroupVersionKinds
+// GroupVersionKinds (or entire API groups)
 type CompositeValidator struct {
 	validators       map[schema.GroupVersionKind]Validator
+	groupValidators  map[string]Validator
 	defaultValidator Validator
 }
 
@@ -28,9 +29,35 @@ func NewCompositeValidator(defaultValidator Validator, validators map[schema.Gro
 	}
 }
 
+// Register sets validator to be used for resources of
+// given GroupVersionKind, replacing any previously
+// registered validator for it
+func (cv *CompositeValidator) Register(gvk schema.GroupVersionKind, validator Validator) {
+	if cv.validators == nil {
+		cv.validators = map[schema.GroupVersionKind]Validator{}
+	}
+	cv.validators[gvk] = validator
+}
+
+// RegisterGroup sets validator to be used for resources of
+// all versions and kinds within given API group that do not have
+// a validator registered for their exact GroupVersionKind
+func (cv *CompositeValidator) RegisterGroup(group string, validator Validator) {
+	if cv.groupValidators == nil {
+		cv.groupValidators = map[string]Validator{}
+	}
+	cv.groupValidators[group] = validator
+}
+
+// Validate uses validator registered for resource's exact
+// GroupVersionKind, then validator registered for its API group,
+// falling back to the default validator
 func (cv *CompositeValidator) Validate(ctx context.Context, res ctlres.Resource, verb string) error {
 	if validator, ok := cv.validators[res.GroupVersion().WithKind(res.Kind())]; ok {
 		return validator.Validate(ctx, res, verb)
 	}
+	if validator, ok := cv.groupValidators[res.APIGroup()]; ok {
+		return validator.Validate(ctx, res, verb)
+	}
 	return cv.defaultValidator.Validate(ctx, res, verb)
 }
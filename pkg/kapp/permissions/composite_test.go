@@ -0,0 +1,65 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCompositeValidatorRegister(t *testing.T) {
+	pdb := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "policy/v1", "kind": "PodDisruptionBudget", "metadata": {"name": "pdb", "namespace": "default"}}`))
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+
+	defaultValidator := &recordingValidator{}
+	pdbValidator := &recordingValidator{}
+
+	validator := permissions.NewCompositeValidator(defaultValidator, nil)
+	validator.Register(schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}, pdbValidator)
+
+	require.NoError(t, validator.Validate(context.Background(), pdb, "create"))
+	require.NoError(t, validator.Validate(context.Background(), cm, "create"))
+
+	require.Equal(t, []string{"poddisruptionbudget/pdb (policy/v1) namespace: default"}, pdbValidator.validated)
+	require.Equal(t, []string{"configmap/cm (v1) namespace: default"}, defaultValidator.validated)
+}
+
+func TestCompositeValidatorRegisterGroup(t *testing.T) {
+	role := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "Role", "metadata": {"name": "role", "namespace": "default"}}`))
+	binding := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "RoleBinding", "metadata": {"name": "binding", "namespace": "default"}}`))
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+
+	defaultValidator := &recordingValidator{}
+	roleValidator := &recordingValidator{}
+	rbacValidator := &recordingValidator{}
+
+	validator := permissions.NewCompositeValidator(defaultValidator, map[schema.GroupVersionKind]permissions.Validator{
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"}: roleValidator,
+	})
+	validator.RegisterGroup("rbac.authorization.k8s.io", rbacValidator)
+
+	require.NoError(t, validator.Validate(context.Background(), role, "create"))
+	require.NoError(t, validator.Validate(context.Background(), binding, "create"))
+	require.NoError(t, validator.Validate(context.Background(), cm, "create"))
+
+	// exact GroupVersionKind takes precedence over API group
+	require.Equal(t, []string{"role/role (rbac.authorization.k8s.io/v1) namespace: default"}, roleValidator.validated)
+	require.Equal(t, []string{"rolebinding/binding (rbac.authorization.k8s.io/v1) namespace: default"}, rbacValidator.validated)
+	require.Equal(t, []string{"configmap/cm (v1) namespace: default"}, defaultValidator.validated)
+}
+
+// recordingValidator allows everything and records resources it was asked about
+type recordingValidator struct {
+	validated []string
+}
+
+func (v *recordingValidator) Validate(_ context.Context, res ctlres.Resource, _ string) error {
+	v.validated = append(v.validated, res.Description())
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"context"
+	"fmt"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// DryRunApplier performs a server side dry run
+// of creating or updating a resource
+type DryRunApplier interface {
+	DryRunApply(context.Context, ctlres.Resource) error
+}
+
+// DynamicDryRunApplier is a DryRunApplier implementation
+// that uses a dynamic client to issue dry run
+// create or update requests
+type DynamicDryRunApplier struct {
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+}
+
+var _ DryRunApplier = (*DynamicDryRunApplier)(nil)
+
+func NewDynamicDryRunApplier(dynamicClient dynamic.Interface, mapper meta.RESTMapper) *DynamicDryRunApplier {
+	return &DynamicDryRunApplier{
+		dynamicClient: dynamicClient,
+		mapper:        mapper,
+	}
+}
+
+// DryRunApply will issue a dry run create request if the resource
+// does not exist on the cluster, otherwise it will issue a dry run update request.
+func (a *DynamicDryRunApplier) DryRunApply(ctx context.Context, res ctlres.Resource) error {
+	mapping, err := a.mapper.RESTMapping(res.GroupKind(), res.GroupVersion().Version)
+	if err != nil {
+		return err
+	}
+
+	var resClient dynamic.ResourceInterface = a.dynamicClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resClient = a.dynamicClient.Resource(mapping.Resource).Namespace(res.Namespace())
+	}
+
+	obj := &unstructured.Unstructured{Object: res.DeepCopyRaw()}
+
+	existing, err := resClient.Get(ctx, res.Name(), metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		_, err = resClient.Create(ctx, obj, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+		return err
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+
+	_, err = resClient.Update(ctx, obj, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+	return err
+}
+
+// DryRunValidator validates permissions by performing a server side
+// dry run apply of a resource. This catches authorization decisions
+// made outside of RBAC (e.g. by validating admission webhooks) that
+// are not reflected in SelfSubjectAccessReview results.
+type DryRunValidator struct {
+	applier DryRunApplier
+}
+
+func NewDryRunValidator(applier DryRunApplier) *DryRunValidator {
+	return &DryRunValidator{applier: applier}
+}
+
+// Validate returns an error only if the dry run request is
+// forbidden by the API server. Any other errors are ignored as they
+// are not related to permissions and will surface during the actual apply.
+func (v *DryRunValidator) Validate(ctx context.Context, res ctlres.Resource) error {
+	err := v.applier.DryRunApply(ctx, res)
+	if err != nil && apierrors.IsForbidden(err) {
+		return fmt.Errorf("not permitted to apply %s (dry run): %w", res.Description(), err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDryRunValidator(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"ns"}}`))
+	gr := schema.GroupResource{Resource: "configmaps"}
+
+	for _, tc := range []struct {
+		name        string
+		dryRunErr   error
+		shouldError bool
+	}{
+		{
+			name: "dry run succeeds, no error",
+		},
+		{
+			name:        "dry run forbidden by webhook, error",
+			dryRunErr:   apierrors.NewForbidden(gr, "cm", errors.New("admission webhook \"deny.example.com\" denied the request")),
+			shouldError: true,
+		},
+		{
+			name:      "dry run fails for reasons other than permissions, no error",
+			dryRunErr: apierrors.NewBadRequest("invalid"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			applier := &fakeDryRunApplier{err: tc.dryRunErr}
+			err := permissions.NewDryRunValidator(applier).Validate(context.Background(), res)
+			require.Equal(t, tc.shouldError, err != nil, "Unexpected error: %v", err)
+			require.Equal(t, []string{"cm"}, applier.applied)
+			if tc.shouldError {
+				require.Contains(t, err.Error(), "not permitted to apply configmap/cm (v1) namespace: ns (dry run)")
+				require.Contains(t, err.Error(), "deny.example.com")
+			}
+		})
+	}
+}
+
+type fakeDryRunApplier struct {
+	err     error
+	applied []string
+}
+
+func (a *fakeDryRunApplier) DryRunApply(_ context.Context, res ctlres.Resource) error {
+	a.applied = append(a.applied, res.Name())
+	return a.err
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PermissionDiscrepancyError is returned by MultiPermissionValidator
+// when the SelfSubjectAccessReview and SelfSubjectRulesReview
+// validators do not agree on whether an action is permitted
+type PermissionDiscrepancyError struct {
+	ResourceAttributes *authv1.ResourceAttributes
+	AccessReviewErr    error
+	RulesReviewErr     error
+}
+
+func (e *PermissionDiscrepancyError) Error() string {
+	gvr := schema.GroupVersionResource{
+		Group:    e.ResourceAttributes.Group,
+		Version:  e.ResourceAttributes.Version,
+		Resource: e.ResourceAttributes.Resource,
+	}
+
+	allowedBy, deniedBy, denyErr := "SelfSubjectRulesReview", "SelfSubjectAccessReview", e.AccessReviewErr
+	if e.AccessReviewErr == nil {
+		allowedBy, deniedBy, denyErr = deniedBy, allowedBy, e.RulesReviewErr
+	}
+
+	return fmt.Sprintf("permission discrepancy for %q %s: allowed by %s but denied by %s: %s",
+		e.ResourceAttributes.Verb, gvr.String(), allowedBy, deniedBy, denyErr)
+}
+
+// MultiPermissionValidator is a PermissionValidator that validates
+// permissions via both SelfSubjectAccessReview and SelfSubjectRulesReview
+// and requires both of them to agree
+type MultiPermissionValidator struct {
+	accessReviewValidator PermissionValidator
+	rulesReviewValidator  PermissionValidator
+}
+
+var _ PermissionValidator = (*MultiPermissionValidator)(nil)
+
+func NewMultiPermissionValidator(accessReviewValidator, rulesReviewValidator PermissionValidator) *MultiPermissionValidator {
+	return &MultiPermissionValidator{
+		accessReviewValidator: accessReviewValidator,
+		rulesReviewValidator:  rulesReviewValidator,
+	}
+}
+
+// ValidatePermissions will validate permissions using both the SelfSubjectAccessReview
+// and SelfSubjectRulesReview validators. An error is returned if both validators
+// deny the action, or a *PermissionDiscrepancyError if only one of them does.
+func (mv *MultiPermissionValidator) ValidatePermissions(ctx context.Context, resourceAttrib *authv1.ResourceAttributes) error {
+	accessReviewErr := mv.accessReviewValidator.ValidatePermissions(ctx, resourceAttrib)
+	rulesReviewErr := mv.rulesReviewValidator.ValidatePermissions(ctx, resourceAttrib)
+
+	switch {
+	case accessReviewErr == nil && rulesReviewErr == nil:
+		return nil
+	case accessReviewErr != nil && rulesReviewErr != nil:
+		if accessReviewErr.Error() == rulesReviewErr.Error() {
+			return accessReviewErr
+		}
+		return errors.Join(accessReviewErr, rulesReviewErr)
+	default:
+		return &PermissionDiscrepancyError{
+			ResourceAttributes: resourceAttrib,
+			AccessReviewErr:    accessReviewErr,
+			RulesReviewErr:     rulesReviewErr,
+		}
+	}
+}
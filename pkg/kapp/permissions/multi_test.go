@@ -0,0 +1,75 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+)
+
+func TestMultiPermissionValidator(t *testing.T) {
+	attrs := &authv1.ResourceAttributes{Verb: "create", Resource: "configmaps", Namespace: "default"}
+	denied := errors.New("not permitted to \"create\" /, Resource=configmaps")
+
+	for _, tc := range []struct {
+		name              string
+		accessReviewErr   error
+		rulesReviewErr    error
+		shouldError       bool
+		shouldDiscrepancy bool
+	}{
+		{
+			name: "both allow, no error",
+		},
+		{
+			name:            "both deny, error",
+			accessReviewErr: denied,
+			rulesReviewErr:  denied,
+			shouldError:     true,
+		},
+		{
+			name:              "access review allows, rules review denies, discrepancy error",
+			rulesReviewErr:    denied,
+			shouldError:       true,
+			shouldDiscrepancy: true,
+		},
+		{
+			name:              "access review denies, rules review allows, discrepancy error",
+			accessReviewErr:   denied,
+			shouldError:       true,
+			shouldDiscrepancy: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := permissions.NewMultiPermissionValidator(
+				&fakePermissionValidator{err: tc.accessReviewErr},
+				&fakePermissionValidator{err: tc.rulesReviewErr},
+			)
+
+			err := validator.ValidatePermissions(context.Background(), attrs)
+			require.Equal(t, tc.shouldError, err != nil, "Unexpected error: %v", err)
+
+			var discrepancyErr *permissions.PermissionDiscrepancyError
+			require.Equal(t, tc.shouldDiscrepancy, errors.As(err, &discrepancyErr))
+			if tc.shouldDiscrepancy {
+				require.Contains(t, err.Error(), "permission discrepancy for \"create\"")
+			}
+		})
+	}
+}
+
+type fakePermissionValidator struct {
+	err     error
+	checked []authv1.ResourceAttributes
+}
+
+func (v *fakePermissionValidator) ValidatePermissions(_ context.Context, attrs *authv1.ResourceAttributes) error {
+	v.checked = append(v.checked, *attrs)
+	return v.err
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"context"
+
+	authv1 "k8s.io/api/authorization/v1"
+)
+
+// NamespacesPermissionValidator is a PermissionValidator that is able to
+// efficiently validate the same permission across multiple namespaces
+type NamespacesPermissionValidator interface {
+	PermissionValidator
+	// ValidatePermissionsInNamespaces validates given attributes in each of
+	// the namespaces (overriding attributes namespace) and returns errors
+	// keyed by namespaces that denied the permission
+	ValidatePermissionsInNamespaces(context.Context, *authv1.ResourceAttributes, []string) map[string]error
+}
+
+var (
+	_ NamespacesPermissionValidator = (*SelfSubjectAccessReviewValidator)(nil)
+	_ NamespacesPermissionValidator = (*SelfSubjectRulesReviewValidator)(nil)
+)
+
+// ValidatePermissionsInNamespaces validates given attributes in each of the
+// namespaces using pv, taking advantage of NamespacesPermissionValidator
+// if implemented. Errors are keyed by namespaces that denied the permission.
+func ValidatePermissionsInNamespaces(ctx context.Context, pv PermissionValidator, resourceAttrib *authv1.ResourceAttributes, namespaces []string) map[string]error {
+	if nsValidator, ok := pv.(NamespacesPermissionValidator); ok {
+		return nsValidator.ValidatePermissionsInNamespaces(ctx, resourceAttrib, namespaces)
+	}
+	return validatePermissionsInNamespaces(namespaces, resourceAttrib, func(attrib *authv1.ResourceAttributes) error {
+		return pv.ValidatePermissions(ctx, attrib)
+	})
+}
+
+// ValidatePermissionsInNamespaces issues a SelfSubjectAccessReview per namespace
+func (rv *SelfSubjectAccessReviewValidator) ValidatePermissionsInNamespaces(ctx context.Context, resourceAttrib *authv1.ResourceAttributes, namespaces []string) map[string]error {
+	return validatePermissionsInNamespaces(namespaces, resourceAttrib, func(attrib *authv1.ResourceAttributes) error {
+		return rv.ValidatePermissions(ctx, attrib)
+	})
+}
+
+// ValidatePermissionsInNamespaces fetches rules once per namespace
+// (reusing cached rules) and evaluates attributes against them
+func (rv *SelfSubjectRulesReviewValidator) ValidatePermissionsInNamespaces(ctx context.Context, resourceAttrib *authv1.ResourceAttributes, namespaces []string) map[string]error {
+	rv.mu.Lock()
+	defer rv.mu.Unlock()
+
+	return validatePermissionsInNamespaces(namespaces, resourceAttrib, func(attrib *authv1.ResourceAttributes) error {
+		return rv.validatePermissions(ctx, attrib)
+	})
+}
+
+func validatePermissionsInNamespaces(namespaces []string, resourceAttrib *authv1.ResourceAttributes,
+	validateFunc func(*authv1.ResourceAttributes) error) map[string]error {
+
+	denied := map[string]error{}
+	checked := map[string]struct{}{}
+
+	for _, ns := range namespaces {
+		if _, found := checked[ns]; found {
+			continue
+		}
+		checked[ns] = struct{}{}
+
+		attrib := resourceAttrib.DeepCopy()
+		attrib.Namespace = ns
+		err := validateFunc(attrib)
+		if err != nil {
+			denied[ns] = err
+		}
+	}
+	return denied
+}
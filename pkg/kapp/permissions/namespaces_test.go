@@ -0,0 +1,61 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidatePermissionsInNamespaces(t *testing.T) {
+	rules := []authv1.ResourceRule{{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"configmaps"}}}
+	namespaces := []string{"ns1", "ns2", "ns3", "ns1"}
+	attrs := &authv1.ResourceAttributes{Verb: "create", Resource: "configmaps", Namespace: "ignored"}
+
+	ssarClient := &fakeNamespacedSSARClient{allowed: []string{"ns1", "ns3"}}
+	ssrrClient := &fakeSSRRClient{rules: map[string][]authv1.ResourceRule{"ns1": rules, "ns3": rules}}
+
+	ssarValidator := permissions.NewSelfSubjectAccessReviewValidator(ssarClient)
+	ssrrValidator := permissions.NewSelfSubjectRulesReviewValidator(ssrrClient)
+
+	for name, pv := range map[string]permissions.PermissionValidator{
+		"access review": ssarValidator,
+		"rules review":  ssrrValidator,
+		"multi":         permissions.NewMultiPermissionValidator(ssarValidator, ssrrValidator),
+	} {
+		t.Run(name, func(t *testing.T) {
+			denied := permissions.ValidatePermissionsInNamespaces(context.Background(), pv, attrs, namespaces)
+			require.Len(t, denied, 1)
+			require.ErrorContains(t, denied["ns2"], `not permitted to "create" /, Resource=configmaps`)
+		})
+	}
+
+	// Attributes are not modified
+	require.Equal(t, "ignored", attrs.Namespace)
+
+	// Each namespace is checked once per access review validator
+	require.Equal(t, []string{"ns1", "ns2", "ns3", "ns1", "ns2", "ns3"}, ssarClient.namespaces)
+
+	// Rules are fetched once per namespace and reused from cache afterwards
+	require.Equal(t, map[string]int{"ns1": 1, "ns2": 1, "ns3": 1}, ssrrClient.calls)
+}
+
+type fakeNamespacedSSARClient struct {
+	allowed    []string
+	namespaces []string
+}
+
+func (c *fakeNamespacedSSARClient) Create(_ context.Context, ssar *authv1.SelfSubjectAccessReview, _ metav1.CreateOptions) (*authv1.SelfSubjectAccessReview, error) {
+	c.namespaces = append(c.namespaces, ssar.Spec.ResourceAttributes.Namespace)
+
+	result := ssar.DeepCopy()
+	result.Status.Allowed = slices.Contains(c.allowed, ssar.Spec.ResourceAttributes.Namespace)
+	return result, nil
+}
@@ -8,12 +8,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
 	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
 	"carvel.dev/kapp/pkg/kapp/preflight"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/spf13/pflag"
+	authv1 "k8s.io/api/authorization/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
 )
 
 // Preflight is an implementation of preflight.Check
@@ -23,15 +31,63 @@ type Preflight struct {
 	depsFactory cmdcore.DepsFactory
 	enabled     bool
 	config      *PreflightConfig
+
+	customValidators map[schema.GroupVersionKind]Validator
+
+	// suggestRBAC includes RBAC manifest granting
+	// denied permissions in the returned error
+	suggestRBAC bool
 }
 
+var _ preflight.CheckWithFlags = (*Preflight)(nil)
+
 const (
 	PermissionValidatorTypeSelfSubjectAccessReview = "SelfSubjectAccessReview"
 	PermissionValidatorTypeSelfSubjectRulesReview  = "SelfSubjectRulesReview"
+	// PermissionValidatorTypeMulti validates using both SelfSubjectAccessReview
+	// and SelfSubjectRulesReview, failing if they disagree
+	PermissionValidatorTypeMulti = "Multi"
 )
 
 type PreflightConfig struct {
 	PermissionValidatorResource string `json:"permissionValidatorResource"`
+	// DryRunApply additionally performs a server side dry run
+	// of each upserted resource to catch permission failures
+	// not reflected by the PermissionValidatorResource
+	DryRunApply bool `json:"dryRunApply"`
+	// RulesCacheTTL is the duration (e.g. 5m) rules fetched via
+	// SelfSubjectRulesReview are cached for before being re-fetched
+	RulesCacheTTL string `json:"rulesCacheTTL"`
+	// Verbs restricts which verbs (create, update, delete, deletecollection)
+	// are validated. All verbs are validated if empty.
+	Verbs []string `json:"verbs"`
+	// DeleteCollectionThreshold is the number of deleted resources
+	// of the same kind within a namespace at which deletecollection
+	// permission is additionally validated. Disabled if 0.
+	DeleteCollectionThreshold int `json:"deleteCollectionThreshold"`
+	// SkipForClusterAdmin performs a single SelfSubjectAccessReview
+	// for all verbs on all resources and skips remaining validation
+	// if it is allowed (i.e. caller is a cluster admin)
+	SkipForClusterAdmin bool `json:"skipForClusterAdmin"`
+	// NamespaceAdminCheck replaces per-resource validation of namespaced
+	// resources with a single check per namespace that caller is allowed
+	// to perform all required verbs on all resources in that namespace,
+	// based on rules fetched via SelfSubjectRulesReview. Cluster scoped
+	// resources are still validated individually
+	NamespaceAdminCheck bool `json:"namespaceAdminCheck"`
+
+	rulesCacheTTL time.Duration
+}
+
+// skipPermissionCheckAnnKey marks resources that should not have
+// their permissions validated (e.g. resources that are expected
+// to be created by a controller after deploy)
+const skipPermissionCheckAnnKey = "kapp.k14s.io/skip-permission-check"
+
+var knownVerbs = []string{"create", "update", "delete", "deletecollection"}
+
+func (c *PreflightConfig) validatesVerb(verb string) bool {
+	return len(c.Verbs) == 0 || slices.Contains(c.Verbs, verb)
 }
 
 func NewPreflight(depsFactory cmdcore.DepsFactory, enabled bool) preflight.Check {
@@ -44,6 +100,20 @@ func NewPreflight(depsFactory cmdcore.DepsFactory, enabled bool) preflight.Check
 	}
 }
 
+// RegisterValidator adds a custom validator used for resources
+// of given GroupVersionKind instead of built-in validators
+func (p *Preflight) RegisterValidator(gvk schema.GroupVersionKind, validator Validator) {
+	if p.customValidators == nil {
+		p.customValidators = map[schema.GroupVersionKind]Validator{}
+	}
+	p.customValidators[gvk] = validator
+}
+
+func (p *Preflight) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&p.suggestRBAC, "preflight-suggest-rbac", false,
+		"Show suggested Role/ClusterRole granting denied permissions when PermissionValidation preflight check fails")
+}
+
 func (p *Preflight) Enabled() bool {
 	return p.enabled
 }
@@ -66,13 +136,32 @@ func (p *Preflight) SetConfig(cfg preflight.CheckConfig) error {
 
 	switch pCfg.PermissionValidatorResource {
 	// Valid, do nothing
-	case PermissionValidatorTypeSelfSubjectAccessReview, PermissionValidatorTypeSelfSubjectRulesReview:
+	case PermissionValidatorTypeSelfSubjectAccessReview, PermissionValidatorTypeSelfSubjectRulesReview, PermissionValidatorTypeMulti:
 	// Default to using SelfSubjectAccessReview
 	case "":
 		pCfg.PermissionValidatorResource = PermissionValidatorTypeSelfSubjectAccessReview
 	default:
 		return fmt.Errorf("unknown permissionValidatorType %q", pCfg.PermissionValidatorResource)
 	}
+
+	if pCfg.RulesCacheTTL != "" {
+		pCfg.rulesCacheTTL, err = time.ParseDuration(pCfg.RulesCacheTTL)
+		if err != nil {
+			return fmt.Errorf("parsing rulesCacheTTL: %w", err)
+		}
+	}
+
+	if pCfg.DeleteCollectionThreshold < 0 {
+		return fmt.Errorf("deleteCollectionThreshold must not be negative")
+	}
+
+	for _, verb := range pCfg.Verbs {
+		if !slices.Contains(knownVerbs, verb) {
+			return fmt.Errorf("unknown verb %q, expected one of %v", verb, knownVerbs)
+		}
+	}
+
+	p.config = pCfg
 	return nil
 }
 
@@ -87,12 +176,51 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 		return err
 	}
 
+	if p.config.SkipForClusterAdmin {
+		clusterAdmin, err := p.isClusterAdmin(ctx, client.AuthorizationV1().SelfSubjectAccessReviews())
+		if err != nil {
+			return err
+		}
+		if clusterAdmin {
+			return nil
+		}
+	}
+
 	var permissionValidator PermissionValidator
+	var rulesReviewValidator *SelfSubjectRulesReviewValidator
+
 	switch p.config.PermissionValidatorResource {
 	case PermissionValidatorTypeSelfSubjectAccessReview:
 		permissionValidator = NewSelfSubjectAccessReviewValidator(client.AuthorizationV1().SelfSubjectAccessReviews())
 	case PermissionValidatorTypeSelfSubjectRulesReview:
-		permissionValidator = NewSelfSubjectRulesReviewValidator(client.AuthorizationV1().SelfSubjectRulesReviews())
+		rulesReviewValidator = NewSelfSubjectRulesReviewValidatorWithOpts(client.AuthorizationV1().SelfSubjectRulesReviews(),
+			SelfSubjectRulesReviewValidatorOpts{CacheTTL: p.config.rulesCacheTTL})
+		permissionValidator = rulesReviewValidator
+	case PermissionValidatorTypeMulti:
+		rulesReviewValidator = NewSelfSubjectRulesReviewValidatorWithOpts(client.AuthorizationV1().SelfSubjectRulesReviews(),
+			SelfSubjectRulesReviewValidatorOpts{CacheTTL: p.config.rulesCacheTTL})
+		permissionValidator = NewMultiPermissionValidator(
+			NewSelfSubjectAccessReviewValidator(client.AuthorizationV1().SelfSubjectAccessReviews()),
+			rulesReviewValidator)
+	}
+
+	if p.config.NamespaceAdminCheck && rulesReviewValidator == nil {
+		rulesReviewValidator = NewSelfSubjectRulesReviewValidatorWithOpts(client.AuthorizationV1().SelfSubjectRulesReviews(),
+			SelfSubjectRulesReviewValidatorOpts{CacheTTL: p.config.rulesCacheTTL})
+	}
+
+	if rulesReviewValidator != nil {
+		// Fetch rules for all namespaces up front instead of one by one during validation
+		err = rulesReviewValidator.Prime(ctx, p.changeNamespaces(changeGraph))
+		if err != nil {
+			return err
+		}
+	}
+
+	var recordingValidator *recordingPermissionValidator
+	if p.suggestRBAC {
+		recordingValidator = newRecordingPermissionValidator(permissionValidator)
+		permissionValidator = recordingValidator
 	}
 
 	roleValidator := NewRoleValidator(permissionValidator, mapper)
@@ -105,23 +233,71 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 		rbacv1.SchemeGroupVersion.WithKind("RoleBinding"):        bindingValidator,
 		rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"): bindingValidator,
 	})
+	for gvk, customValidator := range p.customValidators {
+		validator.Register(gvk, customValidator)
+	}
+
+	var dryRunValidator *DryRunValidator
+	if p.config.DryRunApply {
+		dynamicClient, err := p.depsFactory.DynamicClient(cmdcore.DynamicClientOpts{})
+		if err != nil {
+			return err
+		}
+		dryRunValidator = NewDryRunValidator(NewDynamicDryRunApplier(dynamicClient, mapper))
+	}
 
 	errorSet := []error{}
+
+	if p.config.NamespaceAdminCheck {
+		errorSet = append(errorSet, p.validateNamespaceAdmin(ctx, rulesReviewValidator, changeGraph)...)
+	}
+
+	validate := func(change *ctldgraph.Change, verb string) {
+		if !p.config.validatesVerb(verb) {
+			return
+		}
+		if p.config.NamespaceAdminCheck && change.Change.Resource().Namespace() != "" {
+			return
+		}
+		err := validator.Validate(ctx, change.Change.Resource(), verb)
+		if err != nil {
+			errorSet = append(errorSet, err)
+		}
+	}
+
+	bulkDeletes := newBulkDeletes()
+
 	for _, change := range changeGraph.All() {
+		if change.Change.Resource().Annotations()[skipPermissionCheckAnnKey] == "true" {
+			continue
+		}
+
 		switch change.Change.Op() {
 		case ctldgraph.ActualChangeOpDelete:
-			err = validator.Validate(ctx, change.Change.Resource(), "delete")
-			if err != nil {
-				errorSet = append(errorSet, err)
-			}
+			validate(change, "delete")
+			bulkDeletes.Add(change.Change.Resource())
 		case ctldgraph.ActualChangeOpUpsert:
 			// Check both create and update permissions
-			err = validator.Validate(ctx, change.Change.Resource(), "create")
-			if err != nil {
-				errorSet = append(errorSet, err)
+			validate(change, "create")
+			validate(change, "update")
+
+			// Dry run performs create or update, hence
+			// it is only done when either verb is validated
+			if dryRunValidator != nil && (p.config.validatesVerb("create") || p.config.validatesVerb("update")) {
+				err = dryRunValidator.Validate(ctx, change.Change.Resource())
+				if err != nil {
+					errorSet = append(errorSet, err)
+				}
 			}
+		}
+	}
 
-			err = validator.Validate(ctx, change.Change.Resource(), "update")
+	if p.config.DeleteCollectionThreshold > 0 && p.config.validatesVerb("deletecollection") {
+		for _, res := range bulkDeletes.Over(p.config.DeleteCollectionThreshold) {
+			if p.config.NamespaceAdminCheck && res.Namespace() != "" {
+				continue
+			}
+			err := p.validateDeleteCollection(ctx, permissionValidator, mapper, res)
 			if err != nil {
 				errorSet = append(errorSet, err)
 			}
@@ -129,8 +305,160 @@ func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph)
 	}
 
 	if len(errorSet) > 0 {
+		if recordingValidator != nil {
+			suggestedRBAC, err := SuggestedRBAC(recordingValidator.results)
+			if err != nil {
+				return err
+			}
+			if len(suggestedRBAC) > 0 {
+				errorSet = append(errorSet, fmt.Errorf("Suggested RBAC granting denied permissions:\n%s", suggestedRBAC))
+			}
+		}
 		return errors.Join(errorSet...)
 	}
 
 	return nil
 }
+
+// isClusterAdmin checks whether caller is allowed
+// to perform any verb on any resource in all namespaces
+func (p *Preflight) isClusterAdmin(ctx context.Context, ssarClient authv1client.SelfSubjectAccessReviewInterface) (bool, error) {
+	ssar, err := ssarClient.Create(ctx, &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{Group: "*", Resource: "*", Verb: "*"},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("checking for cluster admin permissions: %w", err)
+	}
+	return ssar.Status.Allowed, nil
+}
+
+// validateNamespaceAdmin checks that caller is allowed to perform verbs
+// required by changes on all resources within each changed namespace.
+// A single error is returned for each namespace that does not allow it.
+func (p *Preflight) validateNamespaceAdmin(ctx context.Context, rv *SelfSubjectRulesReviewValidator, changeGraph *ctldgraph.ChangeGraph) []error {
+	var namespaces []string
+	nsVerbs := map[string][]string{}
+	bulkDeletes := newBulkDeletes()
+
+	addVerb := func(ns, verb string) {
+		if !p.config.validatesVerb(verb) {
+			return
+		}
+		if _, found := nsVerbs[ns]; !found {
+			namespaces = append(namespaces, ns)
+		}
+		if !slices.Contains(nsVerbs[ns], verb) {
+			nsVerbs[ns] = append(nsVerbs[ns], verb)
+		}
+	}
+
+	for _, change := range changeGraph.All() {
+		res := change.Change.Resource()
+		if res.Namespace() == "" || res.Annotations()[skipPermissionCheckAnnKey] == "true" {
+			continue
+		}
+
+		switch change.Change.Op() {
+		case ctldgraph.ActualChangeOpDelete:
+			addVerb(res.Namespace(), "delete")
+			bulkDeletes.Add(res)
+		case ctldgraph.ActualChangeOpUpsert:
+			addVerb(res.Namespace(), "create")
+			addVerb(res.Namespace(), "update")
+		}
+	}
+
+	if p.config.DeleteCollectionThreshold > 0 {
+		for _, res := range bulkDeletes.Over(p.config.DeleteCollectionThreshold) {
+			addVerb(res.Namespace(), "deletecollection")
+		}
+	}
+
+	deniedVerbs := map[string][]string{}
+
+	for _, verb := range knownVerbs {
+		var verbNamespaces []string
+		for _, ns := range namespaces {
+			if slices.Contains(nsVerbs[ns], verb) {
+				verbNamespaces = append(verbNamespaces, ns)
+			}
+		}
+
+		attrs := &authv1.ResourceAttributes{Group: "*", Resource: "*", Verb: verb}
+		for ns := range rv.ValidatePermissionsInNamespaces(ctx, attrs, verbNamespaces) {
+			deniedVerbs[ns] = append(deniedVerbs[ns], verb)
+		}
+	}
+
+	var errs []error
+	for _, ns := range namespaces {
+		if verbs, found := deniedVerbs[ns]; found {
+			errs = append(errs, fmt.Errorf("not permitted to %q all resources in namespace %q", verbs, ns))
+		}
+	}
+	return errs
+}
+
+// validateDeleteCollection checks deletecollection permission
+// for the kind of given resource within its namespace
+func (p *Preflight) validateDeleteCollection(ctx context.Context, pv PermissionValidator, mapper meta.RESTMapper, res ctlres.Resource) error {
+	mapping, err := mapper.RESTMapping(res.GroupKind(), res.GroupVersion().Version)
+	if err != nil {
+		return err
+	}
+
+	return pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+		Group:     mapping.Resource.Group,
+		Version:   mapping.Resource.Version,
+		Resource:  mapping.Resource.Resource,
+		Namespace: res.Namespace(),
+		Verb:      "deletecollection",
+	})
+}
+
+func (p *Preflight) changeNamespaces(changeGraph *ctldgraph.ChangeGraph) []string {
+	var namespaces []string
+	for _, change := range changeGraph.All() {
+		namespaces = append(namespaces, change.Change.Resource().Namespace())
+	}
+	return namespaces
+}
+
+type bulkDeleteKey struct {
+	groupKind schema.GroupKind
+	namespace string
+}
+
+// bulkDeletes counts deleted resources per kind and namespace
+type bulkDeletes struct {
+	keys   []bulkDeleteKey
+	counts map[bulkDeleteKey]int
+	first  map[bulkDeleteKey]ctlres.Resource
+}
+
+func newBulkDeletes() *bulkDeletes {
+	return &bulkDeletes{counts: map[bulkDeleteKey]int{}, first: map[bulkDeleteKey]ctlres.Resource{}}
+}
+
+func (b *bulkDeletes) Add(res ctlres.Resource) {
+	key := bulkDeleteKey{res.GroupKind(), res.Namespace()}
+	if _, found := b.first[key]; !found {
+		b.keys = append(b.keys, key)
+		b.first[key] = res
+	}
+	b.counts[key]++
+}
+
+// Over returns a single resource for each kind and namespace
+// with at least threshold deleted resources
+func (b *bulkDeletes) Over(threshold int) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, key := range b.keys {
+		if b.counts[key] >= threshold {
+			result = append(result, b.first[key])
+		}
+	}
+	return result
+}
@@ -0,0 +1,514 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	"carvel.dev/kapp/pkg/kapp/preflight"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
+)
+
+func TestPreflightVerbs(t *testing.T) {
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+	secret := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret", "namespace": "default"}}`))
+
+	changeGraph, err := ctldgraph.NewChangeGraph([]ctldgraph.ActualChange{
+		actualChange{cm, ctldgraph.ActualChangeOpUpsert},
+		actualChange{secret, ctldgraph.ActualChangeOpDelete},
+	}, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name            string
+		verbs           []interface{}
+		dryRunApply     bool
+		expectedVerbs   []string
+		expectedDryRuns []string
+	}{
+		{
+			name:          "all verbs by default",
+			expectedVerbs: []string{"create", "update", "delete"},
+		},
+		{
+			name:          "only delete",
+			verbs:         []interface{}{"delete"},
+			expectedVerbs: []string{"delete"},
+		},
+		{
+			name:          "only create and update",
+			verbs:         []interface{}{"create", "update"},
+			expectedVerbs: []string{"create", "update"},
+		},
+		{
+			name:            "all verbs with dry run apply",
+			dryRunApply:     true,
+			expectedVerbs:   []string{"create", "update", "delete"},
+			expectedDryRuns: []string{"create default/configmaps/cm"},
+		},
+		{
+			name:          "only delete with dry run apply",
+			verbs:         []interface{}{"delete"},
+			dryRunApply:   true,
+			expectedVerbs: []string{"delete"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ssarClient := &fakeSSARClient{}
+			depsFactory := newFakeDepsFactory(ssarClient)
+			check := permissions.NewPreflight(depsFactory, true)
+
+			cfg := preflight.CheckConfig{}
+			if tc.verbs != nil {
+				cfg["verbs"] = tc.verbs
+			}
+			if tc.dryRunApply {
+				cfg["dryRunApply"] = true
+			}
+			require.NoError(t, check.SetConfig(cfg))
+
+			require.NoError(t, check.Run(context.Background(), changeGraph))
+			require.ElementsMatch(t, tc.expectedVerbs, ssarClient.verbs)
+			require.Equal(t, tc.expectedDryRuns, depsFactory.dynamicClient.dryRuns)
+		})
+	}
+}
+
+func TestPreflightUnknownVerb(t *testing.T) {
+	check := permissions.NewPreflight(newFakeDepsFactory(&fakeSSARClient{}), true)
+	err := check.SetConfig(preflight.CheckConfig{"verbs": []interface{}{"patch"}})
+	require.ErrorContains(t, err, `unknown verb "patch"`)
+}
+
+func TestPreflightSkipPermissionCheckAnnotation(t *testing.T) {
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+	secret := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret", "namespace": "default", "annotations": {"kapp.k14s.io/skip-permission-check": "true"}}}`))
+
+	changeGraph, err := ctldgraph.NewChangeGraph([]ctldgraph.ActualChange{
+		actualChange{cm, ctldgraph.ActualChangeOpUpsert},
+		actualChange{secret, ctldgraph.ActualChangeOpDelete},
+	}, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	ssarClient := &fakeSSARClient{}
+	check := permissions.NewPreflight(newFakeDepsFactory(ssarClient), true)
+	require.NoError(t, check.SetConfig(preflight.CheckConfig{}))
+
+	require.NoError(t, check.Run(context.Background(), changeGraph))
+	require.ElementsMatch(t, []string{"create", "update"}, ssarClient.verbs)
+}
+
+func TestPreflightDeleteCollection(t *testing.T) {
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+	secret1 := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret1", "namespace": "default"}}`))
+	secret2 := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret2", "namespace": "default"}}`))
+	secret3 := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret3", "namespace": "other"}}`))
+
+	changeGraph, err := ctldgraph.NewChangeGraph([]ctldgraph.ActualChange{
+		actualChange{cm, ctldgraph.ActualChangeOpDelete},
+		actualChange{secret1, ctldgraph.ActualChangeOpDelete},
+		actualChange{secret2, ctldgraph.ActualChangeOpDelete},
+		actualChange{secret3, ctldgraph.ActualChangeOpDelete},
+	}, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name          string
+		threshold     int
+		expectedVerbs []string
+	}{
+		{
+			name:          "disabled by default",
+			expectedVerbs: []string{"delete", "delete", "delete", "delete"},
+		},
+		{
+			name:          "checked once for kind in namespace reaching threshold",
+			threshold:     2,
+			expectedVerbs: []string{"delete", "delete", "delete", "delete", "deletecollection"},
+		},
+		{
+			name:          "checked for every kind in namespace reaching threshold",
+			threshold:     1,
+			expectedVerbs: []string{"delete", "delete", "delete", "delete", "deletecollection", "deletecollection", "deletecollection"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ssarClient := &fakeSSARClient{}
+			check := permissions.NewPreflight(newFakeDepsFactory(ssarClient), true)
+			require.NoError(t, check.SetConfig(preflight.CheckConfig{"deleteCollectionThreshold": tc.threshold}))
+
+			require.NoError(t, check.Run(context.Background(), changeGraph))
+			require.ElementsMatch(t, tc.expectedVerbs, ssarClient.verbs)
+		})
+	}
+}
+
+func TestPreflightDryRunApply(t *testing.T) {
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+	secret := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret", "namespace": "default"}}`))
+
+	changeGraph, err := ctldgraph.NewChangeGraph([]ctldgraph.ActualChange{
+		actualChange{cm, ctldgraph.ActualChangeOpUpsert},
+		actualChange{secret, ctldgraph.ActualChangeOpDelete},
+	}, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		dryRunApply bool
+	}{
+		{name: "dry run disabled by default"},
+		{name: "dry run enabled via config", dryRunApply: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			depsFactory := newFakeDepsFactory(&fakeSSARClient{denied: []string{"delete"}})
+			depsFactory.dynamicClient.forbidden = true
+
+			check := permissions.NewPreflight(depsFactory, true)
+			require.NoError(t, check.SetConfig(preflight.CheckConfig{"dryRunApply": tc.dryRunApply}))
+
+			err := check.Run(context.Background(), changeGraph)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), `not permitted to "delete" /v1, Resource=secrets`)
+
+			if tc.dryRunApply {
+				require.Equal(t, []string{"create default/configmaps/cm"}, depsFactory.dynamicClient.dryRuns)
+				require.Contains(t, err.Error(), "not permitted to apply configmap/cm (v1) namespace: default (dry run)")
+			} else {
+				require.Empty(t, depsFactory.dynamicClient.dryRuns)
+				require.NotContains(t, err.Error(), "dry run")
+			}
+		})
+	}
+}
+
+func TestPreflightSuggestRBAC(t *testing.T) {
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+	secret := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret", "namespace": "default"}}`))
+
+	changeGraph, err := ctldgraph.NewChangeGraph([]ctldgraph.ActualChange{
+		actualChange{cm, ctldgraph.ActualChangeOpUpsert},
+		actualChange{secret, ctldgraph.ActualChangeOpDelete},
+	}, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name            string
+		args            []string
+		expectedSuggest bool
+	}{
+		{name: "not suggested by default"},
+		{name: "suggested when flag is set", args: []string{"--preflight-suggest-rbac"}, expectedSuggest: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ssarClient := &fakeSSARClient{denied: []string{"update", "delete"}}
+			check := permissions.NewPreflight(newFakeDepsFactory(ssarClient), true)
+			require.NoError(t, check.SetConfig(preflight.CheckConfig{}))
+
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			check.(preflight.CheckWithFlags).AddFlags(flags)
+			require.NoError(t, flags.Parse(tc.args))
+
+			err := check.Run(context.Background(), changeGraph)
+			require.Error(t, err)
+
+			expectedRBAC := `Suggested RBAC granting denied permissions:
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: kapp-suggested-permissions
+  namespace: default
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - configmaps
+  verbs:
+  - update
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - delete
+`
+			if tc.expectedSuggest {
+				require.Contains(t, err.Error(), expectedRBAC)
+			} else {
+				require.NotContains(t, err.Error(), "Suggested RBAC")
+			}
+		})
+	}
+}
+
+func TestPreflightChangedOnly(t *testing.T) {
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+	secret := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret", "namespace": "unchanged"}}`))
+
+	changeGraph, err := ctldgraph.NewChangeGraph([]ctldgraph.ActualChange{
+		actualChange{cm, ctldgraph.ActualChangeOpUpsert},
+		actualChange{secret, ctldgraph.ActualChangeOpNoop},
+	}, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		expectedCalls map[string]int
+	}{
+		{
+			name:          "all changes by default",
+			expectedCalls: map[string]int{"default": 1, "unchanged": 1},
+		},
+		{
+			name:          "unchanged resources skipped when flag is set",
+			args:          []string{"--preflight-changed-only"},
+			expectedCalls: map[string]int{"default": 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ssrrClient := &fakeSSRRClient{
+				rules: map[string][]authv1.ResourceRule{
+					"default": {{Verbs: []string{"create", "update"}, APIGroups: []string{""}, Resources: []string{"configmaps"}}},
+				},
+			}
+			depsFactory := newFakeDepsFactory(&fakeSSARClient{})
+			depsFactory.coreClient.(*fakeCoreClient).authClient.(*fakeAuthClient).ssrrClient = ssrrClient
+
+			registry := preflight.NewRegistry(map[string]preflight.Check{
+				"PermissionValidation": permissions.NewPreflight(depsFactory, true),
+			})
+
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			registry.AddFlags(flags)
+			require.NoError(t, flags.Parse(tc.args))
+
+			require.NoError(t, registry.SetConfig([]ctlconf.PreflightRule{{
+				Name:   "PermissionValidation",
+				Config: map[string]any{"permissionValidatorResource": permissions.PermissionValidatorTypeSelfSubjectRulesReview},
+			}}))
+
+			require.NoError(t, registry.Run(context.Background(), changeGraph))
+			require.Equal(t, tc.expectedCalls, ssrrClient.calls)
+		})
+	}
+}
+
+func TestPreflightSkipForClusterAdmin(t *testing.T) {
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+	secret := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret", "namespace": "default"}}`))
+
+	changeGraph, err := ctldgraph.NewChangeGraph([]ctldgraph.ActualChange{
+		actualChange{cm, ctldgraph.ActualChangeOpUpsert},
+		actualChange{secret, ctldgraph.ActualChangeOpDelete},
+	}, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name          string
+		skip          bool
+		denied        []string
+		expectedVerbs []string
+	}{
+		{
+			name:          "disabled by default",
+			expectedVerbs: []string{"create", "update", "delete"},
+		},
+		{
+			name:          "cluster admin skips per resource checks",
+			skip:          true,
+			expectedVerbs: []string{"*"},
+		},
+		{
+			name:          "non cluster admin continues with per resource checks",
+			skip:          true,
+			denied:        []string{"*"},
+			expectedVerbs: []string{"*", "create", "update", "delete"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ssarClient := &fakeSSARClient{denied: tc.denied}
+			check := permissions.NewPreflight(newFakeDepsFactory(ssarClient), true)
+			require.NoError(t, check.SetConfig(preflight.CheckConfig{"skipForClusterAdmin": tc.skip}))
+
+			require.NoError(t, check.Run(context.Background(), changeGraph))
+			require.ElementsMatch(t, tc.expectedVerbs, ssarClient.verbs)
+		})
+	}
+}
+
+func TestPreflightNamespaceAdminCheck(t *testing.T) {
+	ns := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "ns3"}}`))
+	cm1 := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "ns1"}}`))
+	cm2 := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "ns2"}}`))
+	secret2 := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret", "namespace": "ns2"}}`))
+
+	changeGraph, err := ctldgraph.NewChangeGraph([]ctldgraph.ActualChange{
+		actualChange{ns, ctldgraph.ActualChangeOpUpsert},
+		actualChange{cm1, ctldgraph.ActualChangeOpUpsert},
+		actualChange{cm2, ctldgraph.ActualChangeOpUpsert},
+		actualChange{secret2, ctldgraph.ActualChangeOpDelete},
+	}, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	ssrrClient := &fakeSSRRClient{
+		rules: map[string][]authv1.ResourceRule{
+			"ns1": {{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+			// Allowed to change each resource individually, but not all resources
+			"ns2": {
+				{Verbs: []string{"create", "update"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+				{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"secrets"}},
+			},
+		},
+	}
+	ssarClient := &fakeSSARClient{}
+	depsFactory := newFakeDepsFactory(ssarClient)
+	depsFactory.coreClient.(*fakeCoreClient).authClient.(*fakeAuthClient).ssrrClient = ssrrClient
+
+	check := permissions.NewPreflight(depsFactory, true)
+	require.NoError(t, check.SetConfig(preflight.CheckConfig{"namespaceAdminCheck": true}))
+
+	err = check.Run(context.Background(), changeGraph)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `not permitted to ["delete"] all resources in namespace "ns2"`)
+	require.NotContains(t, err.Error(), `"ns1"`)
+
+	// Rules are fetched once per namespace and only
+	// cluster scoped resources are checked individually
+	require.Equal(t, 1, ssrrClient.calls["ns1"])
+	require.Equal(t, 1, ssrrClient.calls["ns2"])
+	require.Equal(t, []string{"create", "update"}, ssarClient.verbs)
+
+	// Namespace level verdict reflects updated cached rules
+	ssrrClient.rules["ns2"] = ssrrClient.rules["ns1"]
+	check = permissions.NewPreflight(depsFactory, true)
+	require.NoError(t, check.SetConfig(preflight.CheckConfig{"namespaceAdminCheck": true}))
+	require.NoError(t, check.Run(context.Background(), changeGraph))
+}
+
+type actualChange struct {
+	res ctlres.Resource
+	op  ctldgraph.ActualChangeOp
+}
+
+func (a actualChange) Resource() ctlres.Resource    { return a.res }
+func (a actualChange) Op() ctldgraph.ActualChangeOp { return a.op }
+
+type fakeDepsFactory struct {
+	cmdcore.DepsFactory
+	coreClient    kubernetes.Interface
+	dynamicClient *fakeDynamicClient
+	mapper        meta.RESTMapper
+}
+
+func newFakeDepsFactory(ssarClient authv1client.SelfSubjectAccessReviewInterface) *fakeDepsFactory {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	return &fakeDepsFactory{
+		coreClient:    &fakeCoreClient{authClient: &fakeAuthClient{ssarClient: ssarClient}},
+		dynamicClient: &fakeDynamicClient{},
+		mapper:        mapper,
+	}
+}
+
+func (f *fakeDepsFactory) CoreClient() (kubernetes.Interface, error) { return f.coreClient, nil }
+func (f *fakeDepsFactory) RESTMapper() (meta.RESTMapper, error)      { return f.mapper, nil }
+func (f *fakeDepsFactory) DynamicClient(cmdcore.DynamicClientOpts) (dynamic.Interface, error) {
+	return f.dynamicClient, nil
+}
+
+type fakeCoreClient struct {
+	kubernetes.Interface
+	authClient authv1client.AuthorizationV1Interface
+}
+
+func (c *fakeCoreClient) AuthorizationV1() authv1client.AuthorizationV1Interface { return c.authClient }
+func (c *fakeCoreClient) RbacV1() rbacv1client.RbacV1Interface                   { return nil }
+
+type fakeAuthClient struct {
+	authv1client.AuthorizationV1Interface
+	ssarClient authv1client.SelfSubjectAccessReviewInterface
+	ssrrClient authv1client.SelfSubjectRulesReviewInterface
+}
+
+func (c *fakeAuthClient) SelfSubjectAccessReviews() authv1client.SelfSubjectAccessReviewInterface {
+	return c.ssarClient
+}
+
+func (c *fakeAuthClient) SelfSubjectRulesReviews() authv1client.SelfSubjectRulesReviewInterface {
+	return c.ssrrClient
+}
+
+// fakeSSARClient allows everything except denied verbs and records verbs it was asked about
+type fakeSSARClient struct {
+	verbs  []string
+	denied []string
+}
+
+func (c *fakeSSARClient) Create(_ context.Context, ssar *authv1.SelfSubjectAccessReview, _ metav1.CreateOptions) (*authv1.SelfSubjectAccessReview, error) {
+	c.verbs = append(c.verbs, ssar.Spec.ResourceAttributes.Verb)
+
+	result := ssar.DeepCopy()
+	result.Status.Allowed = !slices.Contains(c.denied, ssar.Spec.ResourceAttributes.Verb)
+	return result, nil
+}
+
+// fakeDynamicClient finds no existing resources and records dry run
+// creates and updates as "<verb> <namespace>/<resource>/<name>"
+type fakeDynamicClient struct {
+	dynamic.Interface
+	dryRuns   []string
+	forbidden bool
+}
+
+func (c *fakeDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return fakeResourceClient{client: c, gvr: gvr}
+}
+
+type fakeResourceClient struct {
+	dynamic.NamespaceableResourceInterface
+	client *fakeDynamicClient
+	gvr    schema.GroupVersionResource
+	ns     string
+}
+
+func (c fakeResourceClient) Namespace(ns string) dynamic.ResourceInterface {
+	return fakeResourceClient{client: c.client, gvr: c.gvr, ns: ns}
+}
+
+func (c fakeResourceClient) Get(_ context.Context, name string, _ metav1.GetOptions, _ ...string) (*unstructured.Unstructured, error) {
+	return nil, apierrors.NewNotFound(c.gvr.GroupResource(), name)
+}
+
+func (c fakeResourceClient) Create(_ context.Context, obj *unstructured.Unstructured, _ metav1.CreateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	c.client.dryRuns = append(c.client.dryRuns, "create "+c.ns+"/"+c.gvr.Resource+"/"+obj.GetName())
+	if c.client.forbidden {
+		return nil, apierrors.NewForbidden(c.gvr.GroupResource(), obj.GetName(), errors.New("denied by admission webhook"))
+	}
+	return obj, nil
+}
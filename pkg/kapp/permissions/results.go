@@ -0,0 +1,52 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"context"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	authv1 "k8s.io/api/authorization/v1"
+)
+
+// ValidationResult is the outcome of a single
+// permission check performed by a Validator
+type ValidationResult struct {
+	Attributes authv1.ResourceAttributes
+	Allowed    bool
+	// Message explains why permission check did not succeed
+	Message string
+}
+
+// ResultsValidator is a Validator that is also able to
+// report outcomes of every permission check it performed
+type ResultsValidator interface {
+	Validator
+	ValidateWithResults(context.Context, ctlres.Resource, string) ([]ValidationResult, error)
+}
+
+// recordingPermissionValidator is a PermissionValidator that
+// records outcomes of all permission checks it delegates
+type recordingPermissionValidator struct {
+	permissionValidator PermissionValidator
+	results             []ValidationResult
+}
+
+var _ PermissionValidator = (*recordingPermissionValidator)(nil)
+
+func newRecordingPermissionValidator(pv PermissionValidator) *recordingPermissionValidator {
+	return &recordingPermissionValidator{permissionValidator: pv}
+}
+
+func (rv *recordingPermissionValidator) ValidatePermissions(ctx context.Context, resourceAttrib *authv1.ResourceAttributes) error {
+	err := rv.permissionValidator.ValidatePermissions(ctx, resourceAttrib)
+
+	result := ValidationResult{Attributes: *resourceAttrib, Allowed: err == nil}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	rv.results = append(rv.results, result)
+
+	return err
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
+)
+
+func TestBasicValidatorValidateWithResults(t *testing.T) {
+	cm := ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "default"}}`))
+
+	validator := permissions.NewBasicValidator(&verbPermissionValidator{denied: []string{"delete"}}, newResultsTestMapper())
+
+	results, err := validator.ValidateWithResults(context.Background(), cm, "create")
+	require.NoError(t, err)
+	require.Equal(t, []permissions.ValidationResult{{
+		Attributes: authv1.ResourceAttributes{Version: "v1", Resource: "configmaps", Namespace: "default", Name: "cm", Verb: "create"},
+		Allowed:    true,
+	}}, results)
+
+	results, err = validator.ValidateWithResults(context.Background(), cm, "delete")
+	require.Error(t, err)
+	require.Equal(t, []permissions.ValidationResult{{
+		Attributes: authv1.ResourceAttributes{Version: "v1", Resource: "configmaps", Namespace: "default", Name: "cm", Verb: "delete"},
+		Message:    `not permitted to "delete" configmaps`,
+	}}, results)
+}
+
+func TestRoleValidatorValidateWithResults(t *testing.T) {
+	role := ctlres.MustNewResourceFromBytes([]byte(`{
+		"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "Role", "metadata": {"name": "role", "namespace": "default"},
+		"rules": [{"apiGroups": [""], "resources": ["pods"], "verbs": ["get", "delete"]}]
+	}`))
+
+	validator := permissions.NewRoleValidator(&verbPermissionValidator{denied: []string{"escalate", "delete"}}, newResultsTestMapper())
+
+	results, err := validator.ValidateWithResults(context.Background(), role, "create")
+	require.ErrorContains(t, err, "potential privilege escalation")
+	require.Equal(t, []permissions.ValidationResult{
+		{
+			Attributes: authv1.ResourceAttributes{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles", Namespace: "default", Name: "role", Verb: "escalate"},
+			Message:    `not permitted to "escalate" roles`,
+		},
+		{
+			Attributes: authv1.ResourceAttributes{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles", Namespace: "default", Name: "role", Verb: "create"},
+			Allowed:    true,
+		},
+		{
+			Attributes: authv1.ResourceAttributes{Resource: "pods", Namespace: "default", Verb: "get"},
+			Allowed:    true,
+		},
+		{
+			Attributes: authv1.ResourceAttributes{Resource: "pods", Namespace: "default", Verb: "delete"},
+			Message:    `not permitted to "delete" pods`,
+		},
+	}, results)
+}
+
+func TestBindingValidatorValidateWithResults(t *testing.T) {
+	binding := ctlres.MustNewResourceFromBytes([]byte(`{
+		"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "RoleBinding", "metadata": {"name": "binding", "namespace": "default"},
+		"roleRef": {"apiGroup": "rbac.authorization.k8s.io", "kind": "ClusterRole", "name": "viewer"},
+		"subjects": [{"kind": "ServiceAccount", "name": "default", "namespace": "default"}]
+	}`))
+
+	rbacClient := &fakeRbacClient{clusterRoles: map[string]*rbacv1.ClusterRole{
+		"viewer": {Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}}}},
+	}}
+
+	validator := permissions.NewBindingValidator(&verbPermissionValidator{denied: []string{"bind"}}, rbacClient, newResultsTestMapper())
+
+	results, err := validator.ValidateWithResults(context.Background(), binding, "update")
+	require.NoError(t, err)
+	require.Equal(t, []permissions.ValidationResult{
+		{
+			Attributes: authv1.ResourceAttributes{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings", Namespace: "default", Name: "binding", Verb: "bind"},
+			Message:    `not permitted to "bind" rolebindings`,
+		},
+		{
+			Attributes: authv1.ResourceAttributes{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings", Namespace: "default", Name: "binding", Verb: "update"},
+			Allowed:    true,
+		},
+		{
+			Attributes: authv1.ResourceAttributes{Resource: "pods", Namespace: "default", Verb: "list"},
+			Allowed:    true,
+		},
+	}, results)
+}
+
+func newResultsTestMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("Role"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("RoleBinding"), meta.RESTScopeNamespace)
+	return mapper
+}
+
+// verbPermissionValidator denies only given verbs
+type verbPermissionValidator struct {
+	denied []string
+}
+
+func (v *verbPermissionValidator) ValidatePermissions(_ context.Context, attrs *authv1.ResourceAttributes) error {
+	for _, verb := range v.denied {
+		if attrs.Verb == verb {
+			return fmt.Errorf("not permitted to %q %s", attrs.Verb, attrs.Resource)
+		}
+	}
+	return nil
+}
+
+type fakeRbacClient struct {
+	rbacv1client.RbacV1Interface
+	clusterRoles map[string]*rbacv1.ClusterRole
+}
+
+func (c *fakeRbacClient) ClusterRoles() rbacv1client.ClusterRoleInterface {
+	return &fakeClusterRoleClient{clusterRoles: c.clusterRoles}
+}
+
+type fakeClusterRoleClient struct {
+	rbacv1client.ClusterRoleInterface
+	clusterRoles map[string]*rbacv1.ClusterRole
+}
+
+func (c *fakeClusterRoleClient) Get(_ context.Context, name string, _ metav1.GetOptions) (*rbacv1.ClusterRole, error) {
+	role, found := c.clusterRoles[name]
+	if !found {
+		return nil, fmt.Errorf("cluster role %q not found", name)
+	}
+	return role, nil
+}
@@ -23,7 +23,7 @@ type RoleValidator struct {
 	mapper              meta.RESTMapper
 }
 
-var _ Validator = (*RoleValidator)(nil)
+var _ ResultsValidator = (*RoleValidator)(nil)
 
 func NewRoleValidator(pv PermissionValidator, mapper meta.RESTMapper) *RoleValidator {
 	return &RoleValidator{
@@ -33,6 +33,19 @@ func NewRoleValidator(pv PermissionValidator, mapper meta.RESTMapper) *RoleValid
 }
 
 func (rv *RoleValidator) Validate(ctx context.Context, res ctlres.Resource, verb string) error {
+	_, err := rv.ValidateWithResults(ctx, res, verb)
+	return err
+}
+
+// ValidateWithResults performs the same validation as Validate,
+// additionally returning outcomes of each performed permission check
+func (rv *RoleValidator) ValidateWithResults(ctx context.Context, res ctlres.Resource, verb string) ([]ValidationResult, error) {
+	pv := newRecordingPermissionValidator(rv.permissionValidator)
+	err := rv.validate(ctx, pv, res, verb)
+	return pv.results, err
+}
+
+func (rv *RoleValidator) validate(ctx context.Context, pv PermissionValidator, res ctlres.Resource, verb string) error {
 	mapping, err := rv.mapper.RESTMapping(res.GroupKind(), res.GroupVersion().Version)
 	if err != nil {
 		return err
@@ -43,7 +56,7 @@ func (rv *RoleValidator) Validate(ctx context.Context, res ctlres.Resource, verb
 		// do early validation on create / update to see if a user has
 		// the "escalate" permissions which allows them to perform
 		// privilege escalation and create any (Cluster)Role
-		err := rv.permissionValidator.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+		err := pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
 			Group:     mapping.Resource.Group,
 			Version:   mapping.Resource.Version,
 			Resource:  mapping.Resource.Resource,
@@ -59,7 +72,7 @@ func (rv *RoleValidator) Validate(ctx context.Context, res ctlres.Resource, verb
 		}
 
 		// Check if user has permissions to even create/update the resource
-		err = rv.permissionValidator.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+		err = pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
 			Group:     mapping.Resource.Group,
 			Version:   mapping.Resource.Version,
 			Resource:  mapping.Resource.Resource,
@@ -91,7 +104,7 @@ func (rv *RoleValidator) Validate(ctx context.Context, res ctlres.Resource, verb
 				if len(subrule.ResourceNames) > 0 {
 					resourceName = subrule.ResourceNames[0]
 				}
-				err := rv.permissionValidator.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+				err := pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
 					Group:     subrule.APIGroups[0],
 					Resource:  subrule.Resources[0],
 					Namespace: res.Namespace(),
@@ -109,7 +122,7 @@ func (rv *RoleValidator) Validate(ctx context.Context, res ctlres.Resource, verb
 			return errors.Join(append([]error{baseErr}, errorSet...)...)
 		}
 	default:
-		return rv.permissionValidator.ValidatePermissions(ctx, &authv1.ResourceAttributes{
+		return pv.ValidatePermissions(ctx, &authv1.ResourceAttributes{
 			Group:     mapping.Resource.Group,
 			Version:   mapping.Resource.Version,
 			Resource:  mapping.Resource.Resource,
@@ -0,0 +1,91 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions
+
+import (
+	"sort"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+const suggestedRBACName = "kapp-suggested-permissions"
+
+// SuggestedRBAC renders a minimal set of Roles (for namespaced
+// permissions) and a ClusterRole (for cluster scoped permissions)
+// granting permissions denied within given results
+func SuggestedRBAC(results []ValidationResult) ([]byte, error) {
+	type ruleKey struct {
+		group    string
+		resource string
+	}
+
+	verbsByNs := map[string]map[ruleKey]sets.Set[string]{}
+
+	for _, result := range results {
+		if result.Allowed {
+			continue
+		}
+		attrs := result.Attributes
+
+		resource := attrs.Resource
+		if len(attrs.Subresource) > 0 {
+			resource += "/" + attrs.Subresource
+		}
+		key := ruleKey{attrs.Group, resource}
+
+		if verbsByNs[attrs.Namespace] == nil {
+			verbsByNs[attrs.Namespace] = map[ruleKey]sets.Set[string]{}
+		}
+		if verbsByNs[attrs.Namespace][key] == nil {
+			verbsByNs[attrs.Namespace][key] = sets.New[string]()
+		}
+		verbsByNs[attrs.Namespace][key].Insert(attrs.Verb)
+	}
+
+	var result []byte
+
+	for _, ns := range sets.List(sets.KeySet(verbsByNs)) {
+		var rules []rbacv1.PolicyRule
+		for key, verbs := range verbsByNs[ns] {
+			rules = append(rules, rbacv1.PolicyRule{
+				APIGroups: []string{key.group},
+				Resources: []string{key.resource},
+				Verbs:     sets.List(verbs),
+			})
+		}
+		sort.Slice(rules, func(i, j int) bool {
+			if rules[i].APIGroups[0] != rules[j].APIGroups[0] {
+				return rules[i].APIGroups[0] < rules[j].APIGroups[0]
+			}
+			return rules[i].Resources[0] < rules[j].Resources[0]
+		})
+
+		kind := "Role"
+		metadata := map[string]interface{}{"name": suggestedRBACName}
+		if len(ns) == 0 {
+			kind = "ClusterRole"
+		} else {
+			metadata["namespace"] = ns
+		}
+
+		// Using map avoids empty fields (e.g. creationTimestamp) of typed objects
+		obj := map[string]interface{}{
+			"apiVersion": rbacv1.SchemeGroupVersion.String(),
+			"kind":       kind,
+			"metadata":   metadata,
+			"rules":      rules,
+		}
+
+		objBs, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, []byte("---\n")...)
+		result = append(result, objBs...)
+	}
+
+	return result, nil
+}
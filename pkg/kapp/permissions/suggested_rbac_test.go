@@ -0,0 +1,53 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+)
+
+func TestSuggestedRBAC(t *testing.T) {
+	results := []permissions.ValidationResult{
+		{Attributes: authv1.ResourceAttributes{Group: "apps", Resource: "deployments", Namespace: "app", Verb: "create"}},
+		{Attributes: authv1.ResourceAttributes{Group: "apps", Resource: "deployments", Namespace: "app", Verb: "update"}},
+		{Attributes: authv1.ResourceAttributes{Resource: "configmaps", Namespace: "app", Verb: "create"}, Allowed: true},
+		{Attributes: authv1.ResourceAttributes{Resource: "namespaces", Verb: "delete"}},
+	}
+
+	suggestedRBAC, err := permissions.SuggestedRBAC(results)
+	require.NoError(t, err)
+
+	expected := `---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: kapp-suggested-permissions
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - namespaces
+  verbs:
+  - delete
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: kapp-suggested-permissions
+  namespace: app
+rules:
+- apiGroups:
+  - apps
+  resources:
+  - deployments
+  verbs:
+  - create
+  - update
+`
+	require.Equal(t, expected, string(suggestedRBAC))
+}
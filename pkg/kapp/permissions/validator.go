@@ -8,8 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"carvel.dev/kapp/pkg/kapp/util"
 	authv1 "k8s.io/api/authorization/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +20,7 @@ import (
 	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	rbacauthorizer "k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac"
+	"k8s.io/utils/clock"
 )
 
 type Validator interface {
@@ -77,17 +80,56 @@ func (rv *SelfSubjectAccessReviewValidator) ValidatePermissions(ctx context.Cont
 	return nil
 }
 
+// DefaultSelfSubjectRulesReviewCacheTTL is the default amount of time
+// rules fetched via SelfSubjectRulesReview are cached for
+const DefaultSelfSubjectRulesReviewCacheTTL = 5 * time.Minute
+
+const selfSubjectRulesReviewPrimeConcurrency = 10
+
+// ClusterScopeRulesNamespace is the namespace used in SelfSubjectRulesReview
+// requests when validating permissions for cluster scoped resources.
+// SelfSubjectRulesReview requires a namespace and returns cluster wide rules
+// together with rules granted by RoleBindings in that namespace. Since this is not
+// a valid namespace name, no RoleBindings can exist in it, hence only cluster wide
+// rules are returned (unlike for "default" namespace, which may have RoleBindings).
+const ClusterScopeRulesNamespace = "kapp.cluster-scope"
+
+type SelfSubjectRulesReviewValidatorOpts struct {
+	// CacheTTL is the amount of time cached rules for a namespace
+	// are considered valid before being re-fetched
+	CacheTTL time.Duration
+	// Clock is used to determine cached rules expiration
+	Clock clock.PassiveClock
+}
+
+type rulesCacheEntry struct {
+	rules     []rbacv1.PolicyRule
+	fetchedAt time.Time
+}
+
 // SelfSubjectRulesReviewValidator is for validating permissions via SelfSubjectRulesReview
 type SelfSubjectRulesReviewValidator struct {
 	ssrrClient authv1client.SelfSubjectRulesReviewInterface
-	cache      map[string][]rbacv1.PolicyRule
+	cache      map[string]rulesCacheEntry
+	opts       SelfSubjectRulesReviewValidatorOpts
 	mu         sync.Mutex
 }
 
 func NewSelfSubjectRulesReviewValidator(ssrrClient authv1client.SelfSubjectRulesReviewInterface) *SelfSubjectRulesReviewValidator {
+	return NewSelfSubjectRulesReviewValidatorWithOpts(ssrrClient, SelfSubjectRulesReviewValidatorOpts{})
+}
+
+func NewSelfSubjectRulesReviewValidatorWithOpts(ssrrClient authv1client.SelfSubjectRulesReviewInterface, opts SelfSubjectRulesReviewValidatorOpts) *SelfSubjectRulesReviewValidator {
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = DefaultSelfSubjectRulesReviewCacheTTL
+	}
+	if opts.Clock == nil {
+		opts.Clock = clock.RealClock{}
+	}
 	return &SelfSubjectRulesReviewValidator{
 		ssrrClient: ssrrClient,
-		cache:      make(map[string][]rbacv1.PolicyRule),
+		cache:      make(map[string]rulesCacheEntry),
+		opts:       opts,
 		mu:         sync.Mutex{},
 	}
 }
@@ -100,49 +142,18 @@ func (rv *SelfSubjectRulesReviewValidator) ValidatePermissions(ctx context.Conte
 	rv.mu.Lock()
 	defer rv.mu.Unlock()
 
-	ns := resourceAttrib.Namespace
-	if ns == "" {
-		ns = "default"
-	}
-
-	if _, ok := rv.cache[ns]; !ok {
-		rules := []rbacv1.PolicyRule{}
-		ssrr, err := rv.ssrrClient.Create(ctx,
-			&authv1.SelfSubjectRulesReview{
-				Spec: authv1.SelfSubjectRulesReviewSpec{
-					Namespace: ns,
-				},
-			},
-			v1.CreateOptions{},
-		)
-		if err != nil {
-			return fmt.Errorf("creating selfsubjectrulesreview: %w", err)
-		}
-		if ssrr.Status.Incomplete {
-			return errors.New("selfsubjectrulesreview is incomplete")
-		}
-
-		for _, rule := range ssrr.Status.ResourceRules {
-			rules = append(rules, rbacv1.PolicyRule{
-				Verbs:         rule.Verbs,
-				APIGroups:     rule.APIGroups,
-				Resources:     rule.Resources,
-				ResourceNames: rule.ResourceNames,
-			})
-		}
-
-		for _, rule := range ssrr.Status.NonResourceRules {
-			rules = append(rules, rbacv1.PolicyRule{
-				Verbs:           rule.Verbs,
-				NonResourceURLs: rule.NonResourceURLs,
-			})
-		}
+	return rv.validatePermissions(ctx, resourceAttrib)
+}
 
-		rv.cache[ns] = rules
+// validatePermissions validates permissions against cached rules.
+// Callers must hold rv.mu.
+func (rv *SelfSubjectRulesReviewValidator) validatePermissions(ctx context.Context, resourceAttrib *authv1.ResourceAttributes) error {
+	// Empty namespace indicates cluster scoped resource
+	rules, err := rv.rulesForNamespace(ctx, resourceAttrib.Namespace)
+	if err != nil {
+		return err
 	}
 
-	rules := rv.cache[ns]
-
 	if !rbacauthorizer.RulesAllow(authorizer.AttributesRecord{
 		Verb:            resourceAttrib.Verb,
 		Name:            resourceAttrib.Name,
@@ -163,6 +174,110 @@ func (rv *SelfSubjectRulesReviewValidator) ValidatePermissions(ctx context.Conte
 	return nil
 }
 
+// rulesForNamespace returns the cached rules for a namespace, fetching
+// them via SelfSubjectRulesReview if they are not cached or have expired.
+// Callers must hold rv.mu.
+func (rv *SelfSubjectRulesReviewValidator) rulesForNamespace(ctx context.Context, ns string) ([]rbacv1.PolicyRule, error) {
+	if entry, ok := rv.cache[ns]; ok && rv.opts.Clock.Since(entry.fetchedAt) < rv.opts.CacheTTL {
+		return entry.rules, nil
+	}
+
+	rules, err := rv.fetchRules(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	rv.cache[ns] = rulesCacheEntry{rules: rules, fetchedAt: rv.opts.Clock.Now()}
+
+	return rules, nil
+}
+
+// Prime fetches and caches rules for all given namespaces concurrently
+// so that subsequent permission validations do not need to fetch them
+func (rv *SelfSubjectRulesReviewValidator) Prime(ctx context.Context, namespaces []string) error {
+	uniqNamespaces := map[string]struct{}{}
+	for _, ns := range namespaces {
+		uniqNamespaces[ns] = struct{}{}
+	}
+
+	throttle := util.NewThrottle(selfSubjectRulesReviewPrimeConcurrency)
+	errCh := make(chan error, len(uniqNamespaces))
+	var wg sync.WaitGroup
+
+	for ns := range uniqNamespaces {
+		ns := ns // copy
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			throttle.Take()
+			defer throttle.Done()
+
+			rules, err := rv.fetchRules(ctx, ns)
+			if err != nil {
+				errCh <- fmt.Errorf("priming rules for namespace %q: %w", ns, err)
+				return
+			}
+
+			rv.mu.Lock()
+			rv.cache[ns] = rulesCacheEntry{rules: rules, fetchedAt: rv.opts.Clock.Now()}
+			rv.mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// fetchRules fetches rules for a namespace via SelfSubjectRulesReview.
+// Empty namespace fetches rules applicable to cluster scoped resources.
+func (rv *SelfSubjectRulesReviewValidator) fetchRules(ctx context.Context, ns string) ([]rbacv1.PolicyRule, error) {
+	if ns == "" {
+		ns = ClusterScopeRulesNamespace
+	}
+
+	rules := []rbacv1.PolicyRule{}
+	ssrr, err := rv.ssrrClient.Create(ctx,
+		&authv1.SelfSubjectRulesReview{
+			Spec: authv1.SelfSubjectRulesReviewSpec{
+				Namespace: ns,
+			},
+		},
+		v1.CreateOptions{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating selfsubjectrulesreview: %w", err)
+	}
+	if ssrr.Status.Incomplete {
+		return nil, errors.New("selfsubjectrulesreview is incomplete")
+	}
+
+	for _, rule := range ssrr.Status.ResourceRules {
+		rules = append(rules, rbacv1.PolicyRule{
+			Verbs:         rule.Verbs,
+			APIGroups:     rule.APIGroups,
+			Resources:     rule.Resources,
+			ResourceNames: rule.ResourceNames,
+		})
+	}
+
+	for _, rule := range ssrr.Status.NonResourceRules {
+		rules = append(rules, rbacv1.PolicyRule{
+			Verbs:           rule.Verbs,
+			NonResourceURLs: rule.NonResourceURLs,
+		})
+	}
+
+	return rules, nil
+}
+
 // RulesForRole will return a slice of rbacv1.PolicyRule objects
 // that are representative of a provided (Cluster)Role's rules.
 // It returns an error if one occurs during the process of fetching this
@@ -0,0 +1,123 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package permissions_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"carvel.dev/kapp/pkg/kapp/permissions"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestSelfSubjectRulesReviewValidatorCacheTTL(t *testing.T) {
+	fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+	ssrrClient := &fakeSSRRClient{
+		rules: map[string][]authv1.ResourceRule{
+			"default": {{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"configmaps"}}},
+		},
+	}
+
+	validator := permissions.NewSelfSubjectRulesReviewValidatorWithOpts(ssrrClient, permissions.SelfSubjectRulesReviewValidatorOpts{
+		CacheTTL: time.Minute,
+		Clock:    fakeClock,
+	})
+
+	attrs := &authv1.ResourceAttributes{Verb: "get", Resource: "configmaps", Namespace: "default"}
+
+	require.NoError(t, validator.ValidatePermissions(context.Background(), attrs))
+	require.Equal(t, 1, ssrrClient.calls["default"])
+
+	// cached rules are used before the TTL elapses
+	fakeClock.SetTime(fakeClock.Now().Add(30 * time.Second))
+	require.NoError(t, validator.ValidatePermissions(context.Background(), attrs))
+	require.Equal(t, 1, ssrrClient.calls["default"])
+
+	// permissions are revoked, but not yet observed due to caching
+	ssrrClient.rules["default"] = nil
+	require.NoError(t, validator.ValidatePermissions(context.Background(), attrs))
+	require.Equal(t, 1, ssrrClient.calls["default"])
+
+	// rules are refetched after the TTL elapses
+	fakeClock.SetTime(fakeClock.Now().Add(31 * time.Second))
+	require.Error(t, validator.ValidatePermissions(context.Background(), attrs))
+	require.Equal(t, 2, ssrrClient.calls["default"])
+}
+
+func TestSelfSubjectRulesReviewValidatorPrime(t *testing.T) {
+	ssrrClient := &fakeSSRRClient{
+		rules: map[string][]authv1.ResourceRule{
+			"ns1":                                  {{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"configmaps"}}},
+			"ns2":                                  {{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}}},
+			permissions.ClusterScopeRulesNamespace: {{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"namespaces"}}},
+		},
+	}
+
+	validator := permissions.NewSelfSubjectRulesReviewValidator(ssrrClient)
+
+	// cluster scoped resources (empty namespace) are checked against cluster wide rules
+	require.NoError(t, validator.Prime(context.Background(), []string{"ns1", "ns2", "ns1", ""}))
+	require.Equal(t, map[string]int{"ns1": 1, "ns2": 1, permissions.ClusterScopeRulesNamespace: 1}, ssrrClient.calls)
+
+	// primed rules are used without additional requests
+	require.NoError(t, validator.ValidatePermissions(context.Background(),
+		&authv1.ResourceAttributes{Verb: "get", Resource: "configmaps", Namespace: "ns1"}))
+	require.NoError(t, validator.ValidatePermissions(context.Background(),
+		&authv1.ResourceAttributes{Verb: "get", Resource: "secrets", Namespace: "ns2"}))
+	require.NoError(t, validator.ValidatePermissions(context.Background(),
+		&authv1.ResourceAttributes{Verb: "get", Resource: "namespaces"}))
+	require.Equal(t, map[string]int{"ns1": 1, "ns2": 1, permissions.ClusterScopeRulesNamespace: 1}, ssrrClient.calls)
+}
+
+func TestSelfSubjectRulesReviewValidatorClusterScoped(t *testing.T) {
+	ssrrClient := &fakeSSRRClient{
+		rules: map[string][]authv1.ResourceRule{
+			// RoleBinding in default namespace grants access to
+			// clusterroles, which must not allow cluster scoped changes
+			"default":                              {{Verbs: []string{"create"}, APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}}},
+			permissions.ClusterScopeRulesNamespace: {{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"namespaces"}}},
+		},
+	}
+
+	validator := permissions.NewSelfSubjectRulesReviewValidator(ssrrClient)
+
+	require.NoError(t, validator.ValidatePermissions(context.Background(),
+		&authv1.ResourceAttributes{Verb: "create", Resource: "namespaces"}))
+
+	err := validator.ValidatePermissions(context.Background(),
+		&authv1.ResourceAttributes{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "clusterroles"})
+	require.ErrorContains(t, err, `not permitted to "create" rbac.authorization.k8s.io/, Resource=clusterroles`)
+
+	// cluster scoped rules are cached separately from default namespace rules
+	require.Equal(t, map[string]int{permissions.ClusterScopeRulesNamespace: 1}, ssrrClient.calls)
+
+	require.NoError(t, validator.ValidatePermissions(context.Background(),
+		&authv1.ResourceAttributes{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Namespace: "default"}))
+	require.Equal(t, map[string]int{permissions.ClusterScopeRulesNamespace: 1, "default": 1}, ssrrClient.calls)
+}
+
+type fakeSSRRClient struct {
+	rules map[string][]authv1.ResourceRule
+	calls map[string]int
+	mu    sync.Mutex
+}
+
+func (c *fakeSSRRClient) Create(_ context.Context, ssrr *authv1.SelfSubjectRulesReview, _ metav1.CreateOptions) (*authv1.SelfSubjectRulesReview, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.calls == nil {
+		c.calls = map[string]int{}
+	}
+	c.calls[ssrr.Spec.Namespace]++
+
+	result := ssrr.DeepCopy()
+	result.Status.ResourceRules = c.rules[ssrr.Spec.Namespace]
+	return result, nil
+}
@@ -6,6 +6,7 @@ package preflight
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"carvel.dev/kapp/pkg/kapp/config"
@@ -13,13 +14,28 @@ import (
 	"github.com/spf13/pflag"
 )
 
-const preflightFlag = "preflight"
+const (
+	preflightFlag      = "preflight"
+	preflightOrderFlag = "preflight-order"
+
+	preflightChangedOnlyFlag = "preflight-changed-only"
+
+	// Special --preflight values to enable or disable all checks
+	preflightAll  = "all"
+	preflightNone = "none"
+)
 
 // Registry is a collection of preflight checks
 type Registry struct {
 	known map[string]Check
 	// Stores the enabled values from the command line
 	enabledFlag map[string]bool
+	// Whether "all" was last specified (instead of "none")
+	allEnabled bool
+	// Stores the execution order from the command line
+	order []string
+	// Restricts checks to changes that are not noop
+	changedOnly bool
 }
 
 // NewRegistry will return a new *Registry with the
@@ -58,7 +74,12 @@ func (c *Registry) Type() string {
 // CheckName,...
 // and sets the specified preflight check
 // as enabled if listed, otherwise, sets as
-// disabled if not listed.
+// disabled if not listed. Special values
+// "all" and "none" enable or disable all checks,
+// and "-CheckName" disables a check enabled by
+// preceding "all" (e.g. "all,-CheckName"). Values
+// are applied from left to right, including values
+// of multiple --preflight flags.
 // Returns an error if there is a problem
 // parsing the preflight checks
 func (c *Registry) Set(s string) error {
@@ -69,10 +90,28 @@ func (c *Registry) Set(s string) error {
 	// Using enabledFlag allows multiple --preflight check flags to be specified
 	mappings := strings.Split(s, ",")
 	for _, key := range mappings {
-		if _, ok := c.known[key]; !ok {
-			return fmt.Errorf("unknown preflight check %q specified", key)
+		switch {
+		case key == preflightAll || key == preflightNone:
+			for name := range c.known {
+				c.enabledFlag[name] = key == preflightAll
+			}
+			c.allEnabled = key == preflightAll
+		case strings.HasPrefix(key, "-"):
+			name := strings.TrimPrefix(key, "-")
+			if _, ok := c.known[name]; !ok {
+				return fmt.Errorf("unknown preflight check %q specified", name)
+			}
+			// Otherwise subtracting would silently leave all checks disabled
+			if !c.allEnabled {
+				return fmt.Errorf("preflight check %q can only be disabled after %q (e.g. \"%s,%s\")", name, preflightAll, preflightAll, key)
+			}
+			c.enabledFlag[name] = false
+		default:
+			if _, ok := c.known[key]; !ok {
+				return fmt.Errorf("unknown preflight check %q specified", key)
+			}
+			c.enabledFlag[key] = true
 		}
-		c.enabledFlag[key] = true
 	}
 
 	// enable/disabled based on validators specified
@@ -93,7 +132,68 @@ func (c *Registry) AddFlags(flags *pflag.FlagSet) {
 	for name := range c.known {
 		knownChecks = append(knownChecks, name)
 	}
-	flags.Var(c, preflightFlag, fmt.Sprintf("preflight checks to run. Available preflight checks are [%s]", strings.Join(knownChecks, ",")))
+	sort.Strings(knownChecks)
+	flags.Var(c, preflightFlag, fmt.Sprintf("preflight checks to run (%q and %q enable or disable all checks, "+
+		"\"-CheckName\" disables a check, e.g. \"all,-CheckName\"). Available preflight checks are [%s]",
+		preflightAll, preflightNone, strings.Join(knownChecks, ",")))
+	flags.Var(&orderValue{c}, preflightOrderFlag, "order in which preflight checks run (format: CheckName,...); unlisted checks run afterwards in alphabetical order")
+	flags.BoolVar(&c.changedOnly, preflightChangedOnlyFlag, false, "run preflight checks only against resources that are going to be changed (skips noop changes)")
+
+	for _, name := range c.orderedNames() {
+		if check, ok := c.known[name].(CheckWithFlags); ok {
+			check.AddFlags(flags)
+		}
+	}
+}
+
+// SetFieldManager passes field manager used
+// for resource writes to checks that depend on it
+func (c *Registry) SetFieldManager(name string) {
+	for _, check := range c.known {
+		if check, ok := check.(CheckWithFieldManager); ok {
+			check.SetFieldManager(name)
+		}
+	}
+}
+
+// SetOrder takes in a list of check names
+// and runs enabled preflight checks in that
+// order. Checks that are not listed run
+// afterwards in alphabetical order.
+// Returns an error if a check is unknown or
+// listed more than once
+func (c *Registry) SetOrder(names []string) error {
+	seen := map[string]bool{}
+	for _, name := range names {
+		if _, ok := c.known[name]; !ok {
+			return fmt.Errorf("unknown preflight check %q specified in order", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate preflight check %q specified in order", name)
+		}
+		seen[name] = true
+	}
+	c.order = names
+	return nil
+}
+
+func (c *Registry) orderedNames() []string {
+	names := append([]string{}, c.order...)
+
+	listed := map[string]bool{}
+	for _, name := range c.order {
+		listed[name] = true
+	}
+
+	var unlisted []string
+	for name := range c.known {
+		if !listed[name] {
+			unlisted = append(unlisted, name)
+		}
+	}
+	sort.Strings(unlisted)
+
+	return append(names, unlisted...)
 }
 
 // AddCheck adds a new preflight check to the registry.
@@ -154,9 +254,17 @@ func (c *Registry) SetConfig(conf []config.PreflightRule) error {
 
 // Run will execute any enabled preflight checks. The provided
 // Context and ChangeGraph will be passed to the preflight checks
-// that are being executed.
+// that are being executed. If --preflight-changed-only is set,
+// noop changes are excluded from the ChangeGraph.
 func (c *Registry) Run(ctx context.Context, cg *ctldgraph.ChangeGraph) error {
-	for name, check := range c.known {
+	if c.changedOnly && cg != nil {
+		cg = cg.Filtered(func(change *ctldgraph.Change) bool {
+			return change.Change.Op() != ctldgraph.ActualChangeOpNoop
+		})
+	}
+
+	for _, name := range c.orderedNames() {
+		check := c.known[name]
 		if check.Enabled() {
 			err := check.Run(ctx, cg)
 			if err != nil {
@@ -166,3 +274,27 @@ func (c *Registry) Run(ctx context.Context, cg *ctldgraph.ChangeGraph) error {
 	}
 	return nil
 }
+
+// orderValue implements the pflag.Value
+// interface for the --preflight-order flag
+type orderValue struct {
+	registry *Registry
+}
+
+func (o *orderValue) String() string {
+	if o.registry == nil {
+		return ""
+	}
+	return strings.Join(o.registry.order, ",")
+}
+
+func (o *orderValue) Type() string {
+	return "string"
+}
+
+func (o *orderValue) Set(s string) error {
+	if o.registry.known == nil {
+		return nil
+	}
+	return o.registry.SetOrder(strings.Split(s, ","))
+}
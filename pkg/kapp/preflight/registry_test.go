@@ -333,3 +333,168 @@ preflightRules:
 		})
 	}
 }
+
+func TestRegistrySetAllNone(t *testing.T) {
+	newRegistry := func() *Registry {
+		return NewRegistry(map[string]Check{
+			"checkA": NewCheck(nil, nil, false),
+			"checkB": NewCheck(nil, nil, true),
+			"checkC": NewCheck(nil, nil, false),
+		})
+	}
+
+	testCases := []struct {
+		name       string
+		preflights []string
+		results    map[string]bool
+		err        string
+	}{
+		{
+			name:       "all enables every check",
+			preflights: []string{"all"},
+			results:    map[string]bool{"checkA": true, "checkB": true, "checkC": true},
+		},
+		{
+			name:       "none disables every check",
+			preflights: []string{"none"},
+			results:    map[string]bool{"checkA": false, "checkB": false, "checkC": false},
+		},
+		{
+			name:       "all with subtraction disables listed checks",
+			preflights: []string{"all,-checkB,-checkC"},
+			results:    map[string]bool{"checkA": true, "checkB": false, "checkC": false},
+		},
+		{
+			name:       "none followed by check enables only that check",
+			preflights: []string{"none,checkC"},
+			results:    map[string]bool{"checkA": false, "checkB": false, "checkC": true},
+		},
+		{
+			name:       "values from multiple flags are applied in order",
+			preflights: []string{"all", "-checkA"},
+			results:    map[string]bool{"checkA": false, "checkB": true, "checkC": true},
+		},
+		{
+			name:       "subtraction without all",
+			preflights: []string{"-checkB"},
+			err:        `preflight check "checkB" can only be disabled after "all" (e.g. "all,-checkB")`,
+		},
+		{
+			name:       "subtraction after none",
+			preflights: []string{"all,none,-checkB"},
+			err:        `preflight check "checkB" can only be disabled after "all" (e.g. "all,-checkB")`,
+		},
+		{
+			name:       "subtraction of unknown check",
+			preflights: []string{"all,-nonexistent"},
+			err:        `unknown preflight check "nonexistent" specified`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := newRegistry()
+
+			var err error
+			for _, val := range tc.preflights {
+				err = registry.Set(val)
+				if err != nil {
+					break
+				}
+			}
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			// Config must not re-enable checks disabled on the command line
+			require.NoError(t, registry.SetConfig([]ctlconf.PreflightRule{{Name: "checkB"}}))
+
+			for k, v := range tc.results {
+				require.Equalf(t, v, registry.known[k].Enabled(), "Unexpected enable value for %s", k)
+			}
+		})
+	}
+}
+
+func TestRegistryRunOrder(t *testing.T) {
+	testCases := []struct {
+		name          string
+		order         string
+		expectedOrder []string
+		shouldErr     bool
+	}{
+		{
+			name:          "no order specified, checks run in alphabetical order",
+			expectedOrder: []string{"aCheck", "bCheck", "cCheck"},
+		},
+		{
+			name:          "full order specified, checks run in specified order",
+			order:         "cCheck,aCheck,bCheck",
+			expectedOrder: []string{"cCheck", "aCheck", "bCheck"},
+		},
+		{
+			name:          "partial order specified, unlisted checks run afterwards",
+			order:         "bCheck",
+			expectedOrder: []string{"bCheck", "aCheck", "cCheck"},
+		},
+		{
+			name:      "unknown check in order, error returned",
+			order:     "nonexistent",
+			shouldErr: true,
+		},
+		{
+			name:      "duplicate check in order, error returned",
+			order:     "aCheck,aCheck",
+			shouldErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var executed []string
+			newCheck := func(name string) Check {
+				return NewCheck(func(_ context.Context, _ *diffgraph.ChangeGraph, _ CheckConfig) error {
+					executed = append(executed, name)
+					return nil
+				}, nil, true)
+			}
+			registry := NewRegistry(map[string]Check{
+				"aCheck": newCheck("aCheck"),
+				"bCheck": newCheck("bCheck"),
+				"cCheck": newCheck("cCheck"),
+			})
+
+			if tc.order != "" {
+				err := (&orderValue{registry}).Set(tc.order)
+				require.Equalf(t, tc.shouldErr, err != nil, "Unexpected error: %v", err)
+				if tc.shouldErr {
+					return
+				}
+			}
+
+			err := registry.Run(nil, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedOrder, executed)
+		})
+	}
+}
+
+func TestRegistrySetFieldManager(t *testing.T) {
+	withFieldManager := &fieldManagerCheck{Check: NewCheck(nil, nil, true)}
+	registry := NewRegistry(map[string]Check{
+		"withFieldManager":    withFieldManager,
+		"withoutFieldManager": NewCheck(nil, nil, true),
+	})
+
+	registry.SetFieldManager("my-manager")
+	require.Equal(t, "my-manager", withFieldManager.fieldManager)
+}
+
+type fieldManagerCheck struct {
+	Check
+	fieldManager string
+}
+
+func (c *fieldManagerCheck) SetFieldManager(name string) { c.fieldManager = name }
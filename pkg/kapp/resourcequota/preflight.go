@@ -0,0 +1,206 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcequota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/preflight"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ preflight.Check = (*Preflight)(nil)
+
+// Preflight is an implementation of preflight.Check
+// that checks that resources created in each namespace
+// would not exceed ResourceQuotas in that namespace
+type Preflight struct {
+	depsFactory cmdcore.DepsFactory
+	enabled     bool
+}
+
+func NewPreflight(depsFactory cmdcore.DepsFactory, enabled bool) *Preflight {
+	return &Preflight{depsFactory: depsFactory, enabled: enabled}
+}
+
+func (p *Preflight) Enabled() bool {
+	return p.enabled
+}
+
+func (p *Preflight) SetEnabled(enabled bool) {
+	p.enabled = enabled
+}
+
+func (p *Preflight) SetConfig(_ preflight.CheckConfig) error {
+	return nil
+}
+
+func (p *Preflight) Run(ctx context.Context, changeGraph *ctldgraph.ChangeGraph) error {
+	coreClient, err := p.depsFactory.CoreClient()
+	if err != nil {
+		return fmt.Errorf("getting core client: %w", err)
+	}
+
+	dynamicClient, err := p.depsFactory.DynamicClient(cmdcore.DynamicClientOpts{})
+	if err != nil {
+		return fmt.Errorf("getting dynamic client: %w", err)
+	}
+
+	mapper, err := p.depsFactory.RESTMapper()
+	if err != nil {
+		return fmt.Errorf("getting RESTMapper: %w", err)
+	}
+
+	upsertsByNs := map[string][]ctlres.Resource{}
+
+	for _, change := range changeGraph.All() {
+		if change.Change.Op() != ctldgraph.ActualChangeOpUpsert {
+			continue
+		}
+		res := change.Change.Resource()
+		if len(res.Namespace()) == 0 {
+			continue
+		}
+		upsertsByNs[res.Namespace()] = append(upsertsByNs[res.Namespace()], res)
+	}
+
+	var namespaces []string
+	for ns := range upsertsByNs {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	validateErrs := []error{}
+	for _, ns := range namespaces {
+		// Only namespaces with quotas are worth checking for created resources
+		quotas, err := coreClient.CoreV1().ResourceQuotas(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("listing resource quotas in namespace '%s': %w", ns, err)
+		}
+		if len(quotas.Items) == 0 {
+			continue
+		}
+
+		created, grs, err := createdResources(ctx, dynamicClient, mapper, ns, upsertsByNs[ns])
+		if err != nil {
+			return err
+		}
+
+		resourceNameFunc := func(res ctlres.Resource) (schema.GroupResource, error) {
+			return grs[res.GroupKind()], nil
+		}
+
+		usage, err := Usage(created, resourceNameFunc)
+		if err != nil {
+			return err
+		}
+
+		for _, quota := range quotas.Items {
+			validateErrs = append(validateErrs, Exceeded(quota, usage)...)
+		}
+	}
+
+	if len(validateErrs) > 0 {
+		baseErr := errors.New("validation for resource quotas failed")
+		return errors.Join(append([]error{baseErr}, validateErrs...)...)
+	}
+
+	return nil
+}
+
+// Exceeded returns errors for each resource limited by quota
+// that would be exceeded after adding given usage. Quotas with
+// scopes are skipped since they only apply to subset of resources.
+func Exceeded(quota corev1.ResourceQuota, usage corev1.ResourceList) []error {
+	if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+		return nil
+	}
+
+	var names []string
+	for name := range quota.Status.Hard {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		requested, found := usage[corev1.ResourceName(name)]
+		if !found {
+			continue
+		}
+		hard := quota.Status.Hard[corev1.ResourceName(name)]
+		used := quota.Status.Used[corev1.ResourceName(name)]
+
+		total := used.DeepCopy()
+		total.Add(requested)
+
+		if total.Cmp(hard) > 0 {
+			errs = append(errs, fmt.Errorf("namespace '%s': resource quota '%s' would be exceeded for '%s' (requested: %s, used: %s, limited: %s)",
+				quota.Namespace, quota.Name, name, requested.String(), used.String(), hard.String()))
+		}
+	}
+	return errs
+}
+
+// createdResources returns resources that do not exist yet in given namespace
+// and plural resource names of their kinds. Existing resources are listed once
+// per kind. Kinds unknown to the cluster (e.g. defined by CRDs created in the
+// same deploy) are skipped, since their quota usage cannot be determined yet.
+func createdResources(ctx context.Context, dynamicClient dynamic.Interface, mapper meta.RESTMapper,
+	ns string, resources []ctlres.Resource) ([]ctlres.Resource, map[schema.GroupKind]schema.GroupResource, error) {
+
+	var kinds []schema.GroupKind
+	resourcesByKind := map[schema.GroupKind][]ctlres.Resource{}
+
+	for _, res := range resources {
+		gk := res.GroupKind()
+		if _, found := resourcesByKind[gk]; !found {
+			kinds = append(kinds, gk)
+		}
+		resourcesByKind[gk] = append(resourcesByKind[gk], res)
+	}
+
+	var created []ctlres.Resource
+	grs := map[schema.GroupKind]schema.GroupResource{}
+
+	for _, gk := range kinds {
+		mapping, err := mapper.RESTMapping(gk, resourcesByKind[gk][0].GroupVersion().Version)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, nil, fmt.Errorf("getting resource mapping for %s: %w", gk, err)
+		}
+
+		list, err := dynamicClient.Resource(mapping.Resource).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing %s in namespace '%s': %w", mapping.Resource.GroupResource(), ns, err)
+		}
+
+		existingNames := map[string]struct{}{}
+		for _, item := range list.Items {
+			existingNames[item.GetName()] = struct{}{}
+		}
+
+		grs[gk] = mapping.Resource.GroupResource()
+
+		for _, res := range resourcesByKind[gk] {
+			if _, found := existingNames[res.Name()]; !found {
+				created = append(created, res)
+			}
+		}
+	}
+
+	return created, grs, nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcequota_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	"carvel.dev/kapp/pkg/kapp/resourcequota"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func TestUsage(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod1
+  namespace: ns
+spec:
+  initContainers:
+  - name: init
+    resources:
+      requests: {cpu: 500m, memory: 64Mi}
+  containers:
+  - name: app
+    resources:
+      requests: {cpu: 100m, memory: 64Mi}
+      limits: {cpu: 200m}
+  - name: sidecar
+    resources:
+      requests: {cpu: 100m, memory: 32Mi}
+      limits: {cpu: 100m}
+`)),
+		ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod2
+  namespace: ns
+spec:
+  containers:
+  - name: app
+    resources:
+      requests: {cpu: 50m}
+`)),
+		ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+  namespace: ns
+`)),
+	}
+
+	resourceNameFunc := func(res ctlres.Resource) (schema.GroupResource, error) {
+		return schema.GroupResource{Group: res.GroupKind().Group, Resource: strings.ToLower(res.Kind()) + "s"}, nil
+	}
+
+	usage, err := resourcequota.Usage(resources, resourceNameFunc)
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"pods":                   "2",
+		"count/pods":             "2",
+		"count/deployments.apps": "1",
+		"cpu":                    "550m",
+		"requests.cpu":           "550m",
+		"memory":                 "96Mi",
+		"requests.memory":        "96Mi",
+		"limits.cpu":             "300m",
+	}
+
+	actual := map[string]string{}
+	for name, quantity := range usage {
+		actual[string(name)] = quantity.String()
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestExceeded(t *testing.T) {
+	quota := corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "ns"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				"pods":         resource.MustParse("2"),
+				"requests.cpu": resource.MustParse("1"),
+			},
+			Used: corev1.ResourceList{
+				"pods":         resource.MustParse("1"),
+				"requests.cpu": resource.MustParse("500m"),
+			},
+		},
+	}
+
+	errs := resourcequota.Exceeded(quota, corev1.ResourceList{
+		"pods":         resource.MustParse("1"),
+		"requests.cpu": resource.MustParse("500m"),
+	})
+	assert.Empty(t, errs)
+
+	errs = resourcequota.Exceeded(quota, corev1.ResourceList{
+		"pods":         resource.MustParse("2"),
+		"requests.cpu": resource.MustParse("200m"),
+	})
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "namespace 'ns': resource quota 'quota' would be exceeded for 'pods' (requested: 2, used: 1, limited: 2)")
+
+	quota.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+	assert.Empty(t, resourcequota.Exceeded(quota, corev1.ResourceList{"pods": resource.MustParse("5")}))
+}
+
+func TestPreflightRun(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "cm", "namespace": "no-quota"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "existing", "namespace": "quota"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "new1", "namespace": "quota"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "new2", "namespace": "quota"}}`)),
+		// Kind is not known until its CRD gets created
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion": "example.com/v1", "kind": "Foo", "metadata": {"name": "foo", "namespace": "quota"}}`)),
+	}
+
+	var changes []ctldgraph.ActualChange
+	for _, res := range resources {
+		changes = append(changes, actualChange{res, ctldgraph.ActualChangeOpUpsert})
+	}
+
+	changeGraph, err := ctldgraph.NewChangeGraph(changes, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	quotas := map[string][]corev1.ResourceQuota{
+		"quota": {{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "quota"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{"pods": resource.MustParse("2")},
+				Used: corev1.ResourceList{"pods": resource.MustParse("1")},
+			},
+		}},
+	}
+	dynamicClient := &fakeDynamicClient{existing: map[string][]string{"quota/pods": {"existing"}}}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+
+	depsFactory := fakeDepsFactory{coreClient: fakeCoreClient{quotas: quotas}, dynamicClient: dynamicClient, mapper: mapper}
+
+	err = resourcequota.NewPreflight(depsFactory, true).Run(context.Background(), changeGraph)
+	require.EqualError(t, err, "validation for resource quotas failed\n"+
+		"namespace 'quota': resource quota 'quota' would be exceeded for 'pods' (requested: 2, used: 1, limited: 2)")
+
+	// Existing resources are listed once per kind only in namespaces with quotas
+	require.Equal(t, []string{"quota/pods"}, dynamicClient.lists)
+}
+
+type actualChange struct {
+	res ctlres.Resource
+	op  ctldgraph.ActualChangeOp
+}
+
+func (a actualChange) Resource() ctlres.Resource    { return a.res }
+func (a actualChange) Op() ctldgraph.ActualChangeOp { return a.op }
+
+type fakeDepsFactory struct {
+	cmdcore.DepsFactory
+	coreClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+}
+
+func (f fakeDepsFactory) CoreClient() (kubernetes.Interface, error) { return f.coreClient, nil }
+func (f fakeDepsFactory) RESTMapper() (meta.RESTMapper, error)      { return f.mapper, nil }
+func (f fakeDepsFactory) DynamicClient(cmdcore.DynamicClientOpts) (dynamic.Interface, error) {
+	return f.dynamicClient, nil
+}
+
+type fakeCoreClient struct {
+	kubernetes.Interface
+	quotas map[string][]corev1.ResourceQuota
+}
+
+func (c fakeCoreClient) CoreV1() corev1client.CoreV1Interface {
+	return fakeCoreV1Client{quotas: c.quotas}
+}
+
+type fakeCoreV1Client struct {
+	corev1client.CoreV1Interface
+	quotas map[string][]corev1.ResourceQuota
+}
+
+func (c fakeCoreV1Client) ResourceQuotas(ns string) corev1client.ResourceQuotaInterface {
+	return fakeQuotaClient{quotas: c.quotas[ns]}
+}
+
+type fakeQuotaClient struct {
+	corev1client.ResourceQuotaInterface
+	quotas []corev1.ResourceQuota
+}
+
+func (c fakeQuotaClient) List(context.Context, metav1.ListOptions) (*corev1.ResourceQuotaList, error) {
+	return &corev1.ResourceQuotaList{Items: c.quotas}, nil
+}
+
+// fakeDynamicClient lists existing resources by "<namespace>/<resource>"
+// and records lists made
+type fakeDynamicClient struct {
+	dynamic.Interface
+	existing map[string][]string
+	lists    []string
+}
+
+func (c *fakeDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return fakeResourceClient{client: c, gvr: gvr}
+}
+
+type fakeResourceClient struct {
+	dynamic.NamespaceableResourceInterface
+	client *fakeDynamicClient
+	gvr    schema.GroupVersionResource
+	ns     string
+}
+
+func (c fakeResourceClient) Namespace(ns string) dynamic.ResourceInterface {
+	return fakeResourceClient{client: c.client, gvr: c.gvr, ns: ns}
+}
+
+func (c fakeResourceClient) List(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	key := c.ns + "/" + c.gvr.Resource
+	c.client.lists = append(c.client.lists, key)
+
+	list := &unstructured.UnstructuredList{}
+	for _, name := range c.client.existing[key] {
+		item := unstructured.Unstructured{}
+		item.SetName(name)
+		list.Items = append(list.Items, item)
+	}
+	return list, nil
+}
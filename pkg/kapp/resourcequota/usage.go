@@ -0,0 +1,109 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcequota
+
+import (
+	"fmt"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// legacyCountedResources are core resources that
+// quota also counts under their plain names (e.g. "pods")
+var legacyCountedResources = map[string]struct{}{
+	"pods": {}, "services": {}, "configmaps": {}, "secrets": {},
+	"persistentvolumeclaims": {}, "replicationcontrollers": {}, "resourcequotas": {},
+}
+
+// ResourceNameFunc returns plural resource name
+// (e.g. "deployments") for a resource
+type ResourceNameFunc func(ctlres.Resource) (schema.GroupResource, error)
+
+// Usage returns quota usage that would be added by creating given
+// resources: object counts (e.g. "count/deployments.apps", "pods")
+// and compute resources requested by Pods (e.g. "requests.cpu").
+// Compute resources of Pods created by workload controllers are
+// not included since they are not known until Pods get created.
+func Usage(resources []ctlres.Resource, resourceNameFunc ResourceNameFunc) (corev1.ResourceList, error) {
+	usage := corev1.ResourceList{}
+
+	for _, res := range resources {
+		gr, err := resourceNameFunc(res)
+		if err != nil {
+			return nil, fmt.Errorf("Determining resource name of %s: %w", res.Description(), err)
+		}
+
+		addQuantity(usage, corev1.ResourceName("count/"+gr.String()), *resource.NewQuantity(1, resource.DecimalSI))
+
+		if gr.Group == "" {
+			if _, found := legacyCountedResources[gr.Resource]; found {
+				addQuantity(usage, corev1.ResourceName(gr.Resource), *resource.NewQuantity(1, resource.DecimalSI))
+			}
+		}
+
+		if gr == (schema.GroupResource{Resource: "pods"}) {
+			pod := corev1.Pod{}
+			err := res.AsUncheckedTypedObj(&pod)
+			if err != nil {
+				return nil, fmt.Errorf("Converting %s to Pod: %w", res.Description(), err)
+			}
+			for name, quantity := range podUsage(pod) {
+				addQuantity(usage, name, quantity)
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// podUsage returns compute resources of a Pod. Similar to the scheduler,
+// effective requests (and limits) are the greater of sum of regular
+// containers and maximum of any init container.
+func podUsage(pod corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			addQuantity(requests, name, quantity)
+		}
+		for name, quantity := range container.Resources.Limits {
+			addQuantity(limits, name, quantity)
+		}
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		maxQuantities(requests, container.Resources.Requests)
+		maxQuantities(limits, container.Resources.Limits)
+	}
+
+	usage := corev1.ResourceList{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage} {
+		if quantity, found := requests[name]; found {
+			usage[name] = quantity
+			usage[corev1.ResourceName("requests."+string(name))] = quantity
+		}
+		if quantity, found := limits[name]; found {
+			usage[corev1.ResourceName("limits."+string(name))] = quantity
+		}
+	}
+	return usage
+}
+
+func addQuantity(list corev1.ResourceList, name corev1.ResourceName, quantity resource.Quantity) {
+	current := list[name]
+	current.Add(quantity)
+	list[name] = current
+}
+
+func maxQuantities(list corev1.ResourceList, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, found := list[name]; !found || quantity.Cmp(current) > 0 {
+			list[name] = quantity
+		}
+	}
+}
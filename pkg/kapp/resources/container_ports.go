@@ -0,0 +1,34 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+// Ephemeral containers are not allowed to declare ports
+var portContainerFields = []string{"initContainers", "containers"}
+
+// ContainerPorts returns container ports (ports[].containerPort)
+// declared in pod spec (or pod template) of a resource.
+// Resources that do not bear pods have no container ports.
+func ContainerPorts(res Resource) []int64 {
+	var result []int64
+
+	for _, container := range PodSpecContainers(res, portContainerFields) {
+		ports, _, _ := unstructured.NestedSlice(container, "ports")
+		for _, port := range ports {
+			typedPort, ok := port.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch typedVal := typedPort["containerPort"].(type) {
+			case int64:
+				result = append(result, typedVal)
+			case float64:
+				result = append(result, int64(typedVal))
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerStatus summarizes readiness of a Pod container
+type ContainerStatus struct {
+	Name         string
+	Init         bool
+	Ready        bool
+	RestartCount int32
+}
+
+// ContainerStatuses returns statuses of init and regular containers
+// of a Pod in the order they are specified. Containers without
+// reported status are not ready. Other resources have none.
+func ContainerStatuses(res Resource) []ContainerStatus {
+	if res.Kind() != "Pod" || res.APIGroup() != "" {
+		return nil
+	}
+
+	var pod corev1.Pod
+	if err := res.AsUncheckedTypedObj(&pod); err != nil {
+		return nil
+	}
+
+	var result []ContainerStatus
+
+	add := func(containers []corev1.Container, statuses []corev1.ContainerStatus, init bool) {
+		for _, container := range containers {
+			status := ContainerStatus{Name: container.Name, Init: init}
+			for _, containerStatus := range statuses {
+				if containerStatus.Name == container.Name {
+					status.Ready = containerStatus.Ready
+					status.RestartCount = containerStatus.RestartCount
+					break
+				}
+			}
+			result = append(result, status)
+		}
+	}
+
+	add(pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true)
+	add(pod.Spec.Containers, pod.Status.ContainerStatuses, false)
+
+	return result
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CustomResourceGroupKinds is a set of group kinds
+// served by CustomResourceDefinitions
+type CustomResourceGroupKinds map[schema.GroupKind]struct{}
+
+// NewCustomResourceGroupKinds returns group kinds defined by given
+// CustomResourceDefinitions. Resources of other kinds are ignored.
+func NewCustomResourceGroupKinds(crds []Resource) CustomResourceGroupKinds {
+	result := CustomResourceGroupKinds{}
+
+	for _, crd := range crds {
+		if crd.GroupKind() != (schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}) {
+			continue
+		}
+		obj := crd.UnstructuredObject()
+		group, _, _ := unstructured.NestedString(obj, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj, "spec", "names", "kind")
+		if len(kind) > 0 {
+			result[schema.GroupKind{Group: group, Kind: kind}] = struct{}{}
+		}
+	}
+
+	return result
+}
+
+// Matches returns true if resource kind is served by a CustomResourceDefinition
+func (gks CustomResourceGroupKinds) Matches(res Resource) bool {
+	_, found := gks[res.GroupKind()]
+	return found
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// CreatedByFieldManager returns name of the field manager that created
+// resource, determined as manager of the earliest Apply or Update entry
+// in managed fields. Empty string is returned if no such entry is found.
+func CreatedByFieldManager(res Resource) string {
+	var creator string
+	var createdAt time.Time
+
+	managedFields, _, _ := unstructured.NestedSlice(res.UnstructuredObject(), "metadata", "managedFields")
+	for _, entry := range managedFields {
+		typedEntry, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		switch typedEntry["operation"] {
+		case "Apply", "Update":
+		default:
+			continue
+		}
+
+		manager, _ := typedEntry["manager"].(string)
+		timeStr, _ := typedEntry["time"].(string)
+
+		t, err := time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			continue
+		}
+
+		// Keep first entry on ties to make result stable
+		if len(creator) == 0 || t.Before(createdAt) {
+			creator = manager
+			createdAt = t
+		}
+	}
+
+	return creator
+}
@@ -15,6 +15,8 @@ type IdentifiedResourcesListOpts struct {
 	IgnoreCachedResTypes bool
 	GKsScope             []schema.GroupKind
 	ResourceNamespaces   []string
+	// PageSize limits number of resources returned per list request (0 means no limit)
+	PageSize int64
 }
 
 func (r IdentifiedResources) List(labelSelector labels.Selector, resRefs []ResourceRef, opts IdentifiedResourcesListOpts) ([]Resource, error) {
@@ -53,6 +55,7 @@ func (r IdentifiedResources) List(labelSelector labels.Selector, resRefs []Resou
 	allOpts := AllOpts{
 		ListOpts: &metav1.ListOptions{
 			LabelSelector: labelSelector.String(),
+			Limit:         opts.PageSize,
 		},
 		ResourceNamespaces: opts.ResourceNamespaces,
 	}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	conditionTimeFields = []string{"lastTransitionTime", "lastUpdateTime", "lastHeartbeatTime", "lastProbeTime"}
+)
+
+// LastChangedAt returns the most recent time resource is known to have changed
+// based on managed fields entries and status conditions, falling back to
+// creation time if neither are present
+func LastChangedAt(res Resource) time.Time {
+	lastChanged := res.CreatedAt()
+
+	observe := func(val interface{}) {
+		str, ok := val.(string)
+		if !ok {
+			return
+		}
+		t, err := time.Parse(time.RFC3339, str)
+		if err == nil && t.After(lastChanged) {
+			lastChanged = t
+		}
+	}
+
+	obj := res.UnstructuredObject()
+
+	managedFields, _, _ := unstructured.NestedSlice(obj, "metadata", "managedFields")
+	for _, entry := range managedFields {
+		if typedEntry, ok := entry.(map[string]interface{}); ok {
+			observe(typedEntry["time"])
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, cond := range conditions {
+		if typedCond, ok := cond.(map[string]interface{}); ok {
+			for _, field := range conditionTimeFields {
+				observe(typedCond[field])
+			}
+		}
+	}
+
+	return lastChanged
+}
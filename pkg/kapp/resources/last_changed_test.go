@@ -0,0 +1,58 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+	"time"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastChangedAt(t *testing.T) {
+	mustParse := func(str string) time.Time {
+		t, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	for _, tc := range []struct {
+		name     string
+		res      string
+		expected time.Time
+	}{
+		{
+			name:     "only creation timestamp",
+			res:      `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","creationTimestamp":"2024-01-01T00:00:00Z"}}`,
+			expected: mustParse("2024-01-01T00:00:00Z"),
+		},
+		{
+			name: "latest managed fields entry",
+			res: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","creationTimestamp":"2024-01-01T00:00:00Z",
+				"managedFields":[{"manager":"kapp","time":"2024-02-01T00:00:00Z"},{"manager":"other","time":"2024-03-01T00:00:00Z"}]}}`,
+			expected: mustParse("2024-03-01T00:00:00Z"),
+		},
+		{
+			name: "status condition newer than managed fields",
+			res: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"deploy","creationTimestamp":"2024-01-01T00:00:00Z",
+				"managedFields":[{"manager":"kapp","time":"2024-02-01T00:00:00Z"}]},
+				"status":{"conditions":[{"type":"Available","lastTransitionTime":"2024-01-15T00:00:00Z","lastUpdateTime":"2024-04-01T00:00:00Z"}]}}`,
+			expected: mustParse("2024-04-01T00:00:00Z"),
+		},
+		{
+			name: "malformed times are ignored",
+			res: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","creationTimestamp":"2024-01-01T00:00:00Z",
+				"managedFields":[{"manager":"kapp","time":"yesterday"}]}}`,
+			expected: mustParse("2024-01-01T00:00:00Z"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := ctlres.MustNewResourceFromBytes([]byte(tc.res))
+			require.True(t, tc.expected.Equal(ctlres.LastChangedAt(res)), "expected %s, got %s", tc.expected, ctlres.LastChangedAt(res))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	podTemplateSpecPath = []string{"spec", "template", "spec"}
+
+	// podSpecPaths maps pod-bearing kinds to location of their pod spec
+	podSpecPaths = map[schema.GroupKind][]string{
+		{Group: "", Kind: "Pod"}:                   {"spec"},
+		{Group: "", Kind: "PodTemplate"}:           {"template", "spec"},
+		{Group: "", Kind: "ReplicationController"}: podTemplateSpecPath,
+		{Group: "apps", Kind: "Deployment"}:        podTemplateSpecPath,
+		{Group: "apps", Kind: "StatefulSet"}:       podTemplateSpecPath,
+		{Group: "apps", Kind: "DaemonSet"}:         podTemplateSpecPath,
+		{Group: "apps", Kind: "ReplicaSet"}:        podTemplateSpecPath,
+		{Group: "batch", Kind: "Job"}:              podTemplateSpecPath,
+		{Group: "batch", Kind: "CronJob"}:          {"spec", "jobTemplate", "spec", "template", "spec"},
+	}
+)
+
+// PodSpecContainers returns containers listed under given fields
+// (e.g. containers, initContainers) of pod spec (or pod template)
+// of a resource. Resources that do not bear pods have no containers.
+func PodSpecContainers(res Resource, fields []string) []map[string]interface{} {
+	path, found := podSpecPaths[res.GroupKind()]
+	if !found {
+		return nil
+	}
+
+	podSpec, found, err := unstructured.NestedMap(res.UnstructuredObject(), path...)
+	if !found || err != nil {
+		return nil
+	}
+
+	var result []map[string]interface{}
+
+	for _, field := range fields {
+		containers, _, err := unstructured.NestedSlice(podSpec, field)
+		if err != nil {
+			continue
+		}
+		for _, container := range containers {
+			if typedContainer, ok := container.(map[string]interface{}); ok {
+				result = append(result, typedContainer)
+			}
+		}
+	}
+
+	return result
+}
@@ -6,6 +6,8 @@ package resources
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"carvel.dev/kapp/pkg/kapp/matcher" // TODO inject
@@ -15,18 +17,113 @@ import (
 type ResourceFilter struct {
 	CreatedAtBeforeTime *time.Time
 	CreatedAtAfterTime  *time.Time
+	// CreatedAtHours only includes resources created
+	// within daily hour window (in UTC)
+	CreatedAtHours *HourWindow
+	// Replicas only includes workloads (Deployments, StatefulSets,
+	// ReplicaSets) with replica count matching comparison
+	Replicas *ReplicasComparison
+	// ContainerPorts only includes pod-bearing resources with
+	// any container declaring any of given ports (see ContainerPorts func)
+	ContainerPorts []int
+	// CreatedByFieldManagers only includes resources created by any of
+	// given field managers (see CreatedByFieldManager func)
+	CreatedByFieldManagers []string
+	// CustomResources, if not nil, only includes resources
+	// of kinds served by CustomResourceDefinitions
+	CustomResources CustomResourceGroupKinds `json:"-"`
 
-	Kinds          []string
+	Kinds  []string
+	Groups []string
+	// KindGroups includes resources matching both kind and API group
+	// given as "Kind.group" (e.g. "Ingress.networking.k8s.io", "Service.core")
+	KindGroups     []string
 	Namespaces     []string
 	Names          []string
 	KindNames      []string
 	KindNamespaces []string
 	KindNsNames    []string
 	Labels         []string
+	ChangeGroups   []string
+	Finalizers     []string
+	HasFinalizers  bool
+	// Orphaned only includes resources without kapp app label
+	// (i.e. resources that are not managed by any kapp app)
+	Orphaned bool
+	// StatusReasons only includes resources with any of given
+	// reasons found in their status (see StatusReasons func)
+	StatusReasons []string
+
+	ExcludedNamespaces []string
 
 	BoolFilter *BoolFilter `json:"-"`
 }
 
+const (
+	appLabelKey             = "kapp.k14s.io/app"
+	coreGroupName           = "core" // used to match resources in core API group
+	changeGroupAnnKey       = "kapp.k14s.io/change-group"
+	changeGroupAnnPrefixKey = "kapp.k14s.io/change-group."
+)
+
+var (
+	systemNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+)
+
+// ExcludeSystemNamespaces adds well known Kubernetes system
+// namespaces to the set of excluded namespaces
+func (f *ResourceFilter) ExcludeSystemNamespaces() {
+	for _, ns := range systemNamespaces {
+		var found bool
+		for _, excludedNs := range f.ExcludedNamespaces {
+			if excludedNs == ns {
+				found = true
+				break
+			}
+		}
+		if !found {
+			f.ExcludedNamespaces = append(f.ExcludedNamespaces, ns)
+		}
+	}
+}
+
+// UnmarshalJSON additionally allows labels to be specified
+// as a single label selector string (e.g. "env in (prod,staging)")
+func (f *ResourceFilter) UnmarshalJSON(data []byte) error {
+	type resourceFilter ResourceFilter // avoids recursion
+	raw := struct {
+		*resourceFilter
+		Labels json.RawMessage
+	}{resourceFilter: (*resourceFilter)(f)}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	if len(raw.Labels) == 0 || string(raw.Labels) == "null" {
+		return nil
+	}
+
+	var labelSelector string
+	if json.Unmarshal(raw.Labels, &labelSelector) == nil {
+		f.Labels = []string{labelSelector}
+		return nil
+	}
+
+	return json.Unmarshal(raw.Labels, &f.Labels)
+}
+
+func (f ResourceFilter) validate() error {
+	for _, label := range f.Labels {
+		_, err := labels.Parse(label)
+		if err != nil {
+			return fmt.Errorf("Parsing label selector '%s': %w", label, err)
+		}
+	}
+	return nil
+}
+
 func (f ResourceFilter) Apply(resources []Resource) []Resource {
 	var result []Resource
 
@@ -40,6 +137,15 @@ func (f ResourceFilter) Apply(resources []Resource) []Resource {
 }
 
 func (f ResourceFilter) Matches(resource Resource) bool {
+	// Exclusions apply regardless of other filters
+	if len(f.ExcludedNamespaces) > 0 {
+		for _, ns := range f.ExcludedNamespaces {
+			if matcher.NewStringMatcher(ns).Matches(resource.Namespace()) {
+				return false
+			}
+		}
+	}
+
 	if f.BoolFilter != nil {
 		return f.BoolFilter.Matches(resource)
 	}
@@ -56,6 +162,53 @@ func (f ResourceFilter) Matches(resource Resource) bool {
 		}
 	}
 
+	if f.CreatedAtHours != nil {
+		if !f.CreatedAtHours.Matches(resource.CreatedAt()) {
+			return false
+		}
+	}
+
+	if f.Replicas != nil {
+		if !f.Replicas.Matches(resource) {
+			return false
+		}
+	}
+
+	if len(f.ContainerPorts) > 0 {
+		var matched bool
+		for _, port := range ContainerPorts(resource) {
+			for _, expectedPort := range f.ContainerPorts {
+				if port == int64(expectedPort) {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(f.CreatedByFieldManagers) > 0 {
+		creator := CreatedByFieldManager(resource)
+		var matched bool
+		for _, manager := range f.CreatedByFieldManagers {
+			if len(creator) > 0 && matcher.NewStringMatcher(manager).Matches(creator) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if f.CustomResources != nil {
+		if !f.CustomResources.Matches(resource) {
+			return false
+		}
+	}
+
 	if len(f.Kinds) > 0 {
 		var matched bool
 		for _, kind := range f.Kinds {
@@ -69,6 +222,44 @@ func (f ResourceFilter) Matches(resource Resource) bool {
 		}
 	}
 
+	if len(f.Groups) > 0 {
+		resGroup := resource.APIGroup()
+		if len(resGroup) == 0 {
+			resGroup = coreGroupName
+		}
+		var matched bool
+		for _, group := range f.Groups {
+			if matcher.NewStringMatcher(group).Matches(resGroup) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(f.KindGroups) > 0 {
+		resGroup := resource.APIGroup()
+		if len(resGroup) == 0 {
+			resGroup = coreGroupName
+		}
+		var matched bool
+		for _, kindGroup := range f.KindGroups {
+			kind, group, _ := strings.Cut(kindGroup, ".")
+			if len(group) == 0 {
+				group = coreGroupName
+			}
+			if matcher.NewStringMatcher(kind).Matches(resource.Kind()) && matcher.NewStringMatcher(group).Matches(resGroup) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
 	if len(f.Namespaces) > 0 {
 		var matched bool
 		for _, ns := range f.Namespaces {
@@ -112,6 +303,64 @@ func (f ResourceFilter) Matches(resource Resource) bool {
 		}
 	}
 
+	if len(f.ChangeGroups) > 0 {
+		var matched bool
+		for key, val := range resource.Annotations() {
+			if key != changeGroupAnnKey && !strings.HasPrefix(key, changeGroupAnnPrefixKey) {
+				continue
+			}
+			for _, group := range f.ChangeGroups {
+				if val == group {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if f.HasFinalizers && len(resource.Finalizers()) == 0 {
+		return false
+	}
+
+	if f.Orphaned {
+		if _, found := resource.Labels()[appLabelKey]; found {
+			return false
+		}
+	}
+
+	if len(f.StatusReasons) > 0 {
+		var matched bool
+		for _, reason := range StatusReasons(resource) {
+			for _, expectedReason := range f.StatusReasons {
+				if reason == expectedReason {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(f.Finalizers) > 0 {
+		var matched bool
+		for _, finalizer := range resource.Finalizers() {
+			for _, expectedFinalizer := range f.Finalizers {
+				if finalizer == expectedFinalizer {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
 	if len(f.KindNames) > 0 {
 		key := resource.Kind() + "/" + resource.Name()
 		var matched bool
@@ -157,6 +406,111 @@ func (f ResourceFilter) Matches(resource Resource) bool {
 	return true
 }
 
+// HourWindow is a daily window of hours (in UTC) starting at
+// Start (inclusive) and ending at End (exclusive). Window wraps
+// around midnight when Start is greater than End (e.g. 22-2)
+type HourWindow struct {
+	Start int
+	End   int
+}
+
+// NewHourWindowFromString parses window specified as "<start>-<end>" (e.g. "1-3")
+func NewHourWindowFromString(data string) (*HourWindow, error) {
+	errMsg := "Expected hour window to be in format '<start>-<end>' with hours between 0 and 24 (example: 1-3, 22-2), but was '%s'"
+
+	pieces := strings.Split(data, "-")
+	if len(pieces) != 2 {
+		return nil, fmt.Errorf(errMsg, data)
+	}
+
+	start, err := strconv.Atoi(pieces[0])
+	if err != nil || start < 0 || start > 23 {
+		return nil, fmt.Errorf(errMsg, data)
+	}
+
+	end, err := strconv.Atoi(pieces[1])
+	if err != nil || end < 0 || end > 24 || start == end {
+		return nil, fmt.Errorf(errMsg, data)
+	}
+
+	return &HourWindow{Start: start, End: end}, nil
+}
+
+func (w HourWindow) Matches(t time.Time) bool {
+	hour := t.UTC().Hour()
+	if w.Start < w.End {
+		return hour >= w.Start && hour < w.End
+	}
+	return hour >= w.Start || hour < w.End
+}
+
+// ReplicasComparison compares replica count (spec.replicas)
+// of workloads against Count using Op (e.g. ">", "=")
+type ReplicasComparison struct {
+	Op    string
+	Count int64
+}
+
+var (
+	replicasComparisonOps = []string{">=", "<=", ">", "<", "="} // longer ops first for parsing
+	replicasWorkloadKinds = map[string]struct{}{"Deployment": {}, "StatefulSet": {}, "ReplicaSet": {}}
+)
+
+// NewReplicasComparisonFromString parses comparison specified as "<op><n>" (e.g. ">0")
+func NewReplicasComparisonFromString(data string) (*ReplicasComparison, error) {
+	errMsg := "Expected replicas comparison to be in format '<op><n>' with op one of >, >=, <, <=, = (example: >0, =0, <3), but was '%s'"
+
+	for _, op := range replicasComparisonOps {
+		if !strings.HasPrefix(data, op) {
+			continue
+		}
+		count, err := strconv.ParseInt(strings.TrimPrefix(data, op), 10, 32)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf(errMsg, data)
+		}
+		return &ReplicasComparison{Op: op, Count: count}, nil
+	}
+
+	return nil, fmt.Errorf(errMsg, data)
+}
+
+// Matches returns false for resources that are not workloads
+func (c ReplicasComparison) Matches(res Resource) bool {
+	if _, found := replicasWorkloadKinds[res.Kind()]; !found {
+		return false
+	}
+
+	replicas := c.replicas(res)
+
+	switch c.Op {
+	case ">":
+		return replicas > c.Count
+	case ">=":
+		return replicas >= c.Count
+	case "<":
+		return replicas < c.Count
+	case "<=":
+		return replicas <= c.Count
+	case "=":
+		return replicas == c.Count
+	default:
+		return false
+	}
+}
+
+func (ReplicasComparison) replicas(res Resource) int64 {
+	spec, _ := res.UnstructuredObject()["spec"].(map[string]interface{})
+
+	switch typedVal := spec["replicas"].(type) {
+	case int64:
+		return typedVal
+	case float64:
+		return int64(typedVal)
+	default:
+		return 1 // defaulted by API server when not specified
+	}
+}
+
 type BoolFilter struct {
 	And      []BoolFilter
 	Or       []BoolFilter
@@ -172,9 +526,36 @@ func NewBoolFilterFromString(data string) (*BoolFilter, error) {
 		return nil, err
 	}
 
+	err = filter.validate()
+	if err != nil {
+		return nil, err
+	}
+
 	return &filter, nil
 }
 
+func (m BoolFilter) validate() error {
+	for _, m2 := range append(append([]BoolFilter{}, m.And...), m.Or...) {
+		err := m2.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.Not != nil {
+		err := m.Not.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.Resource != nil {
+		return m.Resource.validate()
+	}
+
+	return nil
+}
+
 func (m BoolFilter) Matches(res Resource) bool {
 	if len(m.And) > 0 {
 		for _, m2 := range m.And {
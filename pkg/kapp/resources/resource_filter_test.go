@@ -0,0 +1,403 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceFilterExcludeSystemNamespaces(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"kube-system"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"kube-public"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"kube-node-lease"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"app"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`)),
+	}
+
+	filter := ctlres.ResourceFilter{}
+	filter.ExcludeSystemNamespaces()
+	filter.ExcludeSystemNamespaces() // should not add duplicates
+
+	require.Len(t, filter.ExcludedNamespaces, 3)
+
+	result := filter.Apply(resources)
+	require.Len(t, result, 2)
+	require.Equal(t, "app", result[0].Namespace())
+	require.Equal(t, "", result[1].Namespace())
+
+	// composes with namespace filter
+	filter.Namespaces = []string{"kube-system", "app"}
+
+	result = filter.Apply(resources)
+	require.Len(t, result, 1)
+	require.Equal(t, "app", result[0].Namespace())
+	require.Equal(t, "ConfigMap", result[0].Kind())
+}
+
+func TestResourceFilterChangeGroups(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"db","annotations":{"kapp.k14s.io/change-group":"apps.big.co/db"}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","annotations":{"kapp.k14s.io/change-group.app":"apps.big.co/app"}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"other","annotations":{"other":"apps.big.co/db"}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"none"}}`)),
+	}
+
+	result := ctlres.ResourceFilter{ChangeGroups: []string{"apps.big.co/db"}}.Apply(resources)
+	require.Len(t, result, 1)
+	require.Equal(t, "db", result[0].Name())
+
+	result = ctlres.ResourceFilter{ChangeGroups: []string{"apps.big.co/app", "apps.big.co/db"}}.Apply(resources)
+	require.Len(t, result, 2)
+	require.Equal(t, "db", result[0].Name())
+	require.Equal(t, "app", result[1].Name())
+}
+
+func TestBoolFilterLabels(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"prod","labels":{"env":"prod"}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"staging","labels":{"env":"staging"}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"dev","labels":{"env":"dev"}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"none"}}`)),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		filter   string
+		expected []string
+	}{
+		{
+			name:     "in",
+			filter:   `{"resource":{"labels":"env in (prod,staging)"}}`,
+			expected: []string{"prod", "staging"},
+		},
+		{
+			name:     "notin",
+			filter:   `{"resource":{"labels":"env notin (prod,staging)"}}`,
+			expected: []string{"dev", "none"},
+		},
+		{
+			name:     "exists",
+			filter:   `{"resource":{"labels":"env"}}`,
+			expected: []string{"prod", "staging", "dev"},
+		},
+		{
+			name:     "does not exist",
+			filter:   `{"resource":{"labels":"!env"}}`,
+			expected: []string{"none"},
+		},
+		{
+			name:     "list of selectors matches any",
+			filter:   `{"resource":{"labels":["env=prod","env=dev"]}}`,
+			expected: []string{"prod", "dev"},
+		},
+		{
+			name:     "combined with other matchers",
+			filter:   `{"and":[{"resource":{"labels":"env in (prod,staging)"}},{"not":{"resource":{"names":["staging"]}}}]}`,
+			expected: []string{"prod"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := ctlres.NewBoolFilterFromString(tc.filter)
+			require.NoError(t, err)
+
+			var names []string
+			for _, res := range resources {
+				if filter.Matches(res) {
+					names = append(names, res.Name())
+				}
+			}
+			require.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestBoolFilterInvalidLabels(t *testing.T) {
+	_, err := ctlres.NewBoolFilterFromString(`{"or":[{"resource":{"labels":"env in prod"}}]}`)
+	require.ErrorContains(t, err, "Parsing label selector 'env in prod'")
+}
+
+func TestResourceFilterFinalizers(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"none"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"empty","finalizers":[]}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"PersistentVolumeClaim","metadata":{"name":"pvc","finalizers":["kubernetes.io/pvc-protection"]}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"custom","finalizers":["example.com/cleanup","example.com/other"]}}`)),
+	}
+
+	names := func(rs []ctlres.Resource) []string {
+		var result []string
+		for _, res := range rs {
+			result = append(result, res.Name())
+		}
+		return result
+	}
+
+	t.Run("any finalizer", func(t *testing.T) {
+		filter := ctlres.ResourceFilter{HasFinalizers: true}
+		require.Equal(t, []string{"pvc", "custom"}, names(filter.Apply(resources)))
+	})
+
+	t.Run("specific finalizer", func(t *testing.T) {
+		filter := ctlres.ResourceFilter{Finalizers: []string{"example.com/other"}}
+		require.Equal(t, []string{"custom"}, names(filter.Apply(resources)))
+	})
+
+	t.Run("any of specific finalizers", func(t *testing.T) {
+		filter := ctlres.ResourceFilter{Finalizers: []string{"example.com/cleanup", "kubernetes.io/pvc-protection"}}
+		require.Equal(t, []string{"pvc", "custom"}, names(filter.Apply(resources)))
+	})
+}
+
+func TestResourceFilterOrphaned(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"managed","labels":{"kapp.k14s.io/app":"1234"}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"manual","labels":{"x":"y"}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"no-labels"}}`)),
+	}
+
+	var names []string
+	for _, res := range (ctlres.ResourceFilter{Orphaned: true}).Apply(resources) {
+		names = append(names, res.Name())
+	}
+	require.Equal(t, []string{"manual", "no-labels"}, names)
+}
+
+func TestResourceFilterStatusReasons(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"crashing"},"status":{"containerStatuses":[{"name":"app","state":{"waiting":{"reason":"CrashLoopBackOff"}}}]}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"running"},"status":{"containerStatuses":[{"name":"app","state":{"running":{}}}]}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"evicted"},"status":{"reason":"Evicted"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"unschedulable"},"status":{"conditions":[` +
+			`{"type":"Initialized","reason":"PodCompleted","lastTransitionTime":"2024-01-01T00:00:00Z"},` +
+			`{"type":"PodScheduled","reason":"Unschedulable","lastTransitionTime":"2024-01-02T00:00:00Z"}]}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"deployment"},"status":{"conditions":[` +
+			`{"type":"Progressing","reason":"ProgressDeadlineExceeded","lastTransitionTime":"2024-01-02T00:00:00Z"},` +
+			`{"type":"Available","reason":"MinimumReplicasUnavailable","lastTransitionTime":"2024-01-01T00:00:00Z"}]}}`)),
+	}
+
+	for _, tc := range []struct {
+		reasons  []string
+		expected []string
+	}{
+		{reasons: []string{"CrashLoopBackOff"}, expected: []string{"crashing"}},
+		{reasons: []string{"Evicted", "Unschedulable"}, expected: []string{"evicted", "unschedulable"}},
+		{reasons: []string{"ProgressDeadlineExceeded"}, expected: []string{"deployment"}},
+		// only latest condition is considered
+		{reasons: []string{"PodCompleted", "MinimumReplicasUnavailable"}, expected: nil},
+	} {
+		var names []string
+		for _, res := range (ctlres.ResourceFilter{StatusReasons: tc.reasons}).Apply(resources) {
+			names = append(names, res.Name())
+		}
+		require.Equal(t, tc.expected, names, "reasons: %v", tc.reasons)
+	}
+}
+
+func TestResourceFilterGroups(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"networking.k8s.io/v1","kind":"Ingress","metadata":{"name":"ingress"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"networking.k8s.io/v1","kind":"NetworkPolicy","metadata":{"name":"netpol"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"deployment"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"service"}}`)),
+	}
+
+	for _, tc := range []struct {
+		groups   []string
+		expected []string
+	}{
+		{groups: []string{"networking.k8s.io"}, expected: []string{"ingress", "netpol"}},
+		{groups: []string{"networking.k8s.io", "apps"}, expected: []string{"ingress", "netpol", "deployment"}},
+		{groups: []string{"core"}, expected: []string{"service"}},
+	} {
+		var names []string
+		for _, res := range (ctlres.ResourceFilter{Groups: tc.groups}).Apply(resources) {
+			names = append(names, res.Name())
+		}
+		require.Equal(t, tc.expected, names, "groups: %v", tc.groups)
+	}
+}
+
+func TestResourceFilterKindGroups(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"networking.k8s.io/v1","kind":"Ingress","metadata":{"name":"networking-ingress"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"extensions/v1beta1","kind":"Ingress","metadata":{"name":"extensions-ingress"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"networking.k8s.io/v1","kind":"NetworkPolicy","metadata":{"name":"netpol"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"service"}}`)),
+	}
+
+	for _, tc := range []struct {
+		kindGroups []string
+		expected   []string
+	}{
+		{kindGroups: []string{"Ingress.networking.k8s.io"}, expected: []string{"networking-ingress"}},
+		{kindGroups: []string{"Ingress.extensions"}, expected: []string{"extensions-ingress"}},
+		{kindGroups: []string{"Ingress.extensions", "NetworkPolicy.networking.k8s.io"}, expected: []string{"extensions-ingress", "netpol"}},
+		{kindGroups: []string{"Service.core"}, expected: []string{"service"}},
+		{kindGroups: []string{"Service"}, expected: []string{"service"}},
+		{kindGroups: []string{"Ingress"}, expected: nil},
+	} {
+		var names []string
+		for _, res := range (ctlres.ResourceFilter{KindGroups: tc.kindGroups}).Apply(resources) {
+			names = append(names, res.Name())
+		}
+		require.Equal(t, tc.expected, names, "kind groups: %v", tc.kindGroups)
+	}
+}
+
+func TestResourceFilterCreatedAtHours(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"midnight","creationTimestamp":"2024-01-01T00:30:00Z"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"early","creationTimestamp":"2024-01-01T02:00:00Z"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"noon","creationTimestamp":"2024-01-01T12:59:59Z"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"late","creationTimestamp":"2024-01-01T23:15:00+01:00"}}`)),
+	}
+
+	for _, tc := range []struct {
+		window   string
+		expected []string
+	}{
+		{window: "2-3", expected: []string{"early"}},
+		{window: "0-12", expected: []string{"midnight", "early"}},
+		{window: "12-24", expected: []string{"noon", "late"}},
+		// wraps around midnight; "late" is at 22h in UTC
+		{window: "22-1", expected: []string{"midnight", "late"}},
+		{window: "3-12", expected: nil},
+	} {
+		window, err := ctlres.NewHourWindowFromString(tc.window)
+		require.NoError(t, err)
+
+		var names []string
+		for _, res := range (ctlres.ResourceFilter{CreatedAtHours: window}).Apply(resources) {
+			names = append(names, res.Name())
+		}
+		require.Equal(t, tc.expected, names, "window: %s", tc.window)
+	}
+
+	for _, window := range []string{"", "1", "1-2-3", "a-2", "2-2", "24-1", "1-25", "-1-2"} {
+		_, err := ctlres.NewHourWindowFromString(window)
+		require.Error(t, err, "window: %s", window)
+	}
+}
+
+func TestResourceFilterReplicas(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"zero"},"spec":{"replicas":0}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"default"},"spec":{}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apps/v1","kind":"StatefulSet","metadata":{"name":"three"},"spec":{"replicas":3}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apps/v1","kind":"ReplicaSet","metadata":{"name":"five"},"spec":{"replicas":5}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`)),
+	}
+
+	for _, tc := range []struct {
+		comparison string
+		expected   []string
+	}{
+		{comparison: "=0", expected: []string{"zero"}},
+		{comparison: ">0", expected: []string{"default", "three", "five"}},
+		{comparison: ">=3", expected: []string{"three", "five"}},
+		{comparison: "<3", expected: []string{"zero", "default"}},
+		{comparison: "<=3", expected: []string{"zero", "default", "three"}},
+		{comparison: ">5", expected: nil},
+	} {
+		comparison, err := ctlres.NewReplicasComparisonFromString(tc.comparison)
+		require.NoError(t, err)
+
+		var names []string
+		for _, res := range (ctlres.ResourceFilter{Replicas: comparison}).Apply(resources) {
+			names = append(names, res.Name())
+		}
+		require.Equal(t, tc.expected, names, "comparison: %s", tc.comparison)
+	}
+
+	for _, comparison := range []string{"", "0", ">", ">a", "=-1", "!=1", "=>1"} {
+		_, err := ctlres.NewReplicasComparisonFromString(comparison)
+		require.Error(t, err, "comparison: %s", comparison)
+	}
+}
+
+func TestResourceFilterContainerPorts(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"deploy"},"spec":{"template":{"spec":{"containers":[{"name":"app","ports":[{"containerPort":8080}]},{"name":"proxy","ports":[{"containerPort":80}]}]}}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod"},"spec":{"containers":[{"name":"app","ports":[{"containerPort":80,"protocol":"TCP"}]}]}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"batch/v1","kind":"CronJob","metadata":{"name":"cron"},"spec":{"jobTemplate":{"spec":{"template":{"spec":{"containers":[{"name":"app","ports":[{"containerPort":9090}]}]}}}}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"no-ports"},"spec":{"containers":[{"name":"app"}]}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"svc"},"spec":{"ports":[{"port":80,"targetPort":80}]}}`)),
+	}
+
+	for _, tc := range []struct {
+		ports    []int
+		expected []string
+	}{
+		{ports: []int{80}, expected: []string{"deploy", "pod"}},
+		{ports: []int{8080, 9090}, expected: []string{"deploy", "cron"}},
+		{ports: []int{443}, expected: nil},
+	} {
+		var names []string
+		for _, res := range (ctlres.ResourceFilter{ContainerPorts: tc.ports}).Apply(resources) {
+			names = append(names, res.Name())
+		}
+		require.Equal(t, tc.expected, names, "ports: %v", tc.ports)
+	}
+}
+
+func TestResourceFilterCreatedByFieldManagers(t *testing.T) {
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"created-by-controller","managedFields":[
+			{"manager":"kubectl-edit","operation":"Update","time":"2024-01-03T00:00:00Z"},
+			{"manager":"controller","operation":"Apply","time":"2024-01-01T00:00:00Z"},
+			{"manager":"kapp","operation":"Update","time":"2024-01-02T00:00:00Z"}]}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"created-by-kapp","managedFields":[
+			{"manager":"kapp","operation":"Update","time":"2024-01-01T00:00:00Z"},
+			{"manager":"controller","operation":"Apply","time":"2024-01-02T00:00:00Z"}]}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"no-managed-fields"}}`)),
+	}
+
+	require.Equal(t, "controller", ctlres.CreatedByFieldManager(resources[0]))
+	require.Equal(t, "kapp", ctlres.CreatedByFieldManager(resources[1]))
+	require.Equal(t, "", ctlres.CreatedByFieldManager(resources[2]))
+
+	for _, tc := range []struct {
+		managers []string
+		expected []string
+	}{
+		{managers: []string{"controller"}, expected: []string{"created-by-controller"}},
+		{managers: []string{"kapp", "kubectl-edit"}, expected: []string{"created-by-kapp"}},
+		{managers: []string{"kubectl-edit"}, expected: nil},
+	} {
+		var names []string
+		for _, res := range (ctlres.ResourceFilter{CreatedByFieldManagers: tc.managers}).Apply(resources) {
+			names = append(names, res.Name())
+		}
+		require.Equal(t, tc.expected, names, "managers: %v", tc.managers)
+	}
+}
+
+func TestResourceFilterCustomResources(t *testing.T) {
+	crds := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition","metadata":{"name":"widgets.example.com"},"spec":{"group":"example.com","names":{"kind":"Widget","plural":"widgets"}}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"not-a-crd"}}`)),
+	}
+
+	resources := []ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"widget"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"other.com/v1","kind":"Widget","metadata":{"name":"other-widget"}}`)),
+	}
+
+	var names []string
+	for _, res := range (ctlres.ResourceFilter{CustomResources: ctlres.NewCustomResourceGroupKinds(crds)}).Apply(resources) {
+		names = append(names, res.Name())
+	}
+	require.Equal(t, []string{"widget"}, names)
+
+	// Without CRDs no resource is custom
+	require.Empty(t, (ctlres.ResourceFilter{CustomResources: ctlres.NewCustomResourceGroupKinds(nil)}).Apply(resources))
+
+	// Filter is not applied when not set
+	require.Len(t, (ctlres.ResourceFilter{}).Apply(resources), 3)
+}
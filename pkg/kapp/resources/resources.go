@@ -68,6 +68,10 @@ type ResourcesImpl struct {
 type ResourcesImplOpts struct {
 	FallbackAllowedNamespaces        []string
 	ScopeToFallbackAllowedNamespaces bool
+	// FieldManager is recorded in managed fields of created,
+	// updated and patched resources. When empty API server
+	// derives it from the user agent.
+	FieldManager string
 }
 
 func NewResourcesImpl(resourceTypes ResourceTypes, coreClient kubernetes.Interface,
@@ -120,13 +124,11 @@ func (c *ResourcesImpl) All(resTypes []ResourceType, opts AllOpts) ([]Resource,
 			// If resource is cluster scoped or request is not scoped to fallback
 			// allowed namespaces manually, then scope list to all namespaces
 			if !c.opts.ScopeToFallbackAllowedNamespaces || !resType.Namespaced() {
-				err = util.Retry2(time.Second, 5*time.Second, c.isServerRescaleErr, func() error {
+				list, err = c.listAllPages(*opts.ListOpts, func(listOpts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
 					if resType.Namespaced() {
-						list, err = client.Namespace("").List(context.TODO(), *opts.ListOpts)
-					} else {
-						list, err = client.List(context.TODO(), *opts.ListOpts)
+						return client.Namespace("").List(context.TODO(), listOpts)
 					}
-					return err
+					return client.List(context.TODO(), listOpts)
 				})
 
 				if err == nil {
@@ -204,12 +206,8 @@ func (c *ResourcesImpl) allForNamespaces(client dynamic.NamespaceableResourceInt
 
 		go func() {
 			defer itemsDone.Done()
-			var resList *unstructured.UnstructuredList
-			var err error
-
-			err = util.Retry2(time.Second, 5*time.Second, c.isServerRescaleErr, func() error {
-				resList, err = client.Namespace(ns).List(context.TODO(), *listOpts)
-				return err
+			resList, err := c.listAllPages(*listOpts, func(listOpts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+				return client.Namespace(ns).List(context.TODO(), listOpts)
 			})
 			if err != nil {
 				if !errors.IsForbidden(err) {
@@ -241,6 +239,38 @@ func (c *ResourcesImpl) allForNamespaces(client dynamic.NamespaceableResourceInt
 	return list, nil
 }
 
+// listAllPages follows continue tokens until all pages are listed
+// (pagination is only used by the server when list options specify a limit)
+func (c *ResourcesImpl) listAllPages(listOpts metav1.ListOptions,
+	listFunc func(metav1.ListOptions) (*unstructured.UnstructuredList, error)) (*unstructured.UnstructuredList, error) {
+
+	var result *unstructured.UnstructuredList
+
+	for {
+		var page *unstructured.UnstructuredList
+		var err error
+
+		err = util.Retry2(time.Second, 5*time.Second, c.isServerRescaleErr, func() error {
+			page, err = listFunc(listOpts)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if result == nil {
+			result = page
+		} else {
+			result.Items = append(result.Items, page.Items...)
+		}
+
+		listOpts.Continue = page.GetContinue()
+		if len(listOpts.Continue) == 0 {
+			return result, nil
+		}
+	}
+}
+
 func (c *ResourcesImpl) Create(resource Resource) (Resource, error) {
 	if resourcesDebug {
 		t1 := time.Now().UTC()
@@ -258,7 +288,7 @@ func (c *ResourcesImpl) Create(resource Resource) (Resource, error) {
 	var createdUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		createdUn, err = resClient.Create(context.TODO(), resource.unstructuredPtr(), metav1.CreateOptions{})
+		createdUn, err = resClient.Create(context.TODO(), resource.unstructuredPtr(), metav1.CreateOptions{FieldManager: c.opts.FieldManager})
 		return err
 	})
 	if err != nil {
@@ -285,7 +315,7 @@ func (c *ResourcesImpl) Update(resource Resource) (Resource, error) {
 	var updatedUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		updatedUn, err = resClient.Update(context.TODO(), resource.unstructuredPtr(), metav1.UpdateOptions{})
+		updatedUn, err = resClient.Update(context.TODO(), resource.unstructuredPtr(), metav1.UpdateOptions{FieldManager: c.opts.FieldManager})
 		return err
 	})
 	if err != nil {
@@ -309,7 +339,7 @@ func (c *ResourcesImpl) Patch(resource Resource, patchType types.PatchType, data
 	var patchedUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		patchedUn, err = resClient.Patch(context.TODO(), resource.Name(), patchType, data, metav1.PatchOptions{})
+		patchedUn, err = resClient.Patch(context.TODO(), resource.Name(), patchType, data, metav1.PatchOptions{FieldManager: c.opts.FieldManager})
 		return err
 	})
 	if err != nil {
@@ -0,0 +1,94 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestResourcesAllListsAllPages(t *testing.T) {
+	var items []unstructured.Unstructured
+	for i := 0; i < 7; i++ {
+		item := unstructured.Unstructured{}
+		item.SetAPIVersion("v1")
+		item.SetKind("ConfigMap")
+		item.SetName(fmt.Sprintf("cm-%d", i))
+		items = append(items, item)
+	}
+
+	dynamicClient := &fakePaginatingDynamicClient{items: items}
+	resources := ctlres.NewResourcesImpl(nil, nil, dynamicClient, dynamicClient,
+		ctlres.ResourcesImplOpts{}, logger.NewNoopLogger())
+
+	resType := ctlres.ResourceType{
+		GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		APIResource:          metav1.APIResource{Namespaced: true},
+	}
+
+	rs, err := resources.All([]ctlres.ResourceType{resType}, ctlres.AllOpts{ListOpts: &metav1.ListOptions{Limit: 3}})
+	require.NoError(t, err)
+
+	var names []string
+	for _, res := range rs {
+		names = append(names, res.Name())
+	}
+	sort.Strings(names)
+
+	require.Equal(t, []string{"cm-0", "cm-1", "cm-2", "cm-3", "cm-4", "cm-5", "cm-6"}, names)
+	require.Equal(t, []string{"", "3", "6"}, dynamicClient.continueTokens)
+}
+
+// fakePaginatingDynamicClient only implements listing;
+// it pages through items based on list limit and continue token
+type fakePaginatingDynamicClient struct {
+	dynamic.Interface
+	dynamic.NamespaceableResourceInterface
+
+	items          []unstructured.Unstructured
+	continueTokens []string
+}
+
+func (c *fakePaginatingDynamicClient) Resource(schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return c
+}
+
+func (c *fakePaginatingDynamicClient) Namespace(string) dynamic.ResourceInterface {
+	return c
+}
+
+func (c *fakePaginatingDynamicClient) List(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	c.continueTokens = append(c.continueTokens, opts.Continue)
+
+	start := 0
+	if len(opts.Continue) > 0 {
+		var err error
+		start, err = strconv.Atoi(opts.Continue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	end := len(c.items)
+	if opts.Limit > 0 && start+int(opts.Limit) < end {
+		end = start + int(opts.Limit)
+	}
+
+	list := &unstructured.UnstructuredList{Items: c.items[start:end]}
+	if end < len(c.items) {
+		list.SetContinue(strconv.Itoa(end))
+	}
+	return list, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	podContainerStatusFields = []string{"initContainerStatuses", "containerStatuses", "ephemeralContainerStatuses"}
+	podContainerStateFields  = []string{"waiting", "terminated"}
+)
+
+// StatusReasons returns reasons found in resource status:
+// status.reason, reason of the most recently transitioned
+// condition and (for Pods) reasons of waiting or terminated containers
+func StatusReasons(res Resource) []string {
+	var reasons []string
+
+	add := func(val interface{}) {
+		if str, ok := val.(string); ok && len(str) > 0 {
+			reasons = append(reasons, str)
+		}
+	}
+
+	obj := res.UnstructuredObject()
+
+	reason, _, _ := unstructured.NestedString(obj, "status", "reason")
+	add(reason)
+
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	add(latestConditionReason(conditions))
+
+	if res.Kind() == "Pod" && res.APIGroup() == "" {
+		for _, field := range podContainerStatusFields {
+			statuses, _, _ := unstructured.NestedSlice(obj, "status", field)
+			for _, status := range statuses {
+				typedStatus, ok := status.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for _, stateField := range podContainerStateFields {
+					reason, _, _ := unstructured.NestedString(typedStatus, "state", stateField, "reason")
+					add(reason)
+				}
+			}
+		}
+	}
+
+	return reasons
+}
+
+func latestConditionReason(conditions []interface{}) string {
+	var latestReason string
+	var latestTime time.Time
+
+	for _, cond := range conditions {
+		typedCond, ok := cond.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var condTime time.Time
+		if str, ok := typedCond["lastTransitionTime"].(string); ok {
+			condTime, _ = time.Parse(time.RFC3339, str)
+		}
+		// Later conditions win ties (e.g. conditions without transition time)
+		if !condTime.Before(latestTime) {
+			latestTime = condTime
+			latestReason, _ = typedCond["reason"].(string)
+		}
+	}
+
+	return latestReason
+}
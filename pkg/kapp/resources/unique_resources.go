@@ -8,20 +8,31 @@ import (
 	"strings"
 )
 
-type UniqueResourceKey struct {
+// UniqueResourceKey returns a stable key identifying a resource
+// by its API group, version, kind, namespace and name
+// (e.g. apps/v1/Deployment/my-ns/my-app)
+func UniqueResourceKey(res Resource) string {
+	gv := res.GroupVersion()
+	return gv.Group + "/" + gv.Version + "/" + res.Kind() + "/" + res.Namespace() + "/" + res.Name()
+}
+
+// UnversionedResourceKey identifies a resource by its namespace,
+// API group, kind and name. It is used for matching resources
+// across versions (e.g. existing and new), hence API version is not included.
+type UnversionedResourceKey struct {
 	res        Resource
 	customName string
 }
 
-func NewUniqueResourceKey(res Resource) UniqueResourceKey {
-	return UniqueResourceKey{res, ""}
+func NewUniqueResourceKey(res Resource) UnversionedResourceKey {
+	return UnversionedResourceKey{res, ""}
 }
 
-func NewUniqueResourceKeyWithCustomName(res Resource, name string) UniqueResourceKey {
-	return UniqueResourceKey{res, name}
+func NewUniqueResourceKeyWithCustomName(res Resource, name string) UnversionedResourceKey {
+	return UnversionedResourceKey{res, name}
 }
 
-func (k UniqueResourceKey) String() string {
+func (k UnversionedResourceKey) String() string {
 	// version of the resource is not included since it will change over time
 	// TODO technically resource group can be changed (true uniqueness is via UID)
 	name := k.res.Name()
@@ -44,18 +55,29 @@ func (r UniqueResources) Resources() ([]Resource, error) {
 	var errs []error
 
 	uniqRs := map[string]Resource{}
+	uniqUnversionedRs := map[string]Resource{}
 
 	for _, res := range r.resources {
-		resKey := NewUniqueResourceKey(res).String()
+		resKey := UniqueResourceKey(res)
 		if uRes, found := uniqRs[resKey]; found {
 			// Check if duplicate resources are same
 			if !uRes.Equal(res) {
 				errs = append(errs, fmt.Errorf("Found resource '%s' multiple times with different content", res.Description()))
 			}
-		} else {
-			uniqRs[resKey] = res
-			result = append(result, res)
+			continue
 		}
+
+		// Same resource cannot be applied via multiple API versions
+		unversionedKey := NewUniqueResourceKey(res).String()
+		if uRes, found := uniqUnversionedRs[unversionedKey]; found {
+			errs = append(errs, fmt.Errorf("Found resource '%s' multiple times with different API versions (%s and %s)",
+				res.Description(), uRes.APIVersion(), res.APIVersion()))
+			continue
+		}
+
+		uniqRs[resKey] = res
+		uniqUnversionedRs[unversionedKey] = res
+		result = append(result, res)
 	}
 
 	return result, r.combinedErr(errs)
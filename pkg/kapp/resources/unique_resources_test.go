@@ -0,0 +1,89 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueResourceKey(t *testing.T) {
+	key := func(data string) string {
+		return ctlres.UniqueResourceKey(ctlres.MustNewResourceFromBytes([]byte(data)))
+	}
+
+	deploy := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"ns"}}`
+
+	require.Equal(t, "apps/v1/Deployment/ns/app", key(deploy))
+	require.Equal(t, key(deploy), key(deploy))
+	require.Equal(t, "/v1/Namespace//ns", key(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"ns"}}`))
+
+	// Content does not affect the key
+	require.Equal(t, key(deploy), key(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"ns","labels":{"x":"y"}},"spec":{"replicas":2}}`))
+
+	for _, different := range []string{
+		`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app2","namespace":"ns"}}`,
+		`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"ns2"}}`,
+		`{"apiVersion":"apps/v1","kind":"StatefulSet","metadata":{"name":"app","namespace":"ns"}}`,
+		`{"apiVersion":"apps/v1beta1","kind":"Deployment","metadata":{"name":"app","namespace":"ns"}}`,
+		`{"apiVersion":"extensions/v1beta1","kind":"Deployment","metadata":{"name":"app","namespace":"ns"}}`,
+	} {
+		require.NotEqual(t, key(deploy), key(different), "resource: %s", different)
+	}
+}
+
+func TestUnversionedResourceKey(t *testing.T) {
+	key := func(data string) string {
+		return ctlres.NewUniqueResourceKey(ctlres.MustNewResourceFromBytes([]byte(data))).String()
+	}
+
+	deploy := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"ns"}}`
+
+	require.Equal(t, "ns/apps/Deployment/app", key(deploy))
+	require.Equal(t, key(deploy), key(deploy))
+
+	// Content and API version do not affect the key
+	require.Equal(t, key(deploy), key(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"ns","labels":{"x":"y"}},"spec":{"replicas":2}}`))
+	require.Equal(t, key(deploy), key(`{"apiVersion":"apps/v1beta1","kind":"Deployment","metadata":{"name":"app","namespace":"ns"}}`))
+
+	for _, different := range []string{
+		`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app2","namespace":"ns"}}`,
+		`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"ns2"}}`,
+		`{"apiVersion":"apps/v1","kind":"StatefulSet","metadata":{"name":"app","namespace":"ns"}}`,
+		`{"apiVersion":"extensions/v1beta1","kind":"Deployment","metadata":{"name":"app","namespace":"ns"}}`,
+	} {
+		require.NotEqual(t, key(deploy), key(different), "resource: %s", different)
+	}
+
+	res := ctlres.MustNewResourceFromBytes([]byte(deploy))
+	require.Equal(t, "ns/apps/Deployment/custom", ctlres.NewUniqueResourceKeyWithCustomName(res, "custom").String())
+}
+
+func TestUniqueResourcesDuplicates(t *testing.T) {
+	cm := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"ns"},"data":{"key":"val"}}`
+	cmChanged := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"ns"},"data":{"key":"other"}}`
+	secret := `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"cm","namespace":"ns"}}`
+
+	resources, err := ctlres.NewUniqueResources([]ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(cm)),
+		ctlres.MustNewResourceFromBytes([]byte(secret)),
+		ctlres.MustNewResourceFromBytes([]byte(cm)),
+	}).Resources()
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	_, err = ctlres.NewUniqueResources([]ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(cm)),
+		ctlres.MustNewResourceFromBytes([]byte(cmChanged)),
+	}).Resources()
+	require.ErrorContains(t, err, "Found resource 'configmap/cm (v1) namespace: ns' multiple times with different content")
+
+	_, err = ctlres.NewUniqueResources([]ctlres.Resource{
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"ns"}}`)),
+		ctlres.MustNewResourceFromBytes([]byte(`{"apiVersion":"apps/v1beta1","kind":"Deployment","metadata":{"name":"app","namespace":"ns"}}`)),
+	}).Resources()
+	require.ErrorContains(t, err, "Found resource 'deployment/app (apps/v1beta1) namespace: ns' multiple times with different API versions (apps/v1 and apps/v1beta1)")
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRDUpgradeSafetyOldFromCluster(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	testName := "crdupgradesafetyoldfromcluster"
+
+	crdTpl := `
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: memcacheds.__test-name__.example.com
+spec:
+  group: __test-name__.example.com
+  names:
+    kind: Memcached
+    listKind: MemcachedList
+    plural: memcacheds
+    singular: memcached
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        properties:
+          apiVersion:
+            type: string
+          kind:
+            type: string
+          metadata:
+            type: object
+          spec:
+            maxLength: __max-length__
+            type: string
+          status:
+            type: object
+        type: object
+    served: true
+    storage: true
+    subresources:
+      status: {}
+`
+
+	crd := func(maxLength string) string {
+		return strings.NewReplacer("__test-name__", testName, "__max-length__", maxLength).Replace(crdTpl)
+	}
+
+	appName := "crd-upgrade-safety-old-from-cluster"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", appName})
+	}
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy CRD", func() {
+		kapp.RunWithOpts([]string{"deploy", "-a", appName, "-f", "-"}, RunOpts{StdinReader: strings.NewReader(crd("10"))})
+	})
+
+	logger.Section("compare safe change against live CRD", func() {
+		out, err := kapp.RunWithOpts([]string{"tools", "crd-upgrade-safety", "--old-from-cluster", "--new", "-"},
+			RunOpts{NoNamespace: true, StdinReader: strings.NewReader(crd("20"))})
+		require.NoError(t, err)
+		require.Contains(t, out, "CustomResourceDefinition memcacheds."+testName+".example.com: safe")
+	})
+
+	logger.Section("compare unsafe change against live CRD", func() {
+		out, err := kapp.RunWithOpts([]string{"tools", "crd-upgrade-safety", "--old-from-cluster", "--new", "-"},
+			RunOpts{NoNamespace: true, AllowError: true, StdinReader: strings.NewReader(crd("5"))})
+		require.Error(t, err)
+		require.Contains(t, out, "CustomResourceDefinition memcacheds."+testName+".example.com: unsafe")
+		require.Contains(t, err.Error(), "maximum length constraint decreased")
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployApplyConcurrency(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	var yaml strings.Builder
+	for i := 0; i < 10; i++ {
+		yaml.WriteString(fmt.Sprintf(`
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-apply-concurrency-%d
+`, i))
+	}
+
+	name := "test-deploy-apply-concurrency"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy with invalid concurrency", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--apply-concurrency", "0"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml.String()), AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --apply-concurrency to be >= 1, but was 0")
+	})
+
+	logger.Section("deploy many resources one at a time", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--apply-concurrency", "1"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml.String())})
+
+		require.Contains(t, out, "applying complete [10/10 done]")
+
+		// Changes are applied one at a time, hence each is reported on its own
+		for i := 0; i < 10; i++ {
+			require.Equal(t, 1, strings.Count(out, fmt.Sprintf("create configmap/cm-apply-concurrency-%d ", i)))
+		}
+
+		cms := kubectl.Run([]string{"get", "configmaps", "-o", "name"})
+		for i := 0; i < 10; i++ {
+			require.Contains(t, cms, fmt.Sprintf("configmap/cm-apply-concurrency-%d\n", i))
+		}
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployApplyPriorityKinds(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: apply-priority-svc
+spec:
+  ports:
+  - port: 80
+  selector:
+    app: apply-priority
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: apply-priority-cm
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: apply-priority-secret
+`
+
+	name := "test-deploy-apply-priority-kinds"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy with priority kinds", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--apply-priority-kinds", "Secret,ConfigMap"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		var batches [][]string
+		for _, line := range strings.Split(out, "\n") {
+			switch {
+			case strings.Contains(line, "---- applying") && !strings.Contains(line, "applying complete"):
+				batches = append(batches, nil)
+			case strings.Contains(line, ": create ") && len(batches) > 0:
+				resource := strings.Fields(line[strings.Index(line, ": create ")+len(": create "):])[0]
+				batches[len(batches)-1] = append(batches[len(batches)-1], resource)
+			}
+		}
+
+		require.Equal(t, [][]string{
+			{"secret/apply-priority-secret"},
+			{"configmap/apply-priority-cm"},
+			{"service/apply-priority-svc"},
+		}, batches)
+	})
+}
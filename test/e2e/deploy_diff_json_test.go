@@ -0,0 +1,79 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployDiffJSON(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-diff-json
+data:
+  key: __value__
+`
+
+	name := "test-deploy-diff-json"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	outputFile, err := os.CreateTemp(os.TempDir(), "diff-json")
+	require.NoError(t, err)
+	defer os.Remove(outputFile.Name())
+
+	readChanges := func() []ctlcap.ChangeJSON {
+		outputBs, err := os.ReadFile(outputFile.Name())
+		require.NoError(t, err)
+
+		var changes []ctlcap.ChangeJSON
+		require.NoError(t, json.Unmarshal(outputBs, &changes))
+		return changes
+	}
+
+	logger.Section("deploy app with diff json", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-json", outputFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__value__", "value1"))})
+
+		changes := readChanges()
+		require.Len(t, changes, 1)
+		require.Equal(t, ctlcap.ClusterChangeApplyOpAdd, changes[0].Op)
+		require.Equal(t, ctlcap.ChangeGVKJSON{Group: "", Version: "v1", Kind: "ConfigMap"}, changes[0].GVK)
+		require.Equal(t, env.Namespace, changes[0].Namespace)
+		require.Equal(t, "cm-diff-json", changes[0].Name)
+		require.Contains(t, changes[0].Diff, ctldiff.FieldChange{
+			Op: ctldiff.FieldChangeOpAdd, Path: "/data", NewValue: map[string]interface{}{"key": "value1"}})
+	})
+
+	logger.Section("diff app update with diff json", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--diff-json", outputFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__value__", "value2"))})
+
+		changes := readChanges()
+		require.Len(t, changes, 1)
+		require.Equal(t, ctlcap.ClusterChangeApplyOpUpdate, changes[0].Op)
+		require.Equal(t, "cm-diff-json", changes[0].Name)
+		require.Equal(t, []ctldiff.FieldChange{
+			{Op: ctldiff.FieldChangeOpReplace, Path: "/data/key", OldValue: "value1", NewValue: "value2"},
+		}, changes[0].Diff)
+	})
+}
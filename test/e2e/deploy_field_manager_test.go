@@ -0,0 +1,55 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployFieldManager(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: field-manager-cm
+data:
+  key: __value__
+`
+
+	name := "test-deploy-field-manager"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	managers := func() []string {
+		out := kubectl.Run([]string{"get", "configmap", "field-manager-cm", "-o", "jsonpath={.metadata.managedFields[*].manager}"})
+		return strings.Fields(out)
+	}
+
+	logger.Section("deploy with custom field manager", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--field-manager", "custom-manager"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__value__", "initial"))})
+
+		require.Equal(t, []string{"custom-manager"}, managers())
+	})
+
+	logger.Section("update with another field manager", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--field-manager", "other-manager"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__value__", "updated"))})
+
+		require.Contains(t, managers(), "other-manager")
+	})
+}
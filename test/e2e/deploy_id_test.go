@@ -0,0 +1,59 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployID(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+`
+
+	name := "test-deploy-id"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy with deploy id", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--deploy-id", "run-1"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		for _, cmName := range []string{"cm1", "cm2"} {
+			out := kubectl.Run([]string{"get", "configmap", cmName, "-o", `jsonpath={.metadata.annotations.kapp\.k14s\.io/deploy-id}`})
+			require.Equal(t, "run-1", out)
+		}
+	})
+
+	logger.Section("deploy with deploy id and custom annotation", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--deploy-id", "run-2", "--deploy-id-annotation", "example.com/run"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		for _, cmName := range []string{"cm1", "cm2"} {
+			out := kubectl.Run([]string{"get", "configmap", cmName, "-o", `jsonpath={.metadata.annotations.example\.com/run}`})
+			require.Equal(t, "run-2", out)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployOutputApplied(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-output-applied
+data:
+  key: value
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc-output-applied
+spec:
+  ports:
+  - port: 80
+  selector:
+    app: output-applied
+`
+
+	name := "test-deploy-output-applied"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	outputFile, err := os.CreateTemp(os.TempDir(), "output-applied")
+	require.NoError(t, err)
+	defer os.Remove(outputFile.Name())
+
+	logger.Section("deploy app with applied output", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--output-applied", outputFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		outputBs, err := os.ReadFile(outputFile.Name())
+		require.NoError(t, err)
+
+		resources, err := ctlres.NewResourcesFromBytes(outputBs)
+		require.NoError(t, err)
+		require.Len(t, resources, 2, "Expected both deployed resources to be written")
+
+		resByName := map[string]ctlres.Resource{}
+		for _, res := range resources {
+			require.Equal(t, env.Namespace, res.Namespace())
+			require.NotEmpty(t, res.UID(), "Expected server assigned uid")
+			meta := res.UnstructuredObject()["metadata"].(map[string]interface{})
+			require.NotEmpty(t, meta["resourceVersion"], "Expected server assigned resourceVersion")
+			require.False(t, res.CreatedAt().IsZero(), "Expected server assigned creationTimestamp")
+			resByName[res.Name()] = res
+		}
+
+		require.Contains(t, resByName, "cm-output-applied")
+		require.Contains(t, resByName, "svc-output-applied")
+
+		spec := resByName["svc-output-applied"].UnstructuredObject()["spec"].(map[string]interface{})
+		require.NotEmpty(t, spec["clusterIP"], "Expected server defaulted clusterIP")
+		require.Equal(t, "None", spec["sessionAffinity"], "Expected server defaulted sessionAffinity")
+	})
+}
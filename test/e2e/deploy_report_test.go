@@ -0,0 +1,77 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployReport(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-report
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-report-no-wait
+  annotations:
+    kapp.k14s.io/disable-wait: ""
+`
+
+	name := "test-deploy-report"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	outputFile, err := os.CreateTemp(os.TempDir(), "report")
+	require.NoError(t, err)
+	defer os.Remove(outputFile.Name())
+
+	logger.Section("deploy app with report", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--report", outputFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		outputBs, err := os.ReadFile(outputFile.Name())
+		require.NoError(t, err)
+
+		var entries []ctlcap.ApplyReportEntry
+		require.NoError(t, json.Unmarshal(outputBs, &entries))
+		require.Len(t, entries, 2)
+
+		entriesByName := map[string]ctlcap.ApplyReportEntry{}
+		for _, entry := range entries {
+			entriesByName[entry.Name] = entry
+		}
+
+		entry := entriesByName["cm-report"]
+		require.Equal(t, ctlcap.ClusterChangeApplyOpAdd, entry.Op)
+		require.Equal(t, ctlcap.ChangeGVKJSON{Group: "", Version: "v1", Kind: "ConfigMap"}, entry.GVK)
+		require.Equal(t, env.Namespace, entry.Namespace)
+		require.Equal(t, ctlcap.ApplyReportStatusSucceeded, entry.Status)
+		require.Equal(t, ctlcap.ApplyReportStatusSucceeded, entry.WaitOutcome)
+		require.Empty(t, entry.ApplyError)
+		require.Greater(t, entry.DurationSeconds, float64(0))
+
+		entry = entriesByName["cm-report-no-wait"]
+		require.Equal(t, ctlcap.ApplyReportStatusSucceeded, entry.Status)
+		require.Equal(t, ctlcap.ApplyReportStatusSkipped, entry.WaitOutcome)
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployRequireLabel(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-labeled
+  labels:
+    team: platform
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-unlabeled
+`
+
+	name := "test-deploy-require-label"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy resource missing required label", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--require-label", "team"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Resource 'configmap/cm-unlabeled (v1) namespace: "+env.Namespace+"' is missing required label 'team'")
+		require.NotContains(t, err.Error(), "cm-labeled")
+
+		NewMissingClusterResource(t, "configmap", "cm-labeled", env.Namespace, Kubectl{t, env.Namespace, logger})
+	})
+}
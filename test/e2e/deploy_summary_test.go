@@ -0,0 +1,67 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploySummary(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-summary-1
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-summary-2
+`
+
+	name := "test-deploy-summary"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy initial app", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("deploy with summary", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--summary", "--json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		require.Len(t, resp.Tables, 2, "Expected changes and summary tables")
+
+		summaryTable := resp.Tables[1]
+		require.Equal(t, map[string]string{"op": "Op", "count": "Count", "resources": "Resources"}, summaryTable.Header)
+
+		expected := []map[string]string{
+			{"op": "create", "count": "1", "resources": "configmap/cm-summary-2 (v1) namespace: " + env.Namespace},
+			{"op": "update", "count": "0", "resources": ""},
+			{"op": "delete", "count": "1", "resources": "configmap/cm-summary-1 (v1) namespace: " + env.Namespace},
+			{"op": "exists", "count": "0", "resources": ""},
+			{"op": "noop", "count": "0", "resources": ""},
+		}
+		require.Equal(t, expected, summaryTable.Rows)
+	})
+}
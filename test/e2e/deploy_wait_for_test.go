@@ -0,0 +1,52 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployWaitFor(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: wait-for-pod
+spec:
+  containers:
+  - name: app
+    image: busybox
+    command: ["sh", "-c", "sleep 3600"]
+`
+
+	name := "test-deploy-wait-for"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy with invalid wait rule", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait-for", "Pod:Ready"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml), AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected wait rule 'Pod:Ready' to be in format '<Kind>[.<group>]:<ConditionType>=<Status>'")
+	})
+
+	logger.Section("deploy waiting for pod to become ready", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait-for", "Pod:Ready=True"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Contains(t, out, "Encountered successful condition Ready == True")
+	})
+}
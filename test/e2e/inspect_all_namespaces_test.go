@@ -0,0 +1,89 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectAllNamespaces(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	name := "test-inspect-all-namespaces"
+	nsAppName := "test-inspect-all-namespaces-ns"
+	otherNs := "kapp-test-inspect-all-namespaces"
+
+	nsYaml := `
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ` + otherNs + `
+`
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kapp.Run([]string{"delete", "-a", nsAppName})
+		RemoveClusterResource(t, "ns", otherNs, "", kubectl)
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy app and add resource in unrecorded namespace", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", nsAppName}, RunOpts{StdinReader: strings.NewReader(nsYaml)})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{StdinReader: strings.NewReader(yaml)})
+
+		appLabel := kubectl.Run([]string{"get", "cm", "config", "-o", `jsonpath={.metadata.labels.kapp\.k14s\.io/app}`})
+
+		otherYaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: ` + otherNs + `
+  labels:
+    kapp.k14s.io/app: "` + appLabel + `"
+`
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(otherYaml)})
+	})
+
+	inspectNamespaces := func(args []string) []string {
+		out, _ := kapp.RunWithOpts(append([]string{"inspect", "-a", name, "--json",
+			"--dangerous-scope-to-fallback-allowed-namespaces"}, args...), RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		var namespaces []string
+		for _, row := range resp.Tables[0].Rows {
+			require.Equal(t, "config", row["name"])
+			namespaces = append(namespaces, row["namespace"])
+		}
+		return namespaces
+	}
+
+	logger.Section("inspect app in recorded namespaces", func() {
+		require.ElementsMatch(t, []string{env.Namespace}, inspectNamespaces(nil))
+	})
+
+	logger.Section("inspect app in all namespaces", func() {
+		require.ElementsMatch(t, []string{env.Namespace, otherNs}, inspectNamespaces([]string{"--all-namespaces"}))
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectAppChange(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inspect-app-change-cm1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inspect-app-change-cm2
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inspect-app-change-cm3
+`
+
+	name := "test-inspect-app-change"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy app twice with different resources", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-changes-record-resources"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+	})
+
+	resourceNames := func(out string) []string {
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		var names []string
+		for _, row := range resp.Tables[0].Rows {
+			names = append(names, row["name"])
+		}
+		return names
+	}
+
+	logger.Section("inspect first app change", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 2)
+
+		// App changes are listed newest first
+		firstChange := resp.Tables[0].Rows[1]["name"]
+
+		out, _ = kapp.RunWithOpts([]string{"inspect", "-a", name, "--app-change", firstChange, "--json"}, RunOpts{})
+		require.Equal(t, []string{"inspect-app-change-cm1", "inspect-app-change-cm2"}, resourceNames(out))
+	})
+
+	logger.Section("inspect app change without recorded resources", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		lastChange := resp.Tables[0].Rows[0]["name"]
+
+		_, err := kapp.RunWithOpts([]string{"inspect", "-a", name, "--app-change", lastChange}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not have recorded resources")
+	})
+
+	logger.Section("inspect live resources", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--json"}, RunOpts{})
+		require.Equal(t, []string{"inspect-app-change-cm3"}, resourceNames(out))
+	})
+
+	logger.Section("inspect unknown app change", func() {
+		_, err := kapp.RunWithOpts([]string{"inspect", "-a", name, "--app-change", "unknown"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected to find app change 'unknown' for app 'test-inspect-app-change'")
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectCompareNamespace(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	name := "test-inspect-compare-namespace"
+	nsAppName := "test-inspect-compare-namespace-ns"
+	comparedNs := "kapp-test-inspect-compare-namespace"
+
+	nsYaml := `
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ` + comparedNs + `
+`
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key1: val1
+  key2: __val2__
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+stringData:
+  key1: val1
+`
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name, "-n", env.Namespace})
+		kapp.Run([]string{"delete", "-a", name, "-n", comparedNs})
+		kapp.Run([]string{"delete", "-a", nsAppName})
+		RemoveClusterResource(t, "ns", comparedNs, "", kubectl)
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy same app into two namespaces with one field difference", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", nsAppName}, RunOpts{StdinReader: strings.NewReader(nsYaml)})
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "-n", env.Namespace},
+			RunOpts{NoNamespace: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__val2__", "val2"))})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "-n", comparedNs},
+			RunOpts{NoNamespace: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__val2__", "val2-changed"))})
+	})
+
+	logger.Section("inspect app comparing with other namespace", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "-n", env.Namespace, "--compare-namespace", comparedNs},
+			RunOpts{NoNamespace: true})
+
+		require.Contains(t, out, "@@ update configmap/config (v1) namespace: "+env.Namespace+" @@")
+		require.Contains(t, out, "-   key2: val2\n")
+		require.Contains(t, out, "+   key2: val2-changed\n")
+		require.NotContains(t, out, "secret/secret", "Expected identical resources to not show up in diff")
+		require.Contains(t, out, "Op:      0 create, 0 delete, 1 update, 0 noop, 0 exists")
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectErrorsOnly(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: healthy-cm
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: failing-pod
+spec:
+  restartPolicy: Never
+  containers:
+  - name: fail
+    image: busybox
+    command: ["sh", "-c", "exit 1"]
+`
+
+	name := "test-inspect-errors-only"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy failing and healthy resources", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+		require.Error(t, err)
+	})
+
+	logger.Section("inspect with errors only shows only failing resource", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--errors-only", "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		var names []string
+		for _, row := range resp.Tables[0].Rows {
+			names = append(names, row["name"])
+		}
+		require.Equal(t, []string{"failing-pod"}, names)
+	})
+}
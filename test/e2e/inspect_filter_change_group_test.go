@@ -0,0 +1,70 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectFilterChangeGroup(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: db-config
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/db"
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/app"
+    kapp.k14s.io/change-rule: "upsert after upserting apps.big.co/db"
+data:
+  key: value
+`
+
+	name := "test-inspect-filter-change-group"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy resources with distinct change groups", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("inspect resources in a single change group", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--filter-change-group", "apps.big.co/db", "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		expected := []map[string]string{{
+			"age":             "<replaced>",
+			"kind":            "ConfigMap",
+			"name":            "db-config",
+			"namespace":       "kapp-test",
+			"owner":           "kapp",
+			"reconcile_info":  "",
+			"reconcile_state": "ok",
+		}}
+
+		require.Exactlyf(t, expected, replaceAge(resp.Tables[0].Rows), "Expected to see only resources in change group")
+	})
+}
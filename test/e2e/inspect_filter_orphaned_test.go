@@ -0,0 +1,75 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectFilterOrphaned(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	managedYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-managed
+  labels:
+    filter-orphaned-test: "true"
+`
+
+	manualYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-manual
+  labels:
+    filter-orphaned-test: "true"
+`
+
+	name := "test-inspect-filter-orphaned"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kubectl.RunWithOpts([]string{"delete", "configmap", "cm-manual", "--ignore-not-found"}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy managed resource and create manual resource", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(managedYAML)})
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(manualYAML)})
+	})
+
+	names := func(out string) []string {
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		var result []string
+		for _, row := range resp.Tables[0].Rows {
+			result = append(result, row["name"])
+		}
+		return result
+	}
+
+	logger.Section("inspect by label shows both resources", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", "label:filter-orphaned-test=true",
+			"--filter-ns", env.Namespace, "--json"}, RunOpts{})
+		require.Equal(t, []string{"cm-managed", "cm-manual"}, names(out))
+	})
+
+	logger.Section("inspect by label with orphaned filter shows only manual resource", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", "label:filter-orphaned-test=true",
+			"--filter-ns", env.Namespace, "--filter-orphaned", "--json"}, RunOpts{})
+		require.Equal(t, []string{"cm-manual"}, names(out))
+	})
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectLimit(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-b
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-c
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-d
+`
+
+	name := "test-inspect-limit"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+	})
+
+	logger.Section("inspect with limit shows first resources and footer", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--limit", "2", "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		var names []string
+		for _, row := range resp.Tables[0].Rows {
+			names = append(names, row["name"])
+		}
+		require.Equal(t, []string{"cm-a", "cm-b"}, names)
+		require.Contains(t, resp.Tables[0].Notes, "(showing 2 of 4)")
+	})
+
+	logger.Section("inspect with limit larger than resources shows all resources", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--limit", "10", "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 4)
+		require.NotContains(t, resp.Tables[0].Notes, "(showing 10 of 4)")
+	})
+}
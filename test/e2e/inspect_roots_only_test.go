@@ -0,0 +1,84 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectRootsOnly(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: simple-app
+spec:
+  selector:
+    matchLabels:
+      simple-app: ""
+  template:
+    metadata:
+      labels:
+        simple-app: ""
+    spec:
+      containers:
+      - name: simple-app
+        image: docker.io/dkalinin/k8s-simple-app@sha256:4c8b96d4fffdfae29258d94a22ae4ad1fe36139d47288b8960d9958d1e63a9d0
+        env:
+        - name: HELLO_MSG
+          value: stranger
+`
+
+	name := "test-inspect-roots-only"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy deployment", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("inspect shows owned resources by default", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		kinds := map[string]bool{}
+		for _, row := range resp.Tables[0].Rows {
+			kinds[row["kind"]] = true
+		}
+		require.True(t, kinds["ReplicaSet"], "Expected to see ReplicaSet")
+		require.True(t, kinds["Pod"], "Expected to see Pod")
+	})
+
+	logger.Section("inspect with roots only", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--roots-only", "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		expected := []map[string]string{{
+			"age":             "<replaced>",
+			"kind":            "Deployment",
+			"name":            "simple-app",
+			"namespace":       "kapp-test",
+			"owner":           "kapp",
+			"reconcile_info":  "",
+			"reconcile_state": "ok",
+		}}
+
+		require.Exactlyf(t, expected, replaceAge(resp.Tables[0].Rows), "Expected to see only top-level resources")
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectShowContainers(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: show-containers-cm
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: show-containers-pod
+spec:
+  containers:
+  - name: app
+    image: busybox
+    command: ["sh", "-c", "sleep 3600"]
+  - name: sidecar
+    image: busybox
+    command: ["sh", "-c", "sleep 3600"]
+`
+
+	name := "test-inspect-show-containers"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy multi-container pod", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+	})
+
+	logger.Section("inspect with containers shows row per pod container", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--show-containers", "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		type containerRow struct {
+			Name, Kind, Container, Ready, Restarts string
+		}
+
+		var rows []containerRow
+		for _, row := range resp.Tables[0].Rows {
+			rows = append(rows, containerRow{row["name"], row["kind"], row["container"], row["ready"], row["restarts"]})
+		}
+
+		require.Equal(t, []containerRow{
+			{"show-containers-cm", "ConfigMap", "", "", ""},
+			{"show-containers-pod", "Pod", "app", "true", "0"},
+			{"show-containers-pod", "Pod", "sidecar", "true", "0"},
+		}, rows)
+	})
+}
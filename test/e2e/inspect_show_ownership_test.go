@@ -0,0 +1,75 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectShowOwnership(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-with-original
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-without-original
+  annotations:
+    kapp.k14s.io/disable-original: ""
+`
+
+	name := "test-inspect-show-ownership"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy app", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("inspect without ownership does not show ownership column", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--json"}, RunOpts{})
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		for _, row := range resp.Tables[0].Rows {
+			require.NotContains(t, row, "ownership")
+		}
+	})
+
+	logger.Section("inspect with ownership shows kapp labels and annotations", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--show-ownership", "--json"}, RunOpts{})
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		ownershipByName := map[string]string{}
+		for _, row := range resp.Tables[0].Rows {
+			ownershipByName[row["name"]] = row["ownership"]
+		}
+
+		require.Len(t, ownershipByName, 2)
+
+		for _, ownership := range ownershipByName {
+			require.Contains(t, ownership, "label kapp.k14s.io/app=")
+			require.Contains(t, ownership, "label kapp.k14s.io/association=")
+			require.Contains(t, ownership, "annotation kapp.k14s.io/identity=v1;"+env.Namespace+"/")
+		}
+
+		require.NotContains(t, ownershipByName["cm-with-original"], "annotation kapp.k14s.io/disable-original")
+		require.Contains(t, ownershipByName["cm-without-original"], "annotation kapp.k14s.io/disable-original=")
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectShowProvenance(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-helm-managed
+  labels:
+    app.kubernetes.io/managed-by: Helm
+  annotations:
+    meta.helm.sh/release-name: my-release
+    meta.helm.sh/release-namespace: ` + env.Namespace + `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-kapp-managed
+`
+
+	name := "test-inspect-show-provenance"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy app", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("inspect without provenance does not show provenance columns", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--json"}, RunOpts{})
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		for _, row := range resp.Tables[0].Rows {
+			require.NotContains(t, row, "helm")
+			require.NotContains(t, row, "kapp")
+		}
+	})
+
+	logger.Section("inspect with provenance shows helm and kapp metadata side by side", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--show-provenance", "--json"}, RunOpts{})
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		helmByName := map[string]string{}
+		kappByName := map[string]string{}
+		for _, row := range resp.Tables[0].Rows {
+			helmByName[row["name"]] = row["helm"]
+			kappByName[row["name"]] = row["kapp"]
+		}
+
+		require.Len(t, helmByName, 2)
+
+		require.Contains(t, helmByName["cm-helm-managed"], "managed-by=Helm")
+		require.Contains(t, helmByName["cm-helm-managed"], "release="+env.Namespace+"/my-release")
+		require.Equal(t, "", helmByName["cm-kapp-managed"])
+
+		for _, val := range kappByName {
+			require.Contains(t, val, "app=")
+			require.Contains(t, val, "association=")
+		}
+	})
+}
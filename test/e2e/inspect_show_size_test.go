@@ -0,0 +1,66 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectShowSize(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: show-size-small
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: show-size-large
+data:
+  key: ` + strings.Repeat("x", 100000) + `
+`
+
+	name := "test-inspect-show-size"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy large config map", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+	})
+
+	logger.Section("inspect with size sorted by size", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--show-size", "--sort-by", "size", "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		rows := resp.Tables[0].Rows
+		require.Len(t, rows, 2)
+
+		require.Equal(t, "show-size-large", rows[0]["name"])
+		largeSize, err := strconv.Atoi(rows[0]["size"])
+		require.NoError(t, err)
+		require.Greater(t, largeSize, 100000)
+
+		require.Equal(t, "show-size-small", rows[1]["name"])
+		smallSize, err := strconv.Atoi(rows[1]["size"])
+		require.NoError(t, err)
+		require.Greater(t, smallSize, 0)
+		require.Less(t, smallSize, largeSize)
+	})
+}
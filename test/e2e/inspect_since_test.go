@@ -0,0 +1,77 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectSince(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	oldYaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: old
+data:
+  key: __val__
+`
+
+	newYaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: new
+`
+
+	name := "test-inspect-since"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	names := func(out string) []string {
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		var result []string
+		for _, row := range resp.Tables[0].Rows {
+			result = append(result, row["name"])
+		}
+		return result
+	}
+
+	logger.Section("deploy old resource and wait for it to age", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(oldYaml, "__val__", "val1"))})
+		time.Sleep(6 * time.Second)
+	})
+
+	logger.Section("add new resource and inspect recent changes", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(oldYaml, "__val__", "val1") + newYaml)})
+
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--since", "4s", "--json"}, RunOpts{})
+		require.Equal(t, []string{"new"}, names(out))
+	})
+
+	logger.Section("update old resource and inspect recent changes", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(oldYaml, "__val__", "val2") + newYaml)})
+
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--since", "4s", "--json"}, RunOpts{})
+		require.Equal(t, []string{"new", "old"}, names(out))
+	})
+}
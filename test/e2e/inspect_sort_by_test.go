@@ -0,0 +1,70 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectSortBy(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: z-created-first
+`
+
+	yaml2 := yaml1 + `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a-created-second
+`
+
+	name := "test-inspect-sort-by"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy config maps at different times", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		// Creation timestamps have second precision
+		time.Sleep(2 * time.Second)
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+	})
+
+	names := func(out string) []string {
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		var result []string
+		for _, row := range resp.Tables[0].Rows {
+			result = append(result, row["name"])
+		}
+		return result
+	}
+
+	logger.Section("inspect sorts by name by default", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--json"}, RunOpts{})
+		require.Equal(t, []string{"a-created-second", "z-created-first"}, names(out))
+	})
+
+	logger.Section("inspect sorts by creation timestamp", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", name, "--sort-by", ".metadata.creationTimestamp", "--json"}, RunOpts{})
+		require.Equal(t, []string{"z-created-first", "a-created-second"}, names(out))
+	})
+}
@@ -111,6 +111,6 @@ spec:
 		_, err := kapp.RunWithOpts([]string{"deploy", "--preflight=CRDUpgradeSafety", "-a", appName, "-f", "-"},
 			RunOpts{StdinReader: strings.NewReader(update), AllowError: true})
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "\"NoScopeChange\" validation failed: scope changed from \"Namespaced\" to \"Cluster\"")
+		require.Contains(t, err.Error(), "- scope changed from \"Namespaced\" to \"Cluster\" (NoScopeChange)")
 	})
 }
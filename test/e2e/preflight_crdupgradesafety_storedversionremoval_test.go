@@ -111,6 +111,6 @@ spec:
 		_, err := kapp.RunWithOpts([]string{"deploy", "--preflight=CRDUpgradeSafety", "-a", appName, "-f", "-"},
 			RunOpts{StdinReader: strings.NewReader(update), AllowError: true})
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "\"NoStoredVersionRemoved\" validation failed: stored version \"v1alpha1\" removed")
+		require.Contains(t, err.Error(), "- stored version \"v1alpha1\" removed (NoStoredVersionRemoved)")
 	})
 }
@@ -0,0 +1,92 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightDeprecatedAPIs(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	testName := "preflightdeprecatedapis"
+
+	crd := `
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.__test-name__.example.com
+spec:
+  group: __test-name__.example.com
+  names:
+    kind: Widget
+    listKind: WidgetList
+    plural: widgets
+    singular: widget
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true
+    served: true
+    storage: false
+    deprecated: true
+  - name: v1
+    schema:
+      openAPIV3Schema:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true
+    served: true
+    storage: true
+`
+
+	cr := `
+---
+apiVersion: __test-name__.example.com/__version__
+kind: Widget
+metadata:
+  name: widget
+`
+
+	crd = strings.ReplaceAll(crd, "__test-name__", testName)
+	cr = strings.ReplaceAll(cr, "__test-name__", testName)
+
+	crdAppName := "preflight-deprecated-apis-crd-app"
+	appName := "preflight-deprecated-apis-app"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", appName})
+		kapp.Run([]string{"delete", "-a", crdAppName})
+	}
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy CRD with deprecated version", func() {
+		kapp.RunWithOpts([]string{"deploy", "-a", crdAppName, "-f", "-"}, RunOpts{StdinReader: strings.NewReader(crd)})
+	})
+
+	logger.Section("deploy resource using deprecated version, preflight check enabled, should warn and deploy", func() {
+		out, err := kapp.RunWithOpts([]string{"deploy", "--preflight=DeprecatedAPIs", "-a", appName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(cr, "__version__", "v1alpha1"))})
+		require.NoError(t, err)
+		require.Contains(t, out, "Warning: widget/widget ("+testName+".example.com/v1alpha1) namespace: "+env.Namespace+": "+
+			testName+".example.com/v1alpha1 Widget is deprecated; use "+testName+".example.com/v1 Widget")
+		require.Contains(t, out, "Succeeded")
+	})
+
+	logger.Section("deploy resource using non-deprecated version, preflight check enabled, should not warn", func() {
+		out, err := kapp.RunWithOpts([]string{"deploy", "--preflight=DeprecatedAPIs", "-a", appName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(cr, "__version__", "v1"))})
+		require.NoError(t, err)
+		require.NotContains(t, out, "Warning: widget/widget")
+	})
+}
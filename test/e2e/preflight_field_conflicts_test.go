@@ -0,0 +1,62 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightFieldConflicts(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	appName := "preflight-field-conflicts-app"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", appName})
+	}
+	cleanUp()
+	defer cleanUp()
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: preflight-field-conflicts
+data:
+  key: __value__
+  other: value
+`
+
+	logger.Section("deploy app initially", func() {
+		kapp.RunWithOpts([]string{"deploy", "--preflight=FieldConflicts", "-a", appName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__value__", "initial"))})
+	})
+
+	logger.Section("change field using another field manager", func() {
+		kubectl.Run([]string{"patch", "configmap", "preflight-field-conflicts", "--type", "merge", "-p", `{"data":{"key":"patched"}}`})
+	})
+
+	logger.Section("deploy app without changing field owned by another manager, preflight check enabled, should succeed", func() {
+		kapp.RunWithOpts([]string{"deploy", "--preflight=FieldConflicts", "-a", appName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__value__", "patched"))})
+	})
+
+	logger.Section("deploy app changing field owned by another manager, preflight check enabled, should error", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "--preflight=FieldConflicts", "-a", appName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__value__", "updated")), AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "running preflight check \"FieldConflicts\": validation for field ownership conflicts failed")
+		require.Contains(t, err.Error(), "field '.data.key' is owned by field manager 'kubectl-patch'")
+
+		out := kubectl.Run([]string{"get", "configmap", "preflight-field-conflicts", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "patched", out)
+	})
+}
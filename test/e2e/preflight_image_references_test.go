@@ -0,0 +1,63 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightImageReferences(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	appName := "preflight-image-references-app"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", appName})
+	}
+	cleanUp()
+	defer cleanUp()
+
+	malformed := `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: preflight-image-references
+spec:
+  selector:
+    matchLabels:
+      app: preflight-image-references
+  template:
+    metadata:
+      labels:
+        app: preflight-image-references
+    spec:
+      containers:
+      - name: app
+        image: nginx::latest
+`
+
+	logger.Section("deploy app with malformed image reference, preflight check enabled, should error", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "--preflight=ImageReferences", "-a", appName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(malformed), AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "running preflight check \"ImageReferences\": validation for image references failed")
+		require.Contains(t, err.Error(), "Expected image reference 'nginx::latest' to be well-formed")
+		NewMissingClusterResource(t, "deployment", "preflight-image-references", env.Namespace, kubectl)
+	})
+
+	wellFormed := strings.ReplaceAll(malformed, "nginx::latest",
+		"docker.io/dkalinin/k8s-simple-app@sha256:4c8b96d4fffdfae29258d94a22ae4ad1fe36139d47288b8960d9958d1e63a9d0")
+
+	logger.Section("deploy app with well-formed image reference, preflight check enabled, should succeed", func() {
+		kapp.RunWithOpts([]string{"deploy", "--preflight=ImageReferences", "-a", appName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(wellFormed)})
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightPermissionValidationSkipAnnotation(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	testName := "preflight-permission-validation-skip"
+
+	base := `
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: __test-name__
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: scoped-sa
+  namespace: __ns__
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: scoped-sa
+  namespace: __ns__
+  annotations:
+    kubernetes.io/service-account.name: scoped-sa
+type: kubernetes.io/service-account-token
+---
+kind: ClusterRole
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: __test-name__
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["*"]
+- apiGroups: [""]
+  resources: ["namespaces"]
+  verbs: ["list"]
+- apiGroups: [""]
+  resources: ["secrets"]
+  verbs: ["create", "get", "list"]
+---
+kind: ClusterRoleBinding
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: __test-name__
+subjects:
+- kind: ServiceAccount
+  name: scoped-sa
+  namespace: __ns__
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: __test-name__
+`
+
+	base = strings.ReplaceAll(base, "__test-name__", testName)
+	base = strings.ReplaceAll(base, "__ns__", env.Namespace)
+	baseName := "preflight-permission-validation-skip-base-app"
+	appName := "preflight-permission-validation-skip-app"
+	scopedContext := "scoped-context"
+	scopedUser := "scoped-user"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", appName})
+		kapp.Run([]string{"delete", "-a", baseName})
+		RemoveClusterResource(t, "ns", testName, "", kubectl)
+	}
+	cleanUp()
+	defer cleanUp()
+
+	kapp.RunWithOpts([]string{"deploy", "-a", baseName, "-f", "-"}, RunOpts{StdinReader: strings.NewReader(base)})
+	cleanUpContext := ScopedContext(t, kubectl, testName, scopedContext, scopedUser)
+	defer cleanUpContext()
+
+	// Scoped user is only allowed to create secrets, not update them
+	// (recording of original resource is disabled since it requires an update)
+	secretResource := `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: __test-name__
+  namespace: __test-name__
+__annotations__
+`
+	secretResource = strings.ReplaceAll(secretResource, "__test-name__", testName)
+
+	logger.Section("attempt to deploy app with a Secret and missing permissions to update Secrets", func() {
+		yaml := strings.ReplaceAll(secretResource, "__annotations__", "")
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "--preflight=PermissionValidation", "-a", appName, "-f", "-", fmt.Sprintf("--kubeconfig-context=%s", scopedContext)},
+			RunOpts{StdinReader: strings.NewReader(yaml), AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "running preflight check \"PermissionValidation\": not permitted to \"update\" /v1, Resource=secrets")
+		NewMissingClusterResource(t, "secret", testName, testName, kubectl)
+	})
+
+	logger.Section("deploy app with a Secret annotated to skip permission check", func() {
+		yaml := strings.ReplaceAll(secretResource, "__annotations__", `  annotations:
+    kapp.k14s.io/disable-original: ""
+    kapp.k14s.io/skip-permission-check: "true"`)
+
+		kapp.RunWithOpts([]string{"deploy", "--preflight=PermissionValidation", "-a", appName, "-f", "-", fmt.Sprintf("--kubeconfig-context=%s", scopedContext)},
+			RunOpts{StdinReader: strings.NewReader(yaml)})
+
+		NewPresentClusterResource("secret", testName, testName, kubectl)
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightResourceQuota(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	quotaAppName := "preflight-resource-quota-quota"
+	appName := "preflight-resource-quota-app"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", appName})
+		kapp.Run([]string{"delete", "-a", quotaAppName})
+	}
+	cleanUp()
+	defer cleanUp()
+
+	quotaYaml := `
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: preflight-resource-quota
+spec:
+  hard:
+    pods: "1"
+    requests.cpu: 100m
+`
+
+	podYaml := `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: preflight-resource-quota-__name__
+spec:
+  containers:
+  - name: app
+    image: busybox
+    command: ["sh", "-c", "sleep 3600"]
+    resources:
+      requests:
+        cpu: 50m
+      limits:
+        cpu: 50m
+`
+
+	logger.Section("deploy resource quota", func() {
+		kapp.RunWithOpts([]string{"deploy", "-a", quotaAppName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(quotaYaml)})
+	})
+
+	logger.Section("deploy pods exceeding quota, preflight check enabled, should error", func() {
+		yaml := strings.ReplaceAll(podYaml, "__name__", "1") + strings.ReplaceAll(podYaml, "__name__", "2")
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "--preflight=ResourceQuota", "-a", appName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml), AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "running preflight check \"ResourceQuota\": validation for resource quotas failed")
+		require.Contains(t, err.Error(), "resource quota 'preflight-resource-quota' would be exceeded for 'pods' (requested: 2, used: 0, limited: 1)")
+
+		out := kubectl.Run([]string{"get", "pods", "-o", "name"})
+		require.NotContains(t, out, "preflight-resource-quota-")
+	})
+
+	logger.Section("deploy pods within quota, preflight check enabled, should succeed", func() {
+		kapp.RunWithOpts([]string{"deploy", "--preflight=ResourceQuota", "-a", appName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(podYaml, "__name__", "1"))})
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+)
+
+func TestWaitIgnoreKind(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	crdYaml := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: waitignores.example.com
+spec:
+  group: example.com
+  names:
+    kind: WaitIgnore
+    listKind: WaitIgnoreList
+    plural: waitignores
+    singular: waitignore
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true
+`
+
+	crYaml := `
+apiVersion: example.com/v1alpha1
+kind: WaitIgnore
+metadata:
+  name: cr
+status:
+  conditions:
+  - type: Ready
+    status: "False"
+`
+
+	crdName := "test-wait-ignore-kind-crd"
+	name := "test-wait-ignore-kind"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kapp.Run([]string{"delete", "-a", crdName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy crd", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", crdName},
+			RunOpts{StdinReader: strings.NewReader(crdYaml)})
+	})
+
+	logger.Section("deploy custom resource without waiting for its kind", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait-ignore-kind", "WaitIgnore", "--json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(crYaml)})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		expected := []map[string]string{{
+			"kind":            "WaitIgnore",
+			"name":            "cr",
+			"namespace":       env.Namespace,
+			"op":              "create",
+			"op_strategy":     "",
+			"reconcile_info":  "",
+			"reconcile_state": "",
+			"wait_to":         "",
+		}}
+
+		validateChanges(t, resp.Tables, expected, "Op:      1 create, 0 delete, 0 update, 0 noop, 0 exists",
+			"Wait to: 0 reconcile, 0 delete, 1 noop", out)
+	})
+}